@@ -0,0 +1,307 @@
+// Package apicompat classifies exported API changes between two
+// go-fsck models as breaking or compatible.
+package apicompat
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+
+	"github.com/titpetric/exp/cmd/go-fsck/diff"
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// Level is the compatibility level of a change.
+type Level string
+
+const (
+	Breaking   Level = "breaking"
+	Compatible Level = "compatible"
+)
+
+// Change holds an exported API change.
+type Change struct {
+	Level  Level
+	Kind   diff.ChangeKind
+	Symbol string
+	Reason string
+
+	File string `json:",omitempty"`
+	Line int    `json:",omitempty"`
+}
+
+// String formats the change as a single line.
+func (c *Change) String() string {
+	return string(c.Level) + ": " + c.Symbol + ": " + c.Reason
+}
+
+// Result holds the classified API changes.
+type Result struct {
+	Changes []*Change
+}
+
+// Breaking returns the breaking changes.
+func (r *Result) Breaking() []*Change {
+	var result []*Change
+	for _, change := range r.Changes {
+		if change.Level == Breaking {
+			result = append(result, change)
+		}
+	}
+	return result
+}
+
+// Check compares the exported API of two models. Test packages, test
+// files, internal packages and main packages are not part of the API.
+func Check(oldDefs, newDefs []*model.Definition) *Result {
+	compared := diff.Compare(public(oldDefs), public(newDefs))
+
+	result := &Result{}
+	add := func(change *diff.Change, level Level, reason string) {
+		result.Changes = append(result.Changes, &Change{
+			Level:  level,
+			Kind:   change.Kind,
+			Symbol: change.Name(),
+			Reason: reason,
+			File:   change.File,
+			Line:   change.Line,
+		})
+	}
+
+	// declarations of added or removed packages aren't listed
+	packages := map[string]bool{}
+
+	for _, change := range compared.Changes {
+		if change.IsPackage() {
+			switch change.Kind {
+			case diff.Added:
+				add(change, Compatible, "package added")
+				packages[change.ImportPath] = true
+			case diff.Removed:
+				add(change, Breaking, "package removed")
+				packages[change.ImportPath] = true
+			}
+			continue
+		}
+		if packages[change.ImportPath] {
+			continue
+		}
+
+		if !change.Exported && (change.Old == nil || !change.Old.IsExported()) {
+			continue
+		}
+
+		switch change.Kind {
+		case diff.Added:
+			add(change, Compatible, string(change.DeclKind)+" added")
+		case diff.Removed:
+			add(change, Breaking, string(change.DeclKind)+" removed")
+		case diff.Changed:
+			if !change.New.IsExported() {
+				add(change, Breaking, "no longer exported")
+				continue
+			}
+			for _, c := range declarationChanges(change.Old, change.New) {
+				add(change, c.level, c.reason)
+			}
+		}
+	}
+
+	return result
+}
+
+type reason struct {
+	level  Level
+	reason string
+}
+
+func declarationChanges(oldDecl, newDecl *model.Declaration) []reason {
+	var result []reason
+
+	if oldDecl.Kind != newDecl.Kind {
+		return append(result, reason{Breaking, "changed from " + string(oldDecl.Kind) + " to " + string(newDecl.Kind)})
+	}
+
+	if oldParams, newParams := oldDecl.TypeParams.String(), newDecl.TypeParams.String(); oldParams != newParams {
+		result = append(result, reason{Breaking, "type parameters changed from `" + oldParams + "` to `" + newParams + "`"})
+	}
+
+	if oldDecl.Kind == model.FuncKind {
+		if oldSig, newSig := signature(oldDecl.Signature), signature(newDecl.Signature); oldSig != newSig {
+			result = append(result, reason{Breaking, "signature changed from `" + oldSig + "` to `" + newSig + "`"})
+		}
+		oldPtr, newPtr := strings.HasPrefix(oldDecl.Receiver, "*"), strings.HasPrefix(newDecl.Receiver, "*")
+		switch {
+		case !oldPtr && newPtr:
+			// T no longer has the method in its method set
+			result = append(result, reason{Breaking, "receiver changed to pointer"})
+		case oldPtr && !newPtr:
+			result = append(result, reason{Compatible, "receiver changed to value"})
+		}
+		return result
+	}
+
+	if oldDecl.Type != newDecl.Type {
+		result = append(result, reason{Breaking, "type changed from `" + oldDecl.Type + "` to `" + newDecl.Type + "`"})
+		return result
+	}
+
+	if oldDecl.Type == "interface" {
+		return append(result, interfaceChanges(oldDecl.Fields, newDecl.Fields)...)
+	}
+	return append(result, structChanges(oldDecl.Fields, newDecl.Fields)...)
+}
+
+// interfaceChanges reports method set changes. Adding a method breaks
+// implementations, unless the interface is sealed. Removing or changing
+// a method breaks callers.
+func interfaceChanges(oldFields, newFields model.FieldList) []reason {
+	oldMethods, newMethods := methodSet(oldFields), methodSet(newFields)
+
+	added := Breaking
+	if isSealed(oldFields) {
+		added = Compatible
+	}
+
+	var result []reason
+	for _, name := range internal.SortedKeys(oldMethods, newMethods) {
+		oldSig, inOld := oldMethods[name]
+		newSig, inNew := newMethods[name]
+
+		switch {
+		case !inOld:
+			result = append(result, reason{added, "method " + name + " added to interface"})
+		case !inNew:
+			result = append(result, reason{Breaking, "method " + name + " removed from interface"})
+		case oldSig != newSig:
+			result = append(result, reason{Breaking, "method " + name + " changed from `" + oldSig + "` to `" + newSig + "`"})
+		}
+	}
+	return result
+}
+
+// structChanges reports exported field changes. Adding fields and
+// changing tags is compatible.
+func structChanges(oldFields, newFields model.FieldList) []reason {
+	oldIndex, newIndex := fieldSet(oldFields), fieldSet(newFields)
+
+	var result []reason
+	for _, name := range internal.SortedKeys(oldIndex, newIndex) {
+		oldField, inOld := oldIndex[name]
+		newField, inNew := newIndex[name]
+
+		switch {
+		case !inOld:
+			result = append(result, reason{Compatible, "field " + name + " added"})
+		case !inNew:
+			result = append(result, reason{Breaking, "field " + name + " removed"})
+		case oldField.Type != newField.Type:
+			result = append(result, reason{Breaking, "field " + name + " type changed from `" + oldField.Type + "` to `" + newField.Type + "`"})
+		case oldField.Tag != newField.Tag:
+			result = append(result, reason{Compatible, "field " + name + " tag changed"})
+		}
+	}
+	return result
+}
+
+// isSealed returns true if the interface declares an unexported method.
+// A sealed interface can only be implemented in its own package.
+func isSealed(fields model.FieldList) bool {
+	for _, field := range fields {
+		if field.Embed == "" && !ast.IsExported(field.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func methodSet(fields model.FieldList) map[string]string {
+	result := map[string]string{}
+	for _, field := range fields {
+		if field.Embed != "" {
+			result[field.Embed] = field.Embed
+			continue
+		}
+		result[field.Name] = signature(field.Type)
+	}
+	return result
+}
+
+func fieldSet(fields model.FieldList) map[string]*model.Field {
+	result := map[string]*model.Field{}
+	for _, field := range fields {
+		name := field.Path
+		if name == "" {
+			name = field.Name
+		}
+		if !ast.IsExported(field.Name) {
+			continue
+		}
+		result[name] = field
+	}
+	return result
+}
+
+// signature normalizes a signature in the form of `Name (a, b int) error`
+// to `func(int, int) error`, so renaming parameters isn't a change.
+func signature(sig string) string {
+	idx := strings.Index(sig, " (")
+	if idx == -1 {
+		return sig
+	}
+
+	expr, err := parser.ParseExpr("func" + sig[idx:])
+	if err != nil {
+		return sig
+	}
+
+	fn, ok := expr.(*ast.FuncType)
+	if !ok {
+		return sig
+	}
+
+	stripNames(fn.Params)
+	stripNames(fn.Results)
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), fn); err != nil {
+		return sig
+	}
+	return buf.String()
+}
+
+func stripNames(list *ast.FieldList) {
+	if list == nil {
+		return
+	}
+	fields := make([]*ast.Field, 0, len(list.List))
+	for _, field := range list.List {
+		for i := 0; i < max(len(field.Names), 1); i++ {
+			fields = append(fields, &ast.Field{Type: field.Type})
+		}
+	}
+	list.List = fields
+}
+
+// public returns the definitions that are part of the exported API.
+// Declarations from test files are removed from the definitions.
+func public(defs []*model.Definition) []*model.Definition {
+	result := make([]*model.Definition, 0, len(defs))
+	for _, def := range defs {
+		if def.TestPackage || def.Package.Package == "main" || strings.HasSuffix(def.ImportPath, "_test") || isInternal(def.ImportPath) {
+			continue
+		}
+
+		def.ClearTestFiles()
+		result = append(result, def)
+	}
+	return result
+}
+
+func isInternal(importPath string) bool {
+	return strings.HasSuffix(importPath, "/internal") || strings.Contains(importPath, "/internal/") || strings.HasPrefix(importPath, "internal/")
+}
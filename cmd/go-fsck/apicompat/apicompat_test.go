@@ -0,0 +1,148 @@
+package apicompat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func testDefinitions() []*model.Definition {
+	return []*model.Definition{
+		{
+			Package: model.Package{Package: "store", ImportPath: "github.com/x/store"},
+			Types: model.DeclarationList{
+				{
+					Kind: model.TypeKind, Name: "Item", File: "store.go",
+					Fields: model.FieldList{
+						{Name: "ID", Path: "Item.ID", Type: "string", Tag: `json:"id"`},
+						{Name: "Name", Path: "Item.Name", Type: "string"},
+						{Name: "size", Path: "Item.size", Type: "int"},
+					},
+				},
+				{
+					Kind: model.TypeKind, Name: "Reader", Type: "interface", File: "store.go",
+					Fields: model.FieldList{
+						{Name: "Get", Type: "Get (id string) (*Item, error)"},
+					},
+				},
+			},
+			Funcs: model.DeclarationList{
+				{Kind: model.FuncKind, Name: "Get", File: "store.go", Signature: "Get (id string) (*Item, error)"},
+				{Kind: model.FuncKind, Name: "Delete", File: "store.go", Signature: "Delete (id string) error"},
+				{Kind: model.FuncKind, Name: "Size", Receiver: "Item", File: "store.go", Signature: "Size () int"},
+				{Kind: model.FuncKind, Name: "get", File: "store.go", Signature: "get (id string) *Item"},
+				{Kind: model.FuncKind, Name: "TestGet", File: "store_test.go", Signature: "TestGet (t *testing.T)"},
+			},
+		},
+		{
+			Package: model.Package{Package: "cache", ImportPath: "github.com/x/internal/cache"},
+			Funcs: model.DeclarationList{
+				{Kind: model.FuncKind, Name: "New", File: "cache.go", Signature: "New () *Cache"},
+			},
+		},
+	}
+}
+
+func reasons(result *Result) map[string]Level {
+	found := map[string]Level{}
+	for _, change := range result.Changes {
+		found[change.Symbol+": "+change.Reason] = change.Level
+	}
+	return found
+}
+
+func TestCheck(t *testing.T) {
+	oldDefs, newDefs := testDefinitions(), testDefinitions()
+
+	store := newDefs[0]
+	store.Types[0].Fields = model.FieldList{
+		{Name: "ID", Path: "Item.ID", Type: "string", Tag: `json:"id,omitempty"`},
+		{Name: "Name", Path: "Item.Name", Type: "[]byte"},
+		{Name: "Size", Path: "Item.Size", Type: "int"},
+	}
+	store.Types[1].Fields = append(store.Types[1].Fields, &model.Field{Name: "List", Type: "List () ([]*Item, error)"})
+	store.Funcs = model.DeclarationList{
+		// renamed parameter
+		{Kind: model.FuncKind, Name: "Get", File: "store.go", Signature: "Get (key string) (*Item, error)"},
+		{Kind: model.FuncKind, Name: "Set", File: "store.go", Signature: "Set (item *Item) error"},
+		{Kind: model.FuncKind, Name: "Size", Receiver: "*Item", File: "store.go", Signature: "Size () int"},
+		{Kind: model.FuncKind, Name: "get", File: "store.go", Signature: "get (ctx context.Context, id string) *Item"},
+	}
+	newDefs[1].Funcs[0].Signature = "New (size int) *Cache"
+
+	result := Check(oldDefs, newDefs)
+
+	assert.Equal(t, map[string]Level{
+		"github.com/x/store.Delete: func removed":                                         Breaking,
+		"github.com/x/store.Set: func added":                                              Compatible,
+		"github.com/x/store.Item.Size: receiver changed to pointer":                       Breaking,
+		"github.com/x/store.Item: field Item.ID tag changed":                              Compatible,
+		"github.com/x/store.Item: field Item.Name type changed from `string` to `[]byte`": Breaking,
+		"github.com/x/store.Item: field Item.Size added":                                  Compatible,
+		"github.com/x/store.Reader: method List added to interface":                       Breaking,
+	}, reasons(result))
+
+	assert.Len(t, result.Breaking(), 4)
+}
+
+func TestCheck_signature(t *testing.T) {
+	oldDefs, newDefs := testDefinitions(), testDefinitions()
+	newDefs[0].Funcs[0].Signature = "Get (ctx context.Context, id string) (*Item, error)"
+	newDefs[0].Types[1].Fields[0].Type = "Get (id int) (*Item, error)"
+
+	result := Check(oldDefs, newDefs)
+	require.Len(t, result.Changes, 2)
+
+	assert.Equal(t, map[string]Level{
+		"github.com/x/store.Get: signature changed from `func(string) (*Item, error)` to `func(context.Context, string) (*Item, error)`": Breaking,
+		"github.com/x/store.Reader: method Get changed from `func(string) (*Item, error)` to `func(int) (*Item, error)`":                 Breaking,
+	}, reasons(result))
+}
+
+func TestCheck_sealedInterface(t *testing.T) {
+	oldDefs, newDefs := testDefinitions(), testDefinitions()
+	sealed := &model.Field{Name: "sealed", Type: "sealed ()"}
+	oldDefs[0].Types[1].Fields = append(oldDefs[0].Types[1].Fields, sealed)
+	newDefs[0].Types[1].Fields = append(newDefs[0].Types[1].Fields, sealed, &model.Field{Name: "List", Type: "List () ([]*Item, error)"})
+
+	result := Check(oldDefs, newDefs)
+	assert.Equal(t, map[string]Level{
+		"github.com/x/store.Reader: method List added to interface": Compatible,
+	}, reasons(result))
+
+	// Sealing an interface breaks the implementations.
+	result = Check(testDefinitions(), oldDefs)
+	assert.Equal(t, map[string]Level{
+		"github.com/x/store.Reader: method sealed added to interface": Breaking,
+	}, reasons(result))
+}
+
+func TestCheck_typeParams(t *testing.T) {
+	oldDefs, newDefs := testDefinitions(), testDefinitions()
+	oldDefs[0].Types[0].TypeParams = model.TypeParamList{{Name: "T", Constraint: "any"}}
+	newDefs[0].Types[0].TypeParams = model.TypeParamList{{Name: "T", Constraint: "comparable"}}
+
+	result := Check(oldDefs, newDefs)
+	assert.Equal(t, map[string]Level{
+		"github.com/x/store.Item: type parameters changed from `[T any]` to `[T comparable]`": Breaking,
+	}, reasons(result))
+}
+
+func TestCheck_packages(t *testing.T) {
+	result := Check(testDefinitions(), testDefinitions()[1:])
+	require.Len(t, result.Changes, 1)
+	assert.Equal(t, Breaking, result.Changes[0].Level)
+	assert.Equal(t, "package removed", result.Changes[0].Reason)
+
+	assert.Empty(t, Check(testDefinitions(), testDefinitions()).Changes)
+}
+
+func TestSignature(t *testing.T) {
+	assert.Equal(t, "func(int, int) error", signature("Add (a, b int) error"))
+	assert.Equal(t, "func(...string) (int, error)", signature("Join (parts ...string) (n int, err error)"))
+	assert.Equal(t, "func()", signature("Close ()"))
+	assert.Equal(t, "invalid", signature("invalid"))
+}
@@ -0,0 +1,83 @@
+package apicompat
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
+)
+
+// extractRef extracts the model of the current folder at a git ref.
+// The ref is checked out into a temporary worktree.
+func extractRef(ref string, verbose bool) ([]*model.Definition, error) {
+	prefix, err := git(".", "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "go-fsck-apicompat-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	worktree := filepath.Join(tmpDir, "src")
+	if _, err := git(".", "worktree", "add", "--detach", worktree, ref); err != nil {
+		return nil, err
+	}
+	defer git(".", "worktree", "remove", "--force", worktree)
+
+	return extractDir(filepath.Join(worktree, prefix), filepath.Join(tmpDir, "go-fsck.json"), verbose)
+}
+
+// extractDir runs `go-fsck extract ./...` in dir, writing the model to
+// outputFile. A separate process is used, as extract changes the working
+// directory and parses the command line flags.
+func extractDir(dir string, outputFile string, verbose bool) ([]*model.Definition, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(self, "extract", "-o", outputFile, "./...")
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Extracting model in %s\n", dir)
+	}
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error extracting model in %s: %w", dir, err)
+	}
+
+	return loader.ReadFile(outputFile)
+}
+
+// extractWorkingTree extracts the model of the current folder.
+func extractWorkingTree(verbose bool) ([]*model.Definition, error) {
+	tmpDir, err := os.MkdirTemp("", "go-fsck-apicompat-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	return extractDir(".", filepath.Join(tmpDir, "go-fsck.json"), verbose)
+}
+
+func git(dir string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,49 @@
+package apicompat
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+)
+
+type options struct {
+	base    string
+	oldFile string
+	newFile string
+
+	json    bool
+	verbose bool
+	args    []string
+
+	fs *internal.FlagSet
+}
+
+// NewOptions parses command-line flags and returns the apicompat options.
+func NewOptions() *options {
+	cfg := &options{}
+
+	cfg.fs = internal.NewFlagSet("apicompat")
+	cfg.fs.StringVar(&cfg.base, "base", cfg.base, "git ref to compare against (default: compare two model files)")
+	cfg.fs.BoolVar(&cfg.json, "json", cfg.json, "print results as json")
+	cfg.fs.BoolVarP(&cfg.verbose, "verbose", "v", cfg.verbose, "verbose output")
+
+	cfg.args = internal.ParseArgs(cfg.fs)
+
+	switch {
+	case cfg.base == "" && len(cfg.args) == 2:
+		cfg.oldFile, cfg.newFile = cfg.args[0], cfg.args[1]
+	case cfg.base != "" && len(cfg.args) == 1:
+		cfg.newFile = cfg.args[0]
+	}
+
+	return cfg
+}
+
+// PrintHelp displays usage information for the apicompat command.
+func (o *options) PrintHelp() {
+	fmt.Printf("Usage: %s apicompat <options> old.json new.json\n", path.Base(os.Args[0]))
+	fmt.Printf("       %s apicompat --base <ref> [new.json]\n\n", path.Base(os.Args[0]))
+	o.fs.PrintDefaults()
+}
@@ -0,0 +1,78 @@
+package apicompat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fbiville/markdown-table-formatter/pkg/markdown"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
+)
+
+func getDefinitions(cfg *options) (oldDefs, newDefs []*model.Definition, err error) {
+	switch {
+	case cfg.oldFile != "":
+		oldDefs, err = loader.ReadFile(cfg.oldFile)
+	case cfg.base != "":
+		oldDefs, err = extractRef(cfg.base, cfg.verbose)
+	default:
+		return nil, nil, errors.New("apicompat requires --base <ref> or two arguments: old.json new.json")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cfg.newFile != "" {
+		newDefs, err = loader.ReadFile(cfg.newFile)
+	} else {
+		newDefs, err = extractWorkingTree(cfg.verbose)
+	}
+	return oldDefs, newDefs, err
+}
+
+func apicompat(cfg *options) error {
+	oldDefs, newDefs, err := getDefinitions(cfg)
+	if err != nil {
+		return err
+	}
+
+	result := Check(oldDefs, newDefs)
+	breaking := len(result.Breaking())
+
+	if cfg.json {
+		b, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	} else if err := printTable(result); err != nil {
+		return err
+	}
+
+	if breaking > 0 {
+		return fmt.Errorf("found %d breaking API changes", breaking)
+	}
+	return nil
+}
+
+func printTable(result *Result) error {
+	if len(result.Changes) == 0 {
+		fmt.Println("No API changes.")
+		return nil
+	}
+
+	table := [][]string{}
+	for _, change := range result.Changes {
+		table = append(table, []string{string(change.Level), "`" + change.Symbol + "`", change.Reason})
+	}
+
+	t, err := markdown.NewTableFormatterBuilder().WithPrettyPrint().Build("Level", "Symbol", "Change").Format(table)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(t)
+	return nil
+}
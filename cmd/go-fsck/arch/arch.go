@@ -0,0 +1,113 @@
+package arch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fbiville/markdown-table-formatter/pkg/markdown"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
+)
+
+func getDefinitions(cfg *options) ([]*model.Definition, error) {
+	// Read the exported go-fsck.json data, or load the
+	// packages if it doesn't exist.
+	defs, err := loader.ReadFile(cfg.inputFile)
+	if err == nil {
+		return defs, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading %s: %w", cfg.inputFile, err)
+	}
+
+	// list current local packages
+	packages, err := internal.ListPackages(".", "./...")
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := loader.LoadAll(context.Background(), packages, &loader.Options{
+		IncludeTests: cfg.tests,
+		Verbose:      cfg.verbose,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defs = []*model.Definition{}
+	for _, d := range results {
+		defs = append(defs, d...)
+	}
+
+	return defs, nil
+}
+
+func arch(cfg *options) error {
+	config, err := LoadConfig(cfg.configFile)
+	if err != nil {
+		return err
+	}
+
+	defs, err := getDefinitions(cfg)
+	if err != nil {
+		return err
+	}
+
+	result := Check(&config.Arch, defs, cfg.tests)
+
+	if cfg.json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	} else if err := printReport(result); err != nil {
+		return err
+	}
+
+	if result.Failed() {
+		return fmt.Errorf("found %d layer violations and %d layer cycles", len(result.Violations), len(result.Cycles))
+	}
+	return nil
+}
+
+func printReport(result *Result) error {
+	if !result.Failed() {
+		fmt.Println("No layer violations.")
+		return nil
+	}
+
+	if len(result.Violations) > 0 {
+		rows := [][]string{}
+		for _, v := range result.Violations {
+			from := v.From
+			if from == "" {
+				from = "-"
+			}
+			rows = append(rows, []string{v.File, v.Import, from, v.To, v.Rule})
+		}
+
+		table, err := markdown.NewTableFormatterBuilder().WithPrettyPrint().Build("File", "Import", "From", "To", "Rule").Format(rows)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("## Layer violations\n\n%s\n\n", strings.TrimSpace(table))
+	}
+
+	if len(result.Cycles) > 0 {
+		fmt.Printf("## Layer cycles\n\n")
+		for _, cycle := range result.Cycles {
+			fmt.Printf("- %s\n", cycle)
+			for _, imported := range cycle.Imports {
+				fmt.Printf("  - %s\n", imported)
+			}
+		}
+		fmt.Println()
+	}
+	return nil
+}
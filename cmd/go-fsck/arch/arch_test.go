@@ -0,0 +1,137 @@
+package arch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+const testConfig = `
+lint:
+  rules: [godoc]
+arch:
+  layers:
+    - name: domain
+      packages: [./domain/...]
+      deny: [transport]
+    - name: transport
+      packages: [./transport/...]
+      allow: [service, domain]
+    - name: service
+      packages: [./service/...]
+    - name: storage
+      packages: [./internal/storage/...]
+      allow-from: [service]
+    - name: sql
+      packages: [database/sql]
+      allow-from: [storage]
+`
+
+func testDefinitions() []*model.Definition {
+	return []*model.Definition{
+		{
+			Package: model.Package{ImportPath: "example.com/app/domain", Path: "./domain"},
+			Imports: model.StringSet{
+				"user.go":      {`"example.com/app/transport/http"`, `"strings"`},
+				"user_test.go": {`"example.com/app/internal/storage"`},
+			},
+		},
+		{
+			Package: model.Package{ImportPath: "example.com/app/transport/http", Path: "./transport/http"},
+			Imports: model.StringSet{
+				"server.go": {`"example.com/app/domain"`, `store "example.com/app/internal/storage"`},
+			},
+		},
+		{
+			Package: model.Package{ImportPath: "example.com/app/service", Path: "./service"},
+			Imports: model.StringSet{
+				"service.go": {`"example.com/app/internal/storage"`, `"example.com/app/transport/http"`},
+			},
+		},
+		{
+			Package: model.Package{ImportPath: "example.com/app/internal/storage", Path: "./internal/storage"},
+			Imports: model.StringSet{
+				"storage.go": {`"database/sql"`, `"example.com/app/service"`},
+			},
+		},
+		{
+			Package: model.Package{ImportPath: "example.com/app", Path: "."},
+			Imports: model.StringSet{
+				"main.go": {`_ "database/sql"`},
+			},
+		},
+	}
+}
+
+func loadTestConfig(t *testing.T, contents string) (*Config, error) {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), ".go-fsck.yml")
+	require.NoError(t, os.WriteFile(filename, []byte(contents), 0o644))
+	return LoadConfig(filename)
+}
+
+func TestLoadConfig(t *testing.T) {
+	config, err := loadTestConfig(t, testConfig)
+	require.NoError(t, err)
+	require.Len(t, config.Arch.Layers, 5)
+	assert.Equal(t, []string{"service"}, config.Arch.Layers[3].AllowFrom)
+
+	assert.Equal(t, "storage", config.Arch.Layer("example.com/app/internal/storage", "internal/storage").Name)
+	assert.Equal(t, "transport", config.Arch.Layer("example.com/app/transport/http", "transport/http").Name)
+	assert.Equal(t, "sql", config.Arch.Layer("database/sql", "").Name)
+	assert.Nil(t, config.Arch.Layer("example.com/app", "."))
+
+	_, err = loadTestConfig(t, "arch:\n  layers:\n    - name: a\n      packages: [a]\n      deny: [b]\n")
+	assert.ErrorContains(t, err, `arch layer "a" references unknown layer "b"`)
+
+	_, err = loadTestConfig(t, "lint:\n  rules: [godoc]\n")
+	assert.ErrorContains(t, err, "no arch layers defined")
+}
+
+func TestGetDefinitions_invalid(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "go-fsck.json")
+	require.NoError(t, os.WriteFile(inputFile, []byte("{"), 0o644))
+
+	_, err := getDefinitions(&options{inputFile: inputFile})
+	assert.ErrorContains(t, err, "error reading "+inputFile)
+}
+
+func TestCheck(t *testing.T) {
+	config, err := loadTestConfig(t, testConfig)
+	require.NoError(t, err)
+
+	result := Check(&config.Arch, testDefinitions(), false)
+
+	type row struct {
+		File, Import, From, To, Rule string
+	}
+	var rows []row
+	for _, v := range result.Violations {
+		rows = append(rows, row{v.File, v.Import, v.From, v.To, v.Rule})
+	}
+	assert.Equal(t, []row{
+		{"domain/user.go", "example.com/app/transport/http", "domain", "transport", "layer domain may not import transport"},
+		{"main.go", "database/sql", "", "sql", "layer sql may only be imported from storage"},
+		{"transport/http/server.go", "example.com/app/internal/storage", "transport", "storage", "layer transport may only import service, domain"},
+	}, rows)
+
+	require.Len(t, result.Cycles, 2)
+	assert.Equal(t, "domain -> transport -> domain", result.Cycles[0].String())
+	assert.Equal(t, []string{
+		"example.com/app/domain imports example.com/app/transport/http",
+		"example.com/app/transport/http imports example.com/app/domain",
+	}, result.Cycles[0].Imports)
+	assert.Equal(t, "service -> storage -> service", result.Cycles[1].String())
+	assert.True(t, result.Failed())
+
+	result = Check(&config.Arch, testDefinitions(), true)
+	require.Len(t, result.Violations, 4)
+	assert.Equal(t, "domain/user_test.go", result.Violations[1].File)
+	assert.Equal(t, "layer storage may only be imported from service", result.Violations[1].Rule)
+}
@@ -0,0 +1,210 @@
+// Package arch checks package imports against architecture layers.
+package arch
+
+import (
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// Violation is an import breaking a layer rule.
+type Violation struct {
+	File    string
+	Package string
+	Import  string
+
+	From string
+	To   string
+	Rule string
+}
+
+// Cycle is an import cycle between layers. Imports lists a package
+// import for every step of the cycle.
+type Cycle struct {
+	Layers  []string
+	Imports []string
+}
+
+// String formats the cycle as `a -> b -> a`.
+func (c *Cycle) String() string {
+	return strings.Join(c.Layers, " -> ")
+}
+
+// Result holds the layer violations and cycles.
+type Result struct {
+	Violations []*Violation
+	Cycles     []*Cycle
+}
+
+// Failed returns true if any violations or cycles are found.
+func (r *Result) Failed() bool {
+	return len(r.Violations) > 0 || len(r.Cycles) > 0
+}
+
+// Check checks the per-file imports of the definitions against the
+// layers. Imports of test files are skipped unless tests is set.
+func Check(config *ArchConfig, defs []*model.Definition, tests bool) *Result {
+	// folders maps model import paths to the folder relative to the
+	// module, packages outside of the model have no folder.
+	folders := map[string]string{}
+	for _, def := range defs {
+		folders[def.Package.ImportPath] = folder(def.Package.Path)
+	}
+	layerOf := func(importPath string) *Layer {
+		return config.Layer(importPath, folders[importPath])
+	}
+
+	result := &Result{}
+	graph := layerGraph{}
+	seen := map[string]bool{}
+
+	for _, def := range defs {
+		from := layerOf(def.Package.ImportPath)
+
+		for _, filename := range def.Imports.Keys() {
+			if !tests && strings.HasSuffix(filename, "_test.go") {
+				continue
+			}
+
+			file := path.Join(folders[def.Package.ImportPath], filename)
+			for _, literal := range def.Imports.Get(filename) {
+				fields := strings.Fields(literal)
+				importPath := strings.Trim(fields[len(fields)-1], `"`)
+
+				key := file + ":" + importPath
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				to := layerOf(importPath)
+				if to == nil || to == from {
+					continue
+				}
+				if from != nil {
+					graph.add(from.Name, to.Name, def.Package.ImportPath+" imports "+importPath)
+				}
+
+				if rule := checkImport(from, to); rule != "" {
+					v := &Violation{
+						File:    file,
+						Package: def.Package.ImportPath,
+						Import:  importPath,
+						To:      to.Name,
+						Rule:    rule,
+					}
+					if from != nil {
+						v.From = from.Name
+					}
+					result.Violations = append(result.Violations, v)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(result.Violations, func(i, j int) bool {
+		a, b := result.Violations[i], result.Violations[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Import < b.Import
+	})
+
+	result.Cycles = graph.cycles(config.Layers)
+	return result
+}
+
+// checkImport returns the rule broken by a layer importing another
+// layer, or an empty string. A nil from is a package outside of layers.
+func checkImport(from, to *Layer) string {
+	if from != nil {
+		if slices.Contains(from.Deny, to.Name) {
+			return "layer " + from.Name + " may not import " + to.Name
+		}
+		if len(from.Allow) > 0 && !slices.Contains(from.Allow, to.Name) {
+			return "layer " + from.Name + " may only import " + strings.Join(from.Allow, ", ")
+		}
+	}
+	if len(to.AllowFrom) > 0 && (from == nil || !slices.Contains(to.AllowFrom, from.Name)) {
+		return "layer " + to.Name + " may only be imported from " + strings.Join(to.AllowFrom, ", ")
+	}
+	return ""
+}
+
+// layerGraph maps layer names to the imported layers, with the first
+// package import seen for each edge.
+type layerGraph map[string]map[string]string
+
+func (g layerGraph) add(from, to, example string) {
+	if g[from] == nil {
+		g[from] = map[string]string{}
+	}
+	if _, ok := g[from][to]; !ok {
+		g[from][to] = example
+	}
+}
+
+// cycles returns a cycle for every group of layers importing each
+// other, found from the first layer of the group in config order.
+func (g layerGraph) cycles(layers []*Layer) []*Cycle {
+	var (
+		result  []*Cycle
+		covered = map[string]bool{}
+	)
+	for _, layer := range layers {
+		if covered[layer.Name] {
+			continue
+		}
+		cycle := g.cycle(layer.Name)
+		if cycle == nil {
+			continue
+		}
+		for _, name := range cycle.Layers {
+			covered[name] = true
+		}
+		result = append(result, cycle)
+	}
+	return result
+}
+
+// cycle returns the shortest cycle starting and ending at start,
+// or nil if there is none.
+func (g layerGraph) cycle(start string) *Cycle {
+	parent := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, next := range internal.SortedKeys(g[node]) {
+			if next == start {
+				path := []string{start}
+				for n := node; n != start; n = parent[n] {
+					path = append([]string{n}, path...)
+				}
+				path = append([]string{start}, path...)
+
+				cycle := &Cycle{Layers: path}
+				for i := 0; i < len(path)-1; i++ {
+					cycle.Imports = append(cycle.Imports, g[path[i]][path[i+1]])
+				}
+				return cycle
+			}
+			if _, ok := parent[next]; ok {
+				continue
+			}
+			parent[next] = node
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
+
+// folder returns the package folder relative to the module root.
+func folder(p string) string {
+	return path.Clean(strings.TrimPrefix(p, "./"))
+}
@@ -0,0 +1,109 @@
+package arch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+)
+
+// Config holds the arch configuration from `.go-fsck.yml`.
+type Config struct {
+	Arch ArchConfig `yaml:"arch"`
+}
+
+// ArchConfig holds the architecture layers.
+type ArchConfig struct {
+	Layers []*Layer `yaml:"layers"`
+}
+
+// Layer is a named group of packages and the rules for its imports.
+//
+// Packages are matched against the package folder relative to the
+// module root, and the import path, so stdlib and third party packages
+// can be grouped as well. A trailing `/...` matches everything under a
+// folder. A package belongs to the first layer matching it.
+type Layer struct {
+	Name     string   `yaml:"name"`
+	Packages []string `yaml:"packages"`
+
+	// Deny lists the layers this layer may not import.
+	Deny []string `yaml:"deny,omitempty"`
+
+	// Allow lists the only layers this layer may import.
+	Allow []string `yaml:"allow,omitempty"`
+
+	// AllowFrom lists the only layers which may import this layer.
+	AllowFrom []string `yaml:"allow-from,omitempty"`
+}
+
+// Match returns true if the package matches any of the layer patterns.
+func (l *Layer) Match(importPath, folder string) bool {
+	for _, pattern := range l.Packages {
+		if internal.MatchPath(pattern, importPath) || (folder != "" && internal.MatchPath(pattern, folder)) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadConfig reads the config file and validates the layers.
+func LoadConfig(filename string) (*Config, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if err := yaml.Unmarshal(b, config); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", filename, err)
+	}
+	if err := config.Arch.validate(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", filename, err)
+	}
+	return config, nil
+}
+
+func (c *ArchConfig) validate() error {
+	if len(c.Layers) == 0 {
+		return errors.New("no arch layers defined")
+	}
+
+	names := map[string]bool{}
+	for _, layer := range c.Layers {
+		if layer.Name == "" {
+			return errors.New("arch layer without a name")
+		}
+		if names[layer.Name] {
+			return fmt.Errorf("duplicate arch layer %q", layer.Name)
+		}
+		if len(layer.Packages) == 0 {
+			return fmt.Errorf("arch layer %q has no packages", layer.Name)
+		}
+		names[layer.Name] = true
+	}
+
+	for _, layer := range c.Layers {
+		for _, list := range [][]string{layer.Deny, layer.Allow, layer.AllowFrom} {
+			for _, name := range list {
+				if !names[name] {
+					return fmt.Errorf("arch layer %q references unknown layer %q", layer.Name, name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Layer returns the first layer matching the package, or nil.
+func (c *ArchConfig) Layer(importPath, folder string) *Layer {
+	for _, layer := range c.Layers {
+		if layer.Match(importPath, folder) {
+			return layer
+		}
+	}
+	return nil
+}
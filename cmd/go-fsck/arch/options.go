@@ -0,0 +1,45 @@
+package arch
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+)
+
+type options struct {
+	inputFile  string
+	configFile string
+	tests      bool
+
+	json    bool
+	verbose bool
+
+	fs *internal.FlagSet
+}
+
+// NewOptions parses command-line flags and returns the arch options.
+func NewOptions() *options {
+	cfg := &options{
+		inputFile:  "go-fsck.json",
+		configFile: ".go-fsck.yml",
+	}
+
+	cfg.fs = internal.NewFlagSet("arch")
+	cfg.fs.StringVarP(&cfg.inputFile, "input-file", "i", cfg.inputFile, "input file (go-fsck extract ./...)")
+	cfg.fs.StringVarP(&cfg.configFile, "config", "c", cfg.configFile, "config file with the arch layers")
+	cfg.fs.BoolVar(&cfg.tests, "tests", cfg.tests, "check imports of test files")
+	cfg.fs.BoolVar(&cfg.json, "json", cfg.json, "print results as json")
+	cfg.fs.BoolVarP(&cfg.verbose, "verbose", "v", cfg.verbose, "verbose output")
+
+	_ = internal.ParseArgs(cfg.fs)
+
+	return cfg
+}
+
+// PrintHelp displays usage information for the arch command.
+func (o *options) PrintHelp() {
+	fmt.Printf("Usage: %s arch <options>:\n\n", path.Base(os.Args[0]))
+	o.fs.PrintDefaults()
+}
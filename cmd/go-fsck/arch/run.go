@@ -0,0 +1,19 @@
+package arch
+
+import (
+	"os"
+
+	"golang.org/x/exp/slices"
+)
+
+// Run is the entrypoint for `go-fsck arch`.
+func Run() (err error) {
+	cfg := NewOptions()
+
+	if slices.Contains(os.Args, "help") {
+		cfg.PrintHelp()
+		return nil
+	}
+
+	return arch(cfg)
+}
@@ -2,15 +2,16 @@ package coverage
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"os"
 	"sort"
 	"strings"
 	"text/template"
 
 	"github.com/fbiville/markdown-table-formatter/pkg/markdown"
+	"golang.org/x/tools/cover"
 
 	"github.com/titpetric/exp/cmd/go-fsck/internal"
 	"github.com/titpetric/exp/cmd/go-fsck/model"
@@ -30,14 +31,16 @@ func getDefinitions(cfg *options) ([]*model.Definition, error) {
 		return nil, err
 	}
 
-	defs = []*model.Definition{}
-
-	for _, pkg := range packages {
-		d, err := loader.Load(pkg, false, cfg.verbose)
-		if err != nil {
-			return nil, err
-		}
+	results, err := loader.LoadAll(context.Background(), packages, &loader.Options{
+		IncludeTests: false,
+		Verbose:      cfg.verbose,
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	defs = []*model.Definition{}
+	for _, d := range results {
 		defs = append(defs, d...)
 	}
 
@@ -93,86 +96,26 @@ func coverage(cfg *options) error {
 		return err
 	}
 
-	coverinfo, err := loadCoverage(cfg.coverageFile)
-	if err != nil {
-		return err
-	}
-
-	findPackage := func(defs []*model.Definition, name string) *model.Definition {
-		for _, def := range defs {
-			if name == def.Package.ImportPath {
-				return def
-			}
-		}
-		return nil
-	}
-
-	var total, skipped, inits int
-	for _, info := range coverinfo.Functions {
-		p := findPackage(defs, info.Package)
-		if p == nil {
-			log.Printf("Warning, can't find package %s, skipping\n", info.Package)
-			continue
-		}
-
-		// init may show up multiple times in one package
-		if info.Function == "init" {
-			inits++
-			p.InitCount++
-			continue
-		}
-
-		f := p.Funcs.Find(func(d *model.Declaration) bool {
-			if d.Kind != model.FuncKind {
-				return false
-			}
-			return d.Name == info.Function && d.File == info.File && d.Line == info.Line
-		})
-		if f == nil {
-			skipped++
-			continue
-			// return fmt.Errorf("Can't find function by name: %v", info)
+	var result *coverageResult
+	if cfg.coverProfile != "" {
+		profiles, err := cover.ParseProfiles(cfg.coverProfile)
+		if err != nil {
+			return fmt.Errorf("Error reading %s: %w", cfg.coverProfile, err)
 		}
-
-		if f.Complexity != nil {
-			f.Complexity.Coverage = info.Coverage
-		} else {
-			f.Complexity = &model.Complexity{
-				Coverage: info.Coverage,
-			}
+		result = applyProfiles(defs, profiles)
+	} else {
+		coverinfo, err := loadCoverage(cfg.coverageFile)
+		if err != nil {
+			return err
 		}
-		if info.Coverage > 0 {
-			total++
+		result, err = applySummary(defs, coverinfo)
+		if err != nil {
+			return err
 		}
 	}
 
-	var totalPackages int
-	for _, info := range coverinfo.Packages {
-		p := findPackage(defs, info.Package)
-		if p == nil {
-			return fmt.Errorf("Can't find package by name: %s", info.Package)
-		}
-
-		if p.Complexity != nil {
-			p.Complexity.Coverage = info.Coverage
-		} else {
-			p.Complexity = &model.Complexity{
-				Coverage: info.Coverage,
-			}
-		}
-
-		p.Funcs.Walk(func(d *model.Declaration) {
-			if d.Kind != model.FuncKind {
-				return
-			}
-			p.Complexity.Cognitive += d.Complexity.Cognitive
-			p.Complexity.Cyclomatic += d.Complexity.Cyclomatic
-			p.Complexity.Lines += d.Complexity.Lines
-		})
-
-		if info.Coverage > 0 {
-			totalPackages++
-		}
+	if err := printUnmatched(os.Stderr, result.Unmatched); err != nil {
+		return err
 	}
 
 	if cfg.outputFile != "" {
@@ -185,7 +128,7 @@ func coverage(cfg *options) error {
 			return err
 		}
 
-		fmt.Printf("Wrote function coverage %d/%d (skipped %d funcs, %d init()), package coverage %d/%d to %s\n", total, len(coverinfo.Functions), skipped, inits, totalPackages, len(coverinfo.Packages), cfg.outputFile)
+		fmt.Printf("Wrote function coverage %d/%d (skipped %d entries, %d init()), package coverage %d/%d to %s\n", result.FunctionsCovered, result.Functions, len(result.Unmatched), result.Inits, result.PackagesCovered, result.Packages, cfg.outputFile)
 	} else {
 		packages := func(defs []*model.Definition) []model.Package {
 			var result []model.Package
@@ -211,9 +154,12 @@ func coverage(cfg *options) error {
 				return d.Complexity != nil && d.Complexity.Coverage > 0
 			})
 			for _, fn := range fns {
+				if fn.Complexity == nil {
+					fn.Complexity = &model.Complexity{}
+				}
 				info := CoverageInfo{
 					Package:   def.Package.ImportPath,
-					Function:  combined(fn.Receiver, fn.Name),
+					Function:  internal.FuncName(fn.Receiver, fn.Name),
 					Coverage:  fn.Complexity.Coverage,
 					Cognitive: fn.Complexity.Cognitive,
 				}
@@ -310,10 +256,3 @@ func coverage(cfg *options) error {
 
 	return nil
 }
-
-func combined(receiver, name string) string {
-	if receiver != "" {
-		return strings.TrimLeft(receiver, "*") + "." + name
-	}
-	return name
-}
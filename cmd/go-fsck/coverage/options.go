@@ -12,6 +12,7 @@ type options struct {
 	inputFile    string
 	outputFile   string
 	coverageFile string
+	coverProfile string
 
 	template string
 
@@ -27,6 +28,7 @@ func NewOptions() *options {
 	flag.StringVarP(&cfg.inputFile, "input-file", "i", cfg.inputFile, "input file")
 	flag.StringVarP(&cfg.outputFile, "output-file", "o", cfg.outputFile, "output file")
 	flag.StringVarP(&cfg.coverageFile, "coverage-file", "c", cfg.coverageFile, "summary coverage file")
+	flag.StringVarP(&cfg.coverProfile, "coverprofile", "p", cfg.coverProfile, "coverage profile (go test -coverprofile), used instead of --coverage-file")
 
 	flag.StringVar(&cfg.template, "template", cfg.template, "Template for the report")
 
@@ -0,0 +1,195 @@
+package coverage
+
+import (
+	"math"
+	"path"
+	"strings"
+
+	"golang.org/x/tools/cover"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// funcRange is the line range of a function declaration. A model may
+// hold the same declaration more than once, e.g. in the package and in
+// the package compiled with tests, so a range holds all the copies.
+type funcRange struct {
+	decls      []*model.Declaration
+	start, end int
+
+	statements, covered int
+}
+
+// percent returns the coverage percentage, rounded like `go tool cover`.
+func percent(covered, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(covered)/float64(total)*1000) / 10
+}
+
+// applyProfiles computes statement coverage from coverprofile blocks.
+// Blocks are attributed to the innermost function declaration whose
+// line range contains them, so methods are matched by position and the
+// blocks of closures count towards the enclosing function. Blocks that
+// can't be attributed are returned as unmatched entries.
+func applyProfiles(defs []*model.Definition, profiles []*cover.Profile) *coverageResult {
+	result := &coverageResult{}
+
+	var (
+		// files maps `import/path/file.go` to the function ranges.
+		files    = map[string][]*funcRange{}
+		packages = map[string][]*model.Definition{}
+	)
+
+	for _, def := range defs {
+		importPath := def.Package.ImportPath
+		if !def.TestPackage {
+			packages[importPath] = append(packages[importPath], def)
+		}
+
+		for _, decl := range def.Funcs {
+			if decl.IsTestScope() || decl.Line == 0 {
+				continue
+			}
+			key := importPath + "/" + decl.File
+
+			var existing *funcRange
+			for _, r := range files[key] {
+				if r.start == decl.Line {
+					existing = r
+					break
+				}
+			}
+			if existing != nil {
+				existing.decls = append(existing.decls, decl)
+				continue
+			}
+			files[key] = append(files[key], &funcRange{
+				decls: []*model.Declaration{decl},
+				start: decl.Line,
+				end:   decl.LastLine(),
+			})
+		}
+	}
+
+	type totals struct {
+		statements, covered int
+	}
+	packageTotals := map[string]*totals{}
+
+	for _, profile := range profiles {
+		importPath, file := path.Split(profile.FileName)
+		importPath = strings.TrimSuffix(importPath, "/")
+
+		if _, ok := packages[importPath]; !ok {
+			result.Unmatched = append(result.Unmatched, Unmatched{
+				Package:    importPath,
+				File:       file,
+				Statements: statements(profile.Blocks),
+				Reason:     "package not in model",
+			})
+			continue
+		}
+
+		pkgTotals, ok := packageTotals[importPath]
+		if !ok {
+			pkgTotals = &totals{}
+			packageTotals[importPath] = pkgTotals
+		}
+		for _, block := range profile.Blocks {
+			pkgTotals.statements += block.NumStmt
+			if block.Count > 0 {
+				pkgTotals.covered += block.NumStmt
+			}
+		}
+
+		ranges, ok := files[profile.FileName]
+		if !ok {
+			result.Unmatched = append(result.Unmatched, Unmatched{
+				Package:    importPath,
+				File:       file,
+				Statements: statements(profile.Blocks),
+				Reason:     "file not in model",
+			})
+			continue
+		}
+
+		for _, block := range profile.Blocks {
+			var match *funcRange
+			for _, r := range ranges {
+				if block.StartLine < r.start || block.EndLine > r.end {
+					continue
+				}
+				if match == nil || r.end-r.start < match.end-match.start {
+					match = r
+				}
+			}
+
+			if match == nil {
+				result.Unmatched = append(result.Unmatched, Unmatched{
+					Package:    importPath,
+					File:       file,
+					Line:       block.StartLine,
+					EndLine:    block.EndLine,
+					Statements: block.NumStmt,
+					Reason:     "no function at lines",
+				})
+				continue
+			}
+
+			match.statements += block.NumStmt
+			if block.Count > 0 {
+				match.covered += block.NumStmt
+			}
+		}
+	}
+
+	for _, ranges := range files {
+		for _, r := range ranges {
+			if r.statements == 0 {
+				continue
+			}
+
+			coverage := percent(r.covered, r.statements)
+			for _, decl := range r.decls {
+				if decl.Complexity == nil {
+					decl.Complexity = &model.Complexity{}
+				}
+				decl.Complexity.Coverage = coverage
+			}
+
+			if decl := r.decls[0]; decl.Name == "init" && decl.Receiver == "" {
+				result.Inits++
+				continue
+			}
+
+			result.Functions++
+			if r.covered > 0 {
+				result.FunctionsCovered++
+			}
+		}
+	}
+
+	for importPath, t := range packageTotals {
+		coverage := percent(t.covered, t.statements)
+		for _, def := range packages[importPath] {
+			setPackageCoverage(def, coverage)
+		}
+
+		result.Packages++
+		if t.covered > 0 {
+			result.PackagesCovered++
+		}
+	}
+
+	return result
+}
+
+func statements(blocks []cover.ProfileBlock) int {
+	var result int
+	for _, block := range blocks {
+		result += block.NumStmt
+	}
+	return result
+}
@@ -0,0 +1,174 @@
+package coverage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func TestApplyProfiles(t *testing.T) {
+	get := &model.Declaration{
+		Kind:     model.FuncKind,
+		Name:     "Get",
+		Receiver: "*Store",
+		File:     "store.go",
+		Line:     10,
+		Source:   "func (s *Store) Get() string {\n\tif s == nil {\n\t\treturn \"\"\n\t}\n\treturn s.name\n}",
+	}
+	walk := &model.Declaration{
+		Kind:       model.FuncKind,
+		Name:       "Walk",
+		File:       "store.go",
+		Line:       20,
+		Complexity: &model.Complexity{Cognitive: 2, Lines: 8},
+	}
+	defs := []*model.Definition{
+		{
+			Package: model.Package{ImportPath: "example.com/app/store"},
+			Funcs:   model.DeclarationList{get, walk},
+		},
+	}
+
+	profile := strings.Join([]string{
+		"mode: set",
+		// Get: one covered and one uncovered block
+		"example.com/app/store/store.go:10.31,11.14 1 1",
+		"example.com/app/store/store.go:11.14,13.3 1 0",
+		"example.com/app/store/store.go:14.2,14.15 1 1",
+		// Walk: the closure body counts towards Walk
+		"example.com/app/store/store.go:20.20,21.30 2 1",
+		"example.com/app/store/store.go:21.30,23.4 2 0",
+		// a closure in a package level var
+		"example.com/app/store/store.go:30.20,32.2 1 1",
+		"example.com/app/store/other.go:3.10,5.2 2 1",
+		"example.com/app/other/other.go:3.10,5.2 4 1",
+	}, "\n")
+
+	profiles, err := cover.ParseProfilesFromReader(strings.NewReader(profile))
+	require.NoError(t, err)
+
+	result := applyProfiles(defs, profiles)
+
+	assert.Equal(t, 66.7, get.Complexity.Coverage)
+	assert.Equal(t, 50.0, walk.Complexity.Coverage)
+	assert.Equal(t, 2, walk.Complexity.Cognitive)
+
+	assert.Equal(t, 2, result.Functions)
+	assert.Equal(t, 2, result.FunctionsCovered)
+	assert.Equal(t, 1, result.Packages)
+
+	// 7 of 10 statements in the package are covered
+	require.NotNil(t, defs[0].Complexity)
+	assert.Equal(t, 70.0, defs[0].Complexity.Coverage)
+	assert.Equal(t, 2, defs[0].Complexity.Cognitive)
+
+	require.Len(t, result.Unmatched, 3)
+	assert.Equal(t, Unmatched{Package: "example.com/app/other", File: "other.go", Statements: 4, Reason: "package not in model"}, result.Unmatched[0])
+	assert.Equal(t, Unmatched{Package: "example.com/app/store", File: "other.go", Statements: 2, Reason: "file not in model"}, result.Unmatched[1])
+	assert.Equal(t, Unmatched{Package: "example.com/app/store", File: "store.go", Line: 30, EndLine: 32, Statements: 1, Reason: "no function at lines"}, result.Unmatched[2])
+
+	var buf bytes.Buffer
+	require.NoError(t, printUnmatched(&buf, result.Unmatched))
+	assert.Contains(t, buf.String(), "Unmatched coverage entries (3):")
+	assert.Contains(t, buf.String(), "store.go:30-32")
+}
+
+func TestApplySummary_unmatched(t *testing.T) {
+	defs := []*model.Definition{
+		{
+			Package: model.Package{ImportPath: "example.com/app/store"},
+			Funcs: model.DeclarationList{
+				{Kind: model.FuncKind, Name: "Get", File: "store.go", Line: 10},
+			},
+		},
+	}
+
+	result, err := applySummary(defs, &Coverage{
+		Functions: []CoverageInfo{
+			{Package: "example.com/app/store", Function: "Get", File: "store.go", Line: 10, Coverage: 50},
+			{Package: "example.com/app/store", Function: "Put", File: "store.go", Line: 20, Coverage: 10},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 50.0, defs[0].Funcs[0].Complexity.Coverage)
+	assert.Equal(t, 1, result.FunctionsCovered)
+	require.Len(t, result.Unmatched, 1)
+	assert.Equal(t, "Put", result.Unmatched[0].Function)
+	assert.Equal(t, "function not found", result.Unmatched[0].Reason)
+}
+
+func TestApplyProfiles_docComments(t *testing.T) {
+	// Open is declared at lines 5-10, with the doc comment at lines 2-4.
+	// Close is declared at lines 12-21, with the doc comment at line 11.
+	// The source of both funcs includes the doc comment.
+	open := func() *model.Declaration {
+		return &model.Declaration{
+			Kind:   model.FuncKind,
+			Name:   "Open",
+			File:   "store.go",
+			Line:   5,
+			Doc:    "// Open opens the store.\n//\n// It returns an error.",
+			Source: "// Open opens the store.\n//\n// It returns an error.\nfunc Open() error {\n\tif ok {\n\t\treturn nil\n\t}\n\treturn err\n}",
+		}
+	}
+	closeFn := func() *model.Declaration {
+		return &model.Declaration{
+			Kind:   model.FuncKind,
+			Name:   "Close",
+			File:   "store.go",
+			Line:   12,
+			Doc:    "// Close closes the store.",
+			Source: "// Close closes the store.\nfunc Close() error {\n" + strings.Repeat("\tstep()\n", 7) + "\treturn nil\n}",
+		}
+	}
+
+	profile := strings.Join([]string{
+		"mode: set",
+		"example.com/app/store/store.go:5.20,6.7 1 1",
+		"example.com/app/store/store.go:6.7,8.3 1 1",
+		// the first block of Close
+		"example.com/app/store/store.go:12.21,13.8 1 0",
+		"example.com/app/store/store.go:13.8,21.2 8 1",
+	}, "\n")
+
+	profiles, err := cover.ParseProfilesFromReader(strings.NewReader(profile))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		endLine bool
+	}{
+		{"source", false},
+		{"end line", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			first, second := open(), closeFn()
+			if tc.endLine {
+				first.EndLine, second.EndLine = 10, 21
+			}
+			assert.Equal(t, 10, first.LastLine())
+			assert.Equal(t, 21, second.LastLine())
+
+			defs := []*model.Definition{
+				{
+					Package: model.Package{ImportPath: "example.com/app/store"},
+					Funcs:   model.DeclarationList{first, second},
+				},
+			}
+
+			result := applyProfiles(defs, profiles)
+			assert.Empty(t, result.Unmatched)
+			assert.Equal(t, 100.0, first.Complexity.Coverage)
+			assert.Equal(t, 88.9, second.Complexity.Coverage)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fbiville/markdown-table-formatter/pkg/markdown"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// coverageResult holds the counts of applying coverage to the model,
+// and the coverage entries that didn't match a declaration.
+type coverageResult struct {
+	Functions        int
+	FunctionsCovered int
+	Packages         int
+	PackagesCovered  int
+	Inits            int
+
+	Unmatched []Unmatched
+}
+
+// Unmatched is a coverage entry without a matching declaration.
+type Unmatched struct {
+	Package  string
+	File     string `json:",omitempty"`
+	Function string `json:",omitempty"`
+	Line     int    `json:",omitempty"`
+	EndLine  int    `json:",omitempty"`
+
+	// Statements is the number of statements in coverprofile blocks.
+	Statements int `json:",omitempty"`
+
+	Reason string
+}
+
+func findPackage(defs []*model.Definition, name string) *model.Definition {
+	for _, def := range defs {
+		if name == def.Package.ImportPath {
+			return def
+		}
+	}
+	return nil
+}
+
+// applySummary applies the `summary coverfunc` coverage to the model,
+// matching functions by name, file and line.
+func applySummary(defs []*model.Definition, coverinfo *Coverage) (*coverageResult, error) {
+	result := &coverageResult{
+		Functions: len(coverinfo.Functions),
+		Packages:  len(coverinfo.Packages),
+	}
+
+	for _, info := range coverinfo.Functions {
+		p := findPackage(defs, info.Package)
+		if p == nil {
+			result.Unmatched = append(result.Unmatched, Unmatched{
+				Package:  info.Package,
+				File:     info.File,
+				Function: info.Function,
+				Line:     info.Line,
+				Reason:   "package not in model",
+			})
+			continue
+		}
+
+		// init may show up multiple times in one package
+		if info.Function == "init" {
+			result.Inits++
+			p.InitCount++
+			continue
+		}
+
+		f := p.Funcs.Find(func(d *model.Declaration) bool {
+			if d.Kind != model.FuncKind {
+				return false
+			}
+			return d.Name == info.Function && d.File == info.File && d.Line == info.Line
+		})
+		if f == nil {
+			result.Unmatched = append(result.Unmatched, Unmatched{
+				Package:  info.Package,
+				File:     info.File,
+				Function: info.Function,
+				Line:     info.Line,
+				Reason:   "function not found",
+			})
+			continue
+		}
+
+		if f.Complexity != nil {
+			f.Complexity.Coverage = info.Coverage
+		} else {
+			f.Complexity = &model.Complexity{
+				Coverage: info.Coverage,
+			}
+		}
+		if info.Coverage > 0 {
+			result.FunctionsCovered++
+		}
+	}
+
+	for _, info := range coverinfo.Packages {
+		p := findPackage(defs, info.Package)
+		if p == nil {
+			return nil, fmt.Errorf("Can't find package by name: %s", info.Package)
+		}
+
+		setPackageCoverage(p, info.Coverage)
+
+		if info.Coverage > 0 {
+			result.PackagesCovered++
+		}
+	}
+
+	return result, nil
+}
+
+// setPackageCoverage sets the package coverage and sums up the
+// complexity of the package functions.
+func setPackageCoverage(p *model.Definition, coverage float64) {
+	if p.Complexity != nil {
+		p.Complexity.Coverage = coverage
+	} else {
+		p.Complexity = &model.Complexity{
+			Coverage: coverage,
+		}
+	}
+
+	p.Funcs.Walk(func(d *model.Declaration) {
+		if d.Kind != model.FuncKind || d.Complexity == nil {
+			return
+		}
+		p.Complexity.Cognitive += d.Complexity.Cognitive
+		p.Complexity.Cyclomatic += d.Complexity.Cyclomatic
+		p.Complexity.Lines += d.Complexity.Lines
+	})
+}
+
+// printUnmatched prints the unmatched coverage entries as a table.
+func printUnmatched(w io.Writer, entries []Unmatched) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+
+	rows := make([][]string, 0, len(entries))
+	for _, e := range entries {
+		location := e.File
+		if e.Line > 0 {
+			location = fmt.Sprintf("%s:%d", e.File, e.Line)
+			if e.EndLine > e.Line {
+				location += fmt.Sprintf("-%d", e.EndLine)
+			}
+		}
+		statements := ""
+		if e.Statements > 0 {
+			statements = fmt.Sprint(e.Statements)
+		}
+		rows = append(rows, []string{e.Package, location, e.Function, statements, e.Reason})
+	}
+
+	table, err := markdown.NewTableFormatterBuilder().WithPrettyPrint().Build("Package", "Location", "Function", "Statements", "Reason").Format(rows)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Unmatched coverage entries (%d):\n\n%s\n\n", len(entries), strings.TrimSpace(table))
+	return nil
+}
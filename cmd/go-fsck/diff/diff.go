@@ -0,0 +1,316 @@
+// Package diff compares two go-fsck models.
+package diff
+
+import (
+	"strings"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// ChangeKind describes how a symbol changed between models.
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Result holds the differences between two models.
+type Result struct {
+	Changes []*Change
+}
+
+// Change holds the differences for a package or a declaration. Package
+// level changes have an empty Symbol.
+type Change struct {
+	Kind       ChangeKind
+	ImportPath string
+	Symbol     string                `json:",omitempty"`
+	DeclKind   model.DeclarationKind `json:",omitempty"`
+	Exported   bool                  `json:",omitempty"`
+
+	File string `json:",omitempty"`
+	Line int    `json:",omitempty"`
+
+	// Type holds the old and new type for types, consts and vars.
+	Type *Delta `json:",omitempty"`
+
+	// Signature holds the old and new signature for funcs.
+	Signature *Delta `json:",omitempty"`
+
+	// Receiver holds the old and new receiver for methods.
+	Receiver *Delta `json:",omitempty"`
+
+	// TypeParams holds the old and new type parameters of generic types and funcs.
+	TypeParams *Delta `json:",omitempty"`
+
+	// Fields holds struct and interface field changes.
+	Fields []*FieldChange `json:",omitempty"`
+
+	// Complexity holds complexity and coverage changes.
+	Complexity *ComplexityDelta `json:",omitempty"`
+
+	Old *model.Declaration `json:"-"`
+	New *model.Declaration `json:"-"`
+}
+
+// Delta holds an old and a new value.
+type Delta struct {
+	Old string
+	New string
+}
+
+// FieldChange holds the differences for a struct field or interface method.
+type FieldChange struct {
+	Kind ChangeKind
+	Path string
+
+	Type *Delta `json:",omitempty"`
+	Tag  *Delta `json:",omitempty"`
+
+	Old *model.Field `json:"-"`
+	New *model.Field `json:"-"`
+}
+
+// ComplexityDelta holds the old and new complexity. A nil complexity
+// is compared as zero values.
+type ComplexityDelta struct {
+	Old model.Complexity
+	New model.Complexity
+}
+
+// Cognitive returns the cognitive complexity difference.
+func (c *ComplexityDelta) Cognitive() int {
+	return c.New.Cognitive - c.Old.Cognitive
+}
+
+// Cyclomatic returns the cyclomatic complexity difference.
+func (c *ComplexityDelta) Cyclomatic() int {
+	return c.New.Cyclomatic - c.Old.Cyclomatic
+}
+
+// Lines returns the line count difference.
+func (c *ComplexityDelta) Lines() int {
+	return c.New.Lines - c.Old.Lines
+}
+
+// Coverage returns the coverage difference in percentage points.
+func (c *ComplexityDelta) Coverage() float64 {
+	return c.New.Coverage - c.Old.Coverage
+}
+
+// Name returns the qualified symbol name, or the import path for packages.
+func (c *Change) Name() string {
+	if c.Symbol == "" {
+		return c.ImportPath
+	}
+	return c.ImportPath + "." + c.Symbol
+}
+
+// IsPackage returns true if the change is for a package.
+func (c *Change) IsPackage() bool {
+	return c.Symbol == ""
+}
+
+// Filter returns the changes matching matchfn.
+func (r *Result) Filter(matchfn func(*Change) bool) []*Change {
+	var result []*Change
+	for _, change := range r.Changes {
+		if matchfn(change) {
+			result = append(result, change)
+		}
+	}
+	return result
+}
+
+// Count returns the number of changes of a kind, excluding packages.
+func (r *Result) Count(kind ChangeKind) int {
+	return len(r.Filter(func(c *Change) bool {
+		return c.Kind == kind && !c.IsPackage()
+	}))
+}
+
+// Compare returns the differences between the old and new model.
+// Packages are matched by import path, declarations by their symbol
+// name within a package (e.g. `Name` or `Receiver.Name`).
+func Compare(oldDefs, newDefs []*model.Definition) *Result {
+	result := &Result{}
+
+	oldPkgs := definitionIndex(oldDefs)
+	newPkgs := definitionIndex(newDefs)
+
+	for _, importPath := range internal.SortedKeys(oldPkgs, newPkgs) {
+		oldDef, newDef := oldPkgs[importPath], newPkgs[importPath]
+
+		switch {
+		case oldDef == nil:
+			result.Changes = append(result.Changes, &Change{Kind: Added, ImportPath: importPath})
+		case newDef == nil:
+			result.Changes = append(result.Changes, &Change{Kind: Removed, ImportPath: importPath})
+		default:
+			if delta := compareComplexity(oldDef.Complexity, newDef.Complexity); delta != nil {
+				result.Changes = append(result.Changes, &Change{
+					Kind:       Changed,
+					ImportPath: importPath,
+					Complexity: delta,
+				})
+			}
+		}
+
+		result.Changes = append(result.Changes, compareDeclarations(importPath, declarationIndex(oldDef), declarationIndex(newDef))...)
+	}
+
+	return result
+}
+
+func compareDeclarations(importPath string, oldDecls, newDecls map[string]*model.Declaration) []*Change {
+	var result []*Change
+	for _, symbol := range internal.SortedKeys(oldDecls, newDecls) {
+		oldDecl, newDecl := oldDecls[symbol], newDecls[symbol]
+
+		change := &Change{
+			ImportPath: importPath,
+			Symbol:     symbol,
+			Old:        oldDecl,
+			New:        newDecl,
+		}
+
+		decl := newDecl
+		switch {
+		case oldDecl == nil:
+			change.Kind = Added
+		case newDecl == nil:
+			change.Kind = Removed
+			decl = oldDecl
+		default:
+			change.Kind = Changed
+			if !compareDeclaration(change, oldDecl, newDecl) {
+				continue
+			}
+		}
+
+		change.DeclKind = decl.Kind
+		change.Exported = decl.IsExported()
+		change.File = decl.File
+		change.Line = decl.Line
+
+		result = append(result, change)
+	}
+	return result
+}
+
+// compareDeclaration fills the change details and returns true if
+// any differences were found.
+func compareDeclaration(change *Change, oldDecl, newDecl *model.Declaration) bool {
+	if oldDecl.Kind == model.FuncKind || newDecl.Kind == model.FuncKind {
+		if oldDecl.Signature != newDecl.Signature {
+			change.Signature = &Delta{oldDecl.Signature, newDecl.Signature}
+		}
+		if oldDecl.Receiver != newDecl.Receiver {
+			change.Receiver = &Delta{oldDecl.Receiver, newDecl.Receiver}
+		}
+	} else if oldDecl.Type != newDecl.Type {
+		change.Type = &Delta{oldDecl.Type, newDecl.Type}
+	}
+
+	if oldParams, newParams := oldDecl.TypeParams.String(), newDecl.TypeParams.String(); oldParams != newParams {
+		change.TypeParams = &Delta{oldParams, newParams}
+	}
+
+	change.Fields = compareFields(oldDecl.Fields, newDecl.Fields)
+	change.Complexity = compareComplexity(oldDecl.Complexity, newDecl.Complexity)
+
+	return change.Signature != nil || change.Receiver != nil || change.Type != nil || change.TypeParams != nil || len(change.Fields) > 0 || change.Complexity != nil
+}
+
+func compareFields(oldFields, newFields model.FieldList) []*FieldChange {
+	oldIndex, newIndex := fieldIndex(oldFields), fieldIndex(newFields)
+
+	var result []*FieldChange
+	for _, key := range internal.SortedKeys(oldIndex, newIndex) {
+		oldField, newField := oldIndex[key], newIndex[key]
+
+		change := &FieldChange{
+			Path: key,
+			Old:  oldField,
+			New:  newField,
+		}
+
+		switch {
+		case oldField == nil:
+			change.Kind = Added
+		case newField == nil:
+			change.Kind = Removed
+		default:
+			change.Kind = Changed
+			if oldField.Type != newField.Type {
+				change.Type = &Delta{oldField.Type, newField.Type}
+			}
+			if oldField.Tag != newField.Tag {
+				change.Tag = &Delta{oldField.Tag, newField.Tag}
+			}
+			if change.Type == nil && change.Tag == nil {
+				continue
+			}
+		}
+
+		result = append(result, change)
+	}
+	return result
+}
+
+func compareComplexity(oldComplexity, newComplexity *model.Complexity) *ComplexityDelta {
+	delta := &ComplexityDelta{}
+	if oldComplexity != nil {
+		delta.Old = *oldComplexity
+	}
+	if newComplexity != nil {
+		delta.New = *newComplexity
+	}
+	if delta.Old == delta.New {
+		return nil
+	}
+	return delta
+}
+
+func definitionIndex(defs []*model.Definition) map[string]*model.Definition {
+	result := make(map[string]*model.Definition, len(defs))
+	for _, def := range defs {
+		if existing, ok := result[def.ImportPath]; ok {
+			existing.Merge(def)
+			continue
+		}
+		result[def.ImportPath] = def
+	}
+	return result
+}
+
+func declarationIndex(def *model.Definition) map[string]*model.Declaration {
+	result := map[string]*model.Declaration{}
+	if def == nil {
+		return result
+	}
+	for _, decl := range def.DeclarationList() {
+		for _, key := range decl.Keys() {
+			result[key] = decl
+		}
+	}
+	return result
+}
+
+func fieldIndex(fields model.FieldList) map[string]*model.Field {
+	result := make(map[string]*model.Field, len(fields))
+	for _, field := range fields {
+		key := field.Path
+		if key == "" {
+			key = field.Name
+		}
+		if key == "" {
+			key = strings.TrimSpace(field.Embed + " " + field.Type)
+		}
+		result[key] = field
+	}
+	return result
+}
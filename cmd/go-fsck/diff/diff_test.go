@@ -0,0 +1,141 @@
+package diff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
+)
+
+func testDefinitions(modified bool) []*model.Definition {
+	def := &model.Definition{
+		Package: model.Package{Package: "store", ImportPath: "github.com/x/store"},
+		Types: model.DeclarationList{
+			{
+				Kind: model.TypeKind, Name: "Item", Type: "struct", File: "store.go",
+				Fields: model.FieldList{
+					{Name: "ID", Path: "Item.ID", Type: "string", Tag: `json:"id"`},
+					{Name: "Name", Path: "Item.Name", Type: "string"},
+				},
+			},
+		},
+		Funcs: model.DeclarationList{
+			{
+				Kind: model.FuncKind, Name: "Get", File: "store.go",
+				Signature:  "Get (id string) (*Item, error)",
+				Complexity: &model.Complexity{Cognitive: 1, Cyclomatic: 2, Lines: 10},
+			},
+			{Kind: model.FuncKind, Name: "Delete", File: "store.go", Signature: "Delete (id string) error"},
+			{Kind: model.FuncKind, Name: "get", File: "store.go", Signature: "get (id string) *Item"},
+		},
+	}
+
+	if modified {
+		item := def.Types[0]
+		item.Fields[0].Tag = `json:"id,omitempty"`
+		item.Fields = append(item.Fields[:1], &model.Field{Name: "Size", Path: "Item.Size", Type: "int"})
+
+		get := def.Funcs[0]
+		get.Signature = "Get (ctx context.Context, id string) (*Item, error)"
+		get.Complexity = &model.Complexity{Cognitive: 3, Cyclomatic: 2, Lines: 12, Coverage: 50}
+
+		def.Funcs[1] = &model.Declaration{Kind: model.FuncKind, Name: "Set", File: "store.go", Signature: "Set (item *Item) error"}
+		def.Funcs[2].Signature = "get (ctx context.Context, id string) *Item"
+	}
+
+	return []*model.Definition{def}
+}
+
+func TestCompare(t *testing.T) {
+	result := Compare(testDefinitions(false), testDefinitions(true))
+
+	assert.Equal(t, 1, result.Count(Added))
+	assert.Equal(t, 1, result.Count(Removed))
+	assert.Equal(t, 3, result.Count(Changed))
+
+	changes := map[string]*Change{}
+	for _, change := range result.Changes {
+		changes[change.Symbol] = change
+	}
+
+	assert.Equal(t, Added, changes["Set"].Kind)
+	assert.Equal(t, Removed, changes["Delete"].Kind)
+
+	get := changes["Get"]
+	require.NotNil(t, get.Signature)
+	assert.Equal(t, "Get (ctx context.Context, id string) (*Item, error)", get.Signature.New)
+	assert.True(t, get.Exported)
+	require.NotNil(t, get.Complexity)
+	assert.Equal(t, 2, get.Complexity.Cognitive())
+	assert.Equal(t, 0, get.Complexity.Cyclomatic())
+	assert.Equal(t, 50.0, get.Complexity.Coverage())
+
+	item := changes["Item"]
+	require.Len(t, item.Fields, 3)
+	assert.Equal(t, "Item.ID", item.Fields[0].Path)
+	assert.Equal(t, Changed, item.Fields[0].Kind)
+	assert.Equal(t, &Delta{`json:"id"`, `json:"id,omitempty"`}, item.Fields[0].Tag)
+	assert.Nil(t, item.Fields[0].Type)
+	assert.Equal(t, Removed, item.Fields[1].Kind)
+	assert.Equal(t, Added, item.Fields[2].Kind)
+
+	assert.False(t, changes["get"].Exported)
+}
+
+func TestCompare_packages(t *testing.T) {
+	result := Compare(nil, testDefinitions(false))
+
+	require.NotEmpty(t, result.Changes)
+	assert.True(t, result.Changes[0].IsPackage())
+	assert.Equal(t, Added, result.Changes[0].Kind)
+	assert.Equal(t, 4, result.Count(Added))
+
+	assert.Empty(t, Compare(testDefinitions(false), testDefinitions(false)).Changes)
+}
+
+func TestCompare_coverage(t *testing.T) {
+	newDefs, err := loader.ReadFile("../coverage/testdata/go-fsck.json.modified")
+	require.NoError(t, err)
+
+	oldDefs, err := loader.ReadFile("../coverage/testdata/go-fsck.json.modified")
+	require.NoError(t, err)
+	for _, def := range oldDefs {
+		def.Funcs.Walk(func(d *model.Declaration) {
+			if d.Complexity != nil {
+				d.Complexity.Coverage = 0
+			}
+		})
+	}
+
+	result := Compare(oldDefs, newDefs)
+
+	// matches the differences in coverage/testdata/result.txt
+	assert.Equal(t, 7, result.Count(Changed))
+	for _, change := range result.Changes {
+		require.NotNil(t, change.Complexity)
+		assert.Equal(t, 0, change.Complexity.Cognitive())
+		assert.Greater(t, change.Complexity.Coverage(), 0.0)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Markdown(&out, Compare(testDefinitions(false), testDefinitions(true)), false))
+
+	md := out.String()
+	assert.Contains(t, md, "## Added")
+	assert.Contains(t, md, "## Removed")
+	assert.Contains(t, md, "- Get (id string) (*Item, error)\n+ Get (ctx context.Context, id string) (*Item, error)")
+	assert.NotContains(t, md, "- get (id string)")
+	assert.Contains(t, md, "tag `json:\"id\"` → `json:\"id,omitempty\"`")
+	assert.Contains(t, md, "3 (+2)")
+	assert.Contains(t, md, "50.0% (+50.0)")
+
+	out.Reset()
+	require.NoError(t, Markdown(&out, &Result{}, false))
+	assert.Equal(t, "No changes.\n", out.String())
+}
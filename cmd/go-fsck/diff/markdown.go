@@ -0,0 +1,187 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fbiville/markdown-table-formatter/pkg/markdown"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// Markdown renders the result as markdown, suitable for a PR comment.
+// Signature changes are listed for exported funcs, unless all is set.
+func Markdown(w io.Writer, result *Result, all bool) error {
+	if len(result.Changes) == 0 {
+		fmt.Fprintln(w, "No changes.")
+		return nil
+	}
+
+	summary := [][]string{
+		{"Added", fmt.Sprint(result.Count(Added))},
+		{"Removed", fmt.Sprint(result.Count(Removed))},
+		{"Changed", fmt.Sprint(result.Count(Changed))},
+	}
+	if err := writeTable(w, "", []string{"Declarations", "Count"}, summary); err != nil {
+		return err
+	}
+
+	for _, kind := range []ChangeKind{Added, Removed} {
+		title := "## Added"
+		if kind == Removed {
+			title = "## Removed"
+		}
+
+		changes := result.Filter(func(c *Change) bool {
+			return c.Kind == kind
+		})
+
+		rows := make([][]string, 0, len(changes))
+		for _, change := range changes {
+			declKind := string(change.DeclKind)
+			if change.IsPackage() {
+				declKind = "package"
+			}
+			rows = append(rows, []string{code(change.Name()), declKind, location(change)})
+		}
+
+		if err := writeTable(w, title, []string{"Symbol", "Kind", "Location"}, rows); err != nil {
+			return err
+		}
+	}
+
+	signatures := result.Filter(func(c *Change) bool {
+		return (c.Signature != nil || c.Receiver != nil || c.Type != nil || c.TypeParams != nil) && (all || c.Exported)
+	})
+	if len(signatures) > 0 {
+		fmt.Fprintln(w, "## Signature changes")
+		fmt.Fprintln(w)
+		for _, change := range signatures {
+			fmt.Fprintf(w, "%s\n\n```diff\n- %s\n+ %s\n```\n\n", code(change.Name()), signature(change.Old), signature(change.New))
+		}
+	}
+
+	fields := [][]string{}
+	for _, change := range result.Changes {
+		for _, field := range change.Fields {
+			fields = append(fields, []string{code(change.Name()), code(field.Path), string(field.Kind), fieldDetails(field)})
+		}
+	}
+	if err := writeTable(w, "## Field changes", []string{"Type", "Field", "Change", "Details"}, fields); err != nil {
+		return err
+	}
+
+	complexity := [][]string{}
+	for _, change := range result.Changes {
+		c := change.Complexity
+		if c == nil {
+			continue
+		}
+		complexity = append(complexity, []string{
+			code(change.Name()),
+			intDelta(c.New.Cognitive, c.Cognitive()),
+			intDelta(c.New.Cyclomatic, c.Cyclomatic()),
+			intDelta(c.New.Lines, c.Lines()),
+			floatDelta(c.New.Coverage, c.Coverage()),
+		})
+	}
+	return writeTable(w, "## Complexity", []string{"Symbol", "Cognitive", "Cyclomatic", "Lines", "Coverage"}, complexity)
+}
+
+func writeTable(w io.Writer, title string, header []string, rows [][]string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	// pipes in type sets would break the table
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+	}
+
+	t, err := markdown.NewTableFormatterBuilder().WithPrettyPrint().Build(header...).Format(rows)
+	if err != nil {
+		return err
+	}
+
+	if title != "" {
+		fmt.Fprintf(w, "%s\n\n", title)
+	}
+	fmt.Fprintln(w, t)
+	return nil
+}
+
+func fieldDetails(field *FieldChange) string {
+	var result []string
+	switch field.Kind {
+	case Added:
+		result = append(result, fieldType(field.New.Type, field.New.Tag))
+	case Removed:
+		result = append(result, fieldType(field.Old.Type, field.Old.Tag))
+	default:
+		if field.Type != nil {
+			result = append(result, "type "+code(field.Type.Old)+" → "+code(field.Type.New))
+		}
+		if field.Tag != nil {
+			result = append(result, "tag "+code(field.Tag.Old)+" → "+code(field.Tag.New))
+		}
+	}
+	return strings.Join(result, ", ")
+}
+
+func fieldType(typ, tag string) string {
+	if tag == "" {
+		return code(typ)
+	}
+	return code(typ + " `" + tag + "`")
+}
+
+// signature returns the signature of a func including the receiver,
+// or the type parameters and type of other declarations.
+func signature(decl *model.Declaration) string {
+	if decl.Kind != model.FuncKind {
+		return strings.TrimSpace(decl.TypeParams.String() + " " + decl.Type)
+	}
+	if decl.Receiver != "" {
+		return "(" + decl.Receiver + ") " + decl.Signature
+	}
+	return decl.Signature
+}
+
+func location(change *Change) string {
+	if change.File == "" {
+		return ""
+	}
+	if change.Line == 0 {
+		return change.File
+	}
+	return fmt.Sprintf("%s:%d", change.File, change.Line)
+}
+
+// code wraps a value in backticks, using double backticks if the
+// value contains one, e.g. for struct tags.
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
+
+func intDelta(value, delta int) string {
+	if delta == 0 {
+		return fmt.Sprint(value)
+	}
+	return fmt.Sprintf("%d (%+d)", value, delta)
+}
+
+func floatDelta(value, delta float64) string {
+	if delta == 0 {
+		return fmt.Sprintf("%.1f%%", value)
+	}
+	return fmt.Sprintf("%.1f%% (%+.1f)", value, delta)
+}
@@ -0,0 +1,43 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+)
+
+type options struct {
+	oldFile string
+	newFile string
+
+	all  bool
+	json bool
+	args []string
+
+	fs *internal.FlagSet
+}
+
+// NewOptions parses command-line flags and returns the diff options.
+func NewOptions() *options {
+	cfg := &options{}
+
+	cfg.fs = internal.NewFlagSet("diff")
+	cfg.fs.BoolVar(&cfg.all, "all", cfg.all, "list signature changes for unexported symbols")
+	cfg.fs.BoolVar(&cfg.json, "json", cfg.json, "print results as json")
+
+	cfg.args = internal.ParseArgs(cfg.fs)
+
+	if len(cfg.args) == 2 {
+		cfg.oldFile, cfg.newFile = cfg.args[0], cfg.args[1]
+	}
+
+	return cfg
+}
+
+// PrintHelp displays usage information for the diff command.
+func (o *options) PrintHelp() {
+	fmt.Printf("Usage: %s diff <options> old.json new.json:\n\n", path.Base(os.Args[0]))
+	o.fs.PrintDefaults()
+}
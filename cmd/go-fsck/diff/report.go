@@ -0,0 +1,39 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
+)
+
+func diff(cfg *options) error {
+	if cfg.oldFile == "" || cfg.newFile == "" {
+		return errors.New("diff requires two arguments: old.json new.json")
+	}
+
+	oldDefs, err := loader.ReadFile(cfg.oldFile)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", cfg.oldFile, err)
+	}
+
+	newDefs, err := loader.ReadFile(cfg.newFile)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", cfg.newFile, err)
+	}
+
+	result := Compare(oldDefs, newDefs)
+
+	if cfg.json {
+		b, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	return Markdown(os.Stdout, result, cfg.all)
+}
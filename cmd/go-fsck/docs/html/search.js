@@ -0,0 +1,32 @@
+(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("search-results");
+
+  input.addEventListener("input", function () {
+    var query = input.value.trim().toLowerCase();
+    results.innerHTML = "";
+    if (query === "" || typeof searchIndex === "undefined") {
+      return;
+    }
+
+    searchIndex
+      .filter(function (entry) {
+        return entry.name.toLowerCase().indexOf(query) !== -1;
+      })
+      .slice(0, 50)
+      .forEach(function (entry) {
+        var link = document.createElement("a");
+        link.href = entry.url;
+        link.textContent = entry.name;
+
+        var kind = document.createElement("span");
+        kind.textContent = entry.kind;
+        link.appendChild(kind);
+
+        var item = document.createElement("li");
+        item.title = entry.synopsis || entry.package;
+        item.appendChild(link);
+        results.appendChild(item);
+      });
+  });
+})();
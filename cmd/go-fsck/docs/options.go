@@ -7,6 +7,7 @@ import (
 	"path"
 
 	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
 )
 
 type options struct {
@@ -20,11 +21,18 @@ type options struct {
 	docs bool
 
 	verbose bool
+	jobs    int
 	split   bool
 	out     string
 	strip   string
 	args    []string
 
+	sourceURL string
+
+	goos   string
+	goarch string
+	tags   []string
+
 	fs *internal.FlagSet
 }
 
@@ -39,20 +47,35 @@ func NewOptions() *options {
 
 	cfg.fs = internal.NewFlagSet("docs")
 	cfg.fs.StringVarP(&cfg.inputFile, "input-file", "i", cfg.inputFile, "input file")
-	cfg.fs.StringVar(&cfg.render, "render", cfg.render, "print results as [markdown, json, ...]")
+	cfg.fs.StringVar(&cfg.render, "render", cfg.render, "print results as [markdown, json, plantuml, imports, spec, html]")
 	cfg.fs.StringVar(&cfg.focus, "focus", cfg.focus, "focus on configured symbol")
 	cfg.fs.BoolVar(&cfg.model, "model", cfg.model, "model mode: skip functions and interfaces")
 	cfg.fs.StringVar(&cfg.hide, "hide", cfg.hide, "comma-separated list of types to hide")
 	cfg.fs.BoolVarP(&cfg.verbose, "verbose", "v", cfg.verbose, "verbose output")
+	cfg.fs.IntVarP(&cfg.jobs, "jobs", "j", cfg.jobs, "number of packages to load in parallel (default GOMAXPROCS)")
 	cfg.fs.BoolVar(&cfg.split, "split", cfg.split, "split output file per package")
-	cfg.fs.StringVar(&cfg.out, "out", cfg.out, "output directory (used with --split)")
+	cfg.fs.StringVar(&cfg.out, "out", cfg.out, "output directory (used with --split and --render html)")
 	cfg.fs.StringVar(&cfg.strip, "strip", cfg.strip, "prefix to strip from import path for filename")
+	cfg.fs.StringVar(&cfg.sourceURL, "source-url", cfg.sourceURL, "source link template with {path} and {line}, used with --render html")
+
+	cfg.fs.StringVar(&cfg.goos, "goos", cfg.goos, "only document declarations for GOOS (default: current)")
+	cfg.fs.StringVar(&cfg.goarch, "goarch", cfg.goarch, "only document declarations for GOARCH (default: current)")
+	cfg.fs.StringSliceVar(&cfg.tags, "tags", cfg.tags, "build tags to satisfy")
 
 	cfg.args = internal.ParseArgs(cfg.fs)
 
 	return cfg
 }
 
+// build returns the build context to filter declarations with. It returns
+// nil when none of the build flags are set.
+func (o *options) build() *loader.BuildContext {
+	if o.goos == "" && o.goarch == "" && len(o.tags) == 0 {
+		return nil
+	}
+	return loader.NewBuildContext(o.goos, o.goarch, o.tags)
+}
+
 func PrintHelp() {
 	fmt.Printf("Usage: %s docs <options>:\n\n", path.Base(os.Args[0]))
 	flag.PrintDefaults()
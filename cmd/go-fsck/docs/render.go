@@ -1,6 +1,7 @@
 package docs
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 
@@ -13,6 +14,10 @@ func getDefinitions(cfg *options) ([]*model.Definition, error) {
 	// Read the exported go-fsck.json data.
 	defs, err := loader.ReadFile(cfg.inputFile)
 	if err == nil {
+		// models extracted with `--all-platforms` hold all declarations
+		if build := cfg.build(); build != nil {
+			loader.FilterDefinitions(defs, build)
+		}
 		return defs, nil
 	}
 
@@ -28,14 +33,19 @@ func getDefinitions(cfg *options) ([]*model.Definition, error) {
 		return nil, err
 	}
 
-	defs = []*model.Definition{}
+	results, err := loader.LoadAll(context.Background(), packages, &loader.Options{
+		Verbose: cfg.verbose,
+		Jobs:    cfg.jobs,
+		Build:   cfg.build(),
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	for _, pkg := range packages {
-		d, err := loader.Load(pkg, false, cfg.verbose)
-		if err != nil {
-			return nil, err
-		}
+	defs = []*model.Definition{}
 
+	for i, pkg := range packages {
+		d := results[i]
 		for _, v := range d {
 			v.Package.ID = pkg.ID
 			v.Package.ImportPath = pkg.ImportPath
@@ -67,6 +77,8 @@ func render(cfg *options) error {
 		return renderImports(cfg, defs)
 	case "json":
 		return renderJSON(cfg, defs)
+	case "html":
+		return renderHTML(cfg, defs)
 	case "puml", "plantuml":
 		return renderPlantUML(cfg, defs)
 	default:
@@ -0,0 +1,404 @@
+package docs
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/doc/comment"
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+//go:embed html
+var htmlFS embed.FS
+
+// htmlSite holds the pages of the static site. All pages are written
+// to the output folder without subfolders, so links are file names.
+type htmlSite struct {
+	cfg *options
+
+	Packages []*htmlPackage
+
+	// pages maps import paths and type names (`import/path.Type`) to page filenames.
+	pages map[string]string
+}
+
+type htmlPackage struct {
+	Name       string
+	ImportPath string
+	Filename   string
+	Synopsis   string
+	Doc        template.HTML
+
+	Consts []*htmlDecl
+	Vars   []*htmlDecl
+	Funcs  []*htmlDecl
+	Types  []*htmlType
+
+	def *model.Definition
+}
+
+type htmlDecl struct {
+	Name      string
+	Synopsis  string
+	Doc       template.HTML
+	Signature template.HTML
+	Location  string
+	SourceURL string
+}
+
+type htmlType struct {
+	htmlDecl
+
+	Filename string
+	Package  *htmlPackage
+	Kind     string
+	Fields   []*htmlField
+	Methods  []*htmlDecl
+}
+
+type htmlField struct {
+	Name string
+	Type template.HTML
+	Tag  string
+	Doc  string
+}
+
+// searchEntry is an item in the client side search index.
+type searchEntry struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Package  string `json:"package"`
+	URL      string `json:"url"`
+	Synopsis string `json:"synopsis,omitempty"`
+}
+
+// renderHTML writes a static site with a package index, a page for each
+// package and each exported type, and a search index.
+func renderHTML(cfg *options, defs []*model.Definition) error {
+	if err := os.MkdirAll(cfg.out, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	return writeHTML(cfg, defs, func(filename string, data []byte) error {
+		return os.WriteFile(filepath.Join(cfg.out, filename), data, 0644)
+	})
+}
+
+// RenderHTML renders the static site, passing each file to write. The
+// strip prefix and source url template are the --strip and --source-url
+// options of `docs --render html`.
+func RenderHTML(defs []*model.Definition, strip, sourceURL string, write func(filename string, data []byte) error) error {
+	cfg := &options{
+		strip:     strip,
+		sourceURL: sourceURL,
+	}
+	return writeHTML(cfg, defs, write)
+}
+
+func writeHTML(cfg *options, defs []*model.Definition, write func(filename string, data []byte) error) error {
+	site := newHTMLSite(cfg, defs)
+
+	tmpl, err := template.ParseFS(htmlFS, "html/*.tmpl")
+	if err != nil {
+		return err
+	}
+
+	render := func(filename string, name string, data any) error {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+			return fmt.Errorf("failed to render %s: %w", filename, err)
+		}
+		return write(filename, buf.Bytes())
+	}
+
+	if err := render("index.html", "index.tmpl", site); err != nil {
+		return err
+	}
+
+	for _, pkg := range site.Packages {
+		if err := render(pkg.Filename, "package.tmpl", pkg); err != nil {
+			return err
+		}
+		for _, t := range pkg.Types {
+			if err := render(t.Filename, "type.tmpl", t); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, asset := range []string{"style.css", "search.js"} {
+		data, err := htmlFS.ReadFile("html/" + asset)
+		if err != nil {
+			return err
+		}
+		if err := write(asset, data); err != nil {
+			return err
+		}
+	}
+
+	// The index is a script, so the search works when opening the site from disk.
+	index, err := json.Marshal(site.searchIndex())
+	if err != nil {
+		return err
+	}
+	script := "var searchIndex = " + string(index) + ";\n"
+	return write("search-index.js", []byte(script))
+}
+
+// HTMLPage returns the page filename of a package, or of an exported
+// type in the package when typeName is set.
+func HTMLPage(importPath, typeName, strip string) string {
+	base := strings.TrimSuffix(generateFilename(importPath, strip), ".md")
+	if typeName != "" {
+		return base + "." + typeName + ".html"
+	}
+	return base + ".html"
+}
+
+func newHTMLSite(cfg *options, defs []*model.Definition) *htmlSite {
+	site := &htmlSite{
+		cfg:   cfg,
+		pages: map[string]string{},
+	}
+
+	groups := groupDefinitionsByPackage(defs)
+
+	importPaths := make([]string, 0, len(groups))
+	for importPath := range groups {
+		importPaths = append(importPaths, importPath)
+	}
+	sort.Strings(importPaths)
+
+	// Collect all pages first, so cross-links resolve in any order.
+	for _, importPath := range importPaths {
+		def := groups[importPath][0]
+		for _, in := range groups[importPath][1:] {
+			def.Merge(in)
+		}
+
+		pkg := &htmlPackage{
+			Name:       def.Package.Package,
+			ImportPath: importPath,
+			Filename:   HTMLPage(importPath, "", cfg.strip),
+			def:        def,
+		}
+		site.pages[importPath] = pkg.Filename
+
+		for _, t := range def.Types.Exported() {
+			site.pages[importPath+"."+t.Name] = HTMLPage(importPath, t.Name, cfg.strip)
+		}
+
+		site.Packages = append(site.Packages, pkg)
+	}
+
+	for _, pkg := range site.Packages {
+		site.fillPackage(pkg)
+	}
+
+	return site
+}
+
+func (s *htmlSite) fillPackage(pkg *htmlPackage) {
+	def := pkg.def
+
+	pkg.Synopsis = synopsis(def.Doc)
+	pkg.Doc = s.doc(def, def.Doc)
+
+	for _, decl := range def.Consts.Exported() {
+		pkg.Consts = append(pkg.Consts, s.decl(def, decl, decl.Source))
+	}
+	for _, decl := range def.Vars.Exported() {
+		pkg.Vars = append(pkg.Vars, s.decl(def, decl, decl.Source))
+	}
+
+	methods := map[string][]*htmlDecl{}
+	for _, fn := range def.Funcs.Exported() {
+		d := s.decl(def, fn, funcSignature(fn))
+		if fn.Receiver != "" {
+			receiver := fn.ReceiverTypeRef()
+			methods[receiver] = append(methods[receiver], d)
+			continue
+		}
+		pkg.Funcs = append(pkg.Funcs, d)
+	}
+
+	for _, decl := range def.Types.Exported() {
+		t := &htmlType{
+			htmlDecl: *s.decl(def, decl, decl.Source),
+			Filename: s.pages[pkg.ImportPath+"."+decl.Name],
+			Package:  pkg,
+			Kind:     typeKind(decl),
+			Methods:  methods[decl.Name],
+		}
+
+		for _, field := range decl.Fields {
+			if field.Name == "" && field.Embed == "" {
+				continue
+			}
+			if field.Name != "" && !ast.IsExported(field.Name) {
+				continue
+			}
+			name, typ := field.Name, field.Type
+			if field.Embed != "" {
+				name, typ = "", field.Embed
+			}
+			doc := field.Doc
+			if doc == "" {
+				doc = field.Comment
+			}
+			t.Fields = append(t.Fields, &htmlField{
+				Name: name,
+				Type: s.link(def, decl.File, typ),
+				Tag:  field.Tag,
+				Doc:  doc,
+			})
+		}
+
+		pkg.Types = append(pkg.Types, t)
+	}
+}
+
+func (s *htmlSite) decl(def *model.Definition, decl *model.Declaration, signature string) *htmlDecl {
+	names := decl.GetNames()
+	if signature == "" {
+		// sources are only present with `extract --include-sources`
+		signature = string(decl.Kind) + " " + strings.Join(names, ", ")
+		if decl.Kind == model.TypeKind {
+			signature = "type " + decl.Name + decl.TypeParams.String() + " " + typeKind(decl)
+		}
+	}
+
+	return &htmlDecl{
+		Name:      names[0],
+		Synopsis:  synopsis(decl.Doc),
+		Doc:       s.doc(def, decl.Doc),
+		Signature: s.link(def, decl.File, strings.TrimSpace(signature)),
+		Location:  fmt.Sprintf("%s:%d", decl.File, decl.Line),
+		SourceURL: s.sourceURL(def, decl),
+	}
+}
+
+// sourceURL fills the `{path}` and `{line}` placeholders of --source-url.
+func (s *htmlSite) sourceURL(def *model.Definition, decl *model.Declaration) string {
+	if s.cfg.sourceURL == "" || decl.File == "" {
+		return ""
+	}
+	filename := path.Join(strings.TrimPrefix(def.Package.Path, "./"), decl.File)
+	return strings.NewReplacer("{path}", filename, "{line}", fmt.Sprint(decl.Line)).Replace(s.cfg.sourceURL)
+}
+
+// identifier matches type references like `Name` or `pkg.Name`.
+var identifier = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?`)
+
+// link escapes Go source and links type references to their pages.
+// Qualified references are resolved with the imports of the file.
+func (s *htmlSite) link(def *model.Definition, file string, src string) template.HTML {
+	imports, _ := def.Imports.Map(def.Imports.Get(file))
+
+	var (
+		buf  strings.Builder
+		last int
+	)
+	for _, loc := range identifier.FindAllStringIndex(src, -1) {
+		buf.WriteString(template.HTMLEscapeString(src[last:loc[0]]))
+		last = loc[1]
+
+		ref := src[loc[0]:loc[1]]
+		key := def.Package.ImportPath + "." + ref
+		if pkgName, name, ok := strings.Cut(ref, "."); ok {
+			key = imports[pkgName] + "." + name
+		}
+
+		page, ok := s.pages[key]
+		if !ok {
+			buf.WriteString(template.HTMLEscapeString(ref))
+			continue
+		}
+		fmt.Fprintf(&buf, `<a href="%s">%s</a>`, page, template.HTMLEscapeString(ref))
+	}
+	buf.WriteString(template.HTMLEscapeString(src[last:]))
+
+	return template.HTML(buf.String())
+}
+
+// doc renders a doc comment, resolving [Name] and [pkg.Name] doc links.
+func (s *htmlSite) doc(def *model.Definition, text string) template.HTML {
+	if text == "" {
+		return ""
+	}
+
+	imports, _ := def.Imports.Map(def.Imports.All())
+
+	parser := &comment.Parser{
+		LookupPackage: func(name string) (string, bool) {
+			importPath, ok := imports[name]
+			return importPath, ok
+		},
+		LookupSym: func(recv, name string) bool {
+			_, ok := s.pages[def.Package.ImportPath+"."+name]
+			return recv == "" && ok
+		},
+	}
+	printer := &comment.Printer{
+		HeadingLevel: 3,
+		DocLinkURL: func(link *comment.DocLink) string {
+			importPath := link.ImportPath
+			if importPath == "" {
+				importPath = def.Package.ImportPath
+			}
+			if page, ok := s.pages[importPath+"."+link.Name]; ok {
+				return page
+			}
+			return link.DefaultURL("https://pkg.go.dev")
+		},
+	}
+
+	return template.HTML(printer.HTML(parser.Parse(text)))
+}
+
+func (s *htmlSite) searchIndex() []searchEntry {
+	result := []searchEntry{}
+	for _, pkg := range s.Packages {
+		result = append(result, searchEntry{Name: pkg.ImportPath, Kind: "package", Package: pkg.ImportPath, URL: pkg.Filename, Synopsis: pkg.Synopsis})
+		for _, t := range pkg.Types {
+			result = append(result, searchEntry{Name: pkg.Name + "." + t.Name, Kind: "type", Package: pkg.ImportPath, URL: t.Filename, Synopsis: t.Synopsis})
+			for _, m := range t.Methods {
+				result = append(result, searchEntry{Name: pkg.Name + "." + t.Name + "." + m.Name, Kind: "method", Package: pkg.ImportPath, URL: t.Filename + "#" + m.Name, Synopsis: m.Synopsis})
+			}
+		}
+		for _, fn := range pkg.Funcs {
+			result = append(result, searchEntry{Name: pkg.Name + "." + fn.Name, Kind: "func", Package: pkg.ImportPath, URL: pkg.Filename + "#" + fn.Name, Synopsis: fn.Synopsis})
+		}
+	}
+	return result
+}
+
+// typeKind returns `struct` for struct types, which have no type set.
+func typeKind(decl *model.Declaration) string {
+	if decl.Type == "" && len(decl.Fields) > 0 {
+		return "struct"
+	}
+	return decl.Type
+}
+
+func funcSignature(fn *model.Declaration) string {
+	if fn.Receiver != "" {
+		return "func (" + fn.Receiver + ") " + fn.Signature
+	}
+	return "func " + fn.Signature
+}
+
+func synopsis(text string) string {
+	return new(doc.Package).Synopsis(text)
+}
@@ -0,0 +1,132 @@
+package docs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func htmlTestDefinitions() []*model.Definition {
+	return []*model.Definition{
+		{
+			Package: model.Package{Package: "store", ImportPath: "github.com/x/store", Path: "./store"},
+			Doc:     "Package store persists items. See [Store] and [model.Item].",
+			Imports: model.StringSet{
+				"store.go": {`"github.com/x/model"`},
+			},
+			Types: model.DeclarationList{
+				{
+					Kind: model.TypeKind, Name: "Store", File: "store.go", Line: 10,
+					Doc: "Store holds items.",
+					Fields: model.FieldList{
+						{Name: "Items", Type: "[]*model.Item", Tag: `json:"items"`, Doc: "Items in the store."},
+						{Name: "mu", Type: "sync.Mutex"},
+					},
+				},
+			},
+			Funcs: model.DeclarationList{
+				{Kind: model.FuncKind, Name: "New", File: "store.go", Line: 20, Signature: "New () *Store", Doc: "New creates a Store."},
+				{Kind: model.FuncKind, Name: "Get", Receiver: "*Store", File: "store.go", Line: 30, Signature: "Get (id string) *model.Item"},
+				{Kind: model.FuncKind, Name: "get", File: "store.go", Line: 40, Signature: "get (id string) <-chan bool"},
+			},
+		},
+		{
+			Package: model.Package{Package: "model", ImportPath: "github.com/x/model", Path: "./model"},
+			Types: model.DeclarationList{
+				{Kind: model.TypeKind, Name: "Item", File: "item.go", Line: 5, Fields: model.FieldList{{Name: "ID", Type: "string"}}},
+			},
+		},
+		{
+			Package: model.Package{Package: "store_test", ImportPath: "github.com/x/store_test", TestPackage: true},
+		},
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	cfg := &options{
+		out:       t.TempDir(),
+		strip:     "github.com/x",
+		sourceURL: "https://github.com/x/blob/main/{path}#L{line}",
+	}
+
+	require.NoError(t, renderHTML(cfg, htmlTestDefinitions()))
+
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(cfg.out, name))
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	index := read("index.html")
+	assert.Contains(t, index, `<a href="store.html">github.com/x/store</a>`)
+	assert.Contains(t, index, `<a href="model.html">github.com/x/model</a>`)
+	assert.NotContains(t, index, "store_test")
+
+	store := read("store.html")
+	assert.Contains(t, store, `<a href="store.Store.html">Store</a>`)
+	assert.Contains(t, store, `func New () *<a href="store.Store.html">Store</a>`)
+	assert.Contains(t, store, `See <a href="store.Store.html">Store</a> and <a href="model.Item.html">model.Item</a>.`)
+	assert.NotContains(t, store, "func get")
+	assert.Contains(t, store, `<a href="https://github.com/x/blob/main/store/store.go#L20">store.go:20</a>`)
+
+	typ := read("store.Store.html")
+	assert.Contains(t, typ, `[]*<a href="model.Item.html">model.Item</a>`)
+	assert.Contains(t, typ, "Items in the store.")
+	assert.NotContains(t, typ, "sync.Mutex")
+	assert.Contains(t, typ, `<div class="decl" id="Get">`)
+	assert.Contains(t, typ, `func (*<a href="store.Store.html">Store</a>) Get (id string) *<a href="model.Item.html">model.Item</a>`)
+
+	search := read("search-index.js")
+	assert.True(t, strings.HasPrefix(search, "var searchIndex = ["))
+	assert.Contains(t, search, `"name":"store.Store.Get","kind":"method","package":"github.com/x/store","url":"store.Store.html#Get"`)
+
+	for _, asset := range []string{"model.Item.html", "style.css", "search.js"} {
+		assert.FileExists(t, filepath.Join(cfg.out, asset))
+	}
+}
+
+func TestRenderHTML_Generics(t *testing.T) {
+	cfg := &options{
+		out:   t.TempDir(),
+		strip: "github.com/x",
+	}
+
+	defs := []*model.Definition{
+		{
+			Package: model.Package{Package: "pool", ImportPath: "github.com/x/pool", Path: "./pool"},
+			Types: model.DeclarationList{
+				{Kind: model.TypeKind, Name: "Reseter", Type: "interface", File: "pool.go", Fields: model.FieldList{{Name: "Reset", Type: "Reset ()"}}},
+				{
+					Kind: model.TypeKind, Name: "Allocator", File: "pool.go",
+					TypeParams: model.TypeParamList{{Name: "T", Constraint: "Reseter"}},
+					Fields:     model.FieldList{{Name: "pool", Type: "sync.Pool"}},
+				},
+			},
+			Funcs: model.DeclarationList{
+				{
+					Kind: model.FuncKind, Name: "New", File: "pool.go",
+					TypeParams: model.TypeParamList{{Name: "T", Constraint: "Reseter"}},
+					Signature:  "New[T Reseter] (newFunc func() T) *Allocator[T]",
+				},
+				{Kind: model.FuncKind, Name: "Get", Receiver: "*Allocator[T]", File: "pool.go", Signature: "Get () T"},
+			},
+		},
+	}
+
+	require.NoError(t, renderHTML(cfg, defs))
+
+	b, err := os.ReadFile(filepath.Join(cfg.out, "pool.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `func New[T <a href="pool.Reseter.html">Reseter</a>] (newFunc func() T) *<a href="pool.Allocator.html">Allocator</a>[T]`)
+
+	b, err = os.ReadFile(filepath.Join(cfg.out, "pool.Allocator.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `type <a href="pool.Allocator.html">Allocator</a>[T <a href="pool.Reseter.html">Reseter</a>] struct`)
+	assert.Contains(t, string(b), `func (*<a href="pool.Allocator.html">Allocator</a>[T]) Get () T`)
+}
@@ -246,14 +246,7 @@ func renderPlantUML(opt *options, defs []*model.Definition) error {
 					}
 				}
 
-				if len(t.Arguments) > 0 {
-					names := []string{}
-					for _, name := range t.Arguments {
-						names = append(names, name)
-					}
-					t.Arguments = names
-					name += "[" + strings.Join(names, ", ") + "]"
-				}
+				name += t.TypeParams.String()
 
 				fmt.Println(token, fmt.Sprintf("%q", namespace+name), "{")
 				for _, f := range t.Fields {
@@ -41,6 +41,17 @@ func NewDB(dsn string) (*DB, error) {
 	return db, nil
 }
 
+// NewDBWithConn initializes the schema on an existing connection. It
+// allows writing the symbol graph into a database shared with other
+// tables. Closing the returned DB closes the connection.
+func NewDBWithConn(conn *sql.DB) (*DB, error) {
+	db := &DB{conn: conn}
+	if err := db.Create(); err != nil {
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+	return db, nil
+}
+
 // Create initializes the database schema.
 func (db *DB) Create() error {
 	_, err := db.conn.Exec(schemaUp)
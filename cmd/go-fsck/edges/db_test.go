@@ -1,6 +1,7 @@
 package edges
 
 import (
+	"database/sql"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,6 +18,26 @@ func TestNewDB_Memory(t *testing.T) {
 	assert.Equal(t, 0, count)
 }
 
+func TestNewDBWithConn(t *testing.T) {
+	conn, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Exec("CREATE TABLE other (id INTEGER)")
+	require.NoError(t, err)
+
+	db, err := NewDBWithConn(conn)
+	require.NoError(t, err)
+
+	count, err := db.SymbolCount()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	var tables int
+	require.NoError(t, conn.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('other', 'symbols', 'relationships')").Scan(&tables))
+	assert.Equal(t, 3, tables)
+}
+
 func TestInsertSymbols(t *testing.T) {
 	db, err := NewDB(":memory:")
 	require.NoError(t, err)
@@ -18,140 +18,33 @@ func ExtractEdges(def *model.Definition) ([]*Edge, []*Relationship, error) {
 		return nil, nil, fmt.Errorf("definition is nil")
 	}
 
-	importPath := def.Package.Name()
-	edges := make([]*Edge, 0)
-	relationships := make([]*Relationship, 0)
-
-	// Extract types
-	for _, typeDecl := range def.Types {
-		edge := &Edge{
-			ImportPath: importPath,
-			SymbolName: typeDecl.Name,
-			SymbolKind: TypeKind,
-			IsExported: typeDecl.IsExported(),
-			File:       typeDecl.File,
-			Line:       typeDecl.Line,
-		}
-		edges = append(edges, edge)
-	}
+	return ExtractAll([]*model.Definition{def})
+}
 
-	// Extract vars
-	for _, varDecl := range def.Vars {
-		edge := &Edge{
-			ImportPath: importPath,
-			SymbolName: varDecl.Name,
-			SymbolKind: VarKind,
-			IsExported: varDecl.IsExported(),
-			File:       varDecl.File,
-			Line:       varDecl.Line,
+// ExtractAll extracts symbol edges and relationships from a list of definitions,
+// usually read from a recursive go-fsck.json.
+//
+// Symbols are collected from all definitions first, so relationships can be
+// resolved across packages. Qualified type references (`oas.OAS`) are resolved
+// with the import table of the file declaring the function. Types from packages
+// outside of the model are added as symbols without a file.
+func ExtractAll(defs []*model.Definition) ([]*Edge, []*Relationship, error) {
+	g := newGraph()
+
+	for _, def := range defs {
+		if def == nil {
+			return nil, nil, fmt.Errorf("definition is nil")
 		}
-		edges = append(edges, edge)
+		g.addSymbols(def)
 	}
 
-	// Extract consts
-	for _, constDecl := range def.Consts {
-		names := constDecl.GetNames()
-		for _, name := range names {
-			edge := &Edge{
-				ImportPath: importPath,
-				SymbolName: name,
-				SymbolKind: ConstKind,
-				IsExported: constDecl.IsExported(),
-				File:       constDecl.File,
-				Line:       constDecl.Line,
-			}
-			edges = append(edges, edge)
-		}
+	for _, def := range defs {
+		g.addRelationships(def)
 	}
 
-	// Extract funcs
-	for _, funcDecl := range def.Funcs {
-		edge := &Edge{
-			ImportPath: importPath,
-			SymbolName: funcDecl.Name,
-			Receiver:   funcDecl.Receiver,
-			SymbolKind: FuncKind,
-			IsExported: funcDecl.IsExported(),
-			File:       funcDecl.File,
-			Line:       funcDecl.Line,
-		}
-		edges = append(edges, edge)
-
-		// Extract relationships for this function
-
-		// 1. Receiver relationship
-		if funcDecl.Receiver != "" {
-			receiverType := strings.TrimLeft(funcDecl.Receiver, "*")
-			receiverEdge := findEdgeByName(edges, receiverType)
-			if receiverEdge != nil {
-				relationships = append(relationships, &Relationship{
-					From: edge,
-					To:   receiverEdge,
-					Type: ReceiverRel,
-				})
-			}
-		}
-
-		// 2. Argument type relationships
-		for _, arg := range funcDecl.Arguments {
-			argType := parseTypeReference(arg)
-			if argType != "" {
-				targetEdge := findEdgeInPackageOrExternal(edges, importPath, argType)
-				if targetEdge != nil {
-					relationships = append(relationships, &Relationship{
-						From: edge,
-						To:   targetEdge,
-						Type: ArgumentRel,
-					})
-				}
-			}
-		}
-
-		// 3. Return type relationships
-		for _, ret := range funcDecl.Returns {
-			retType := parseTypeReference(ret)
-			if retType != "" {
-				targetEdge := findEdgeInPackageOrExternal(edges, importPath, retType)
-				if targetEdge != nil {
-					relationships = append(relationships, &Relationship{
-						From: edge,
-						To:   targetEdge,
-						Type: ReturnRel,
-					})
-				}
-			}
-		}
-
-		// 4. Uses relationships (from References)
-		for _, ref := range funcDecl.References.All() {
-			refType := parseTypeReference(ref)
-			if refType != "" && refType != funcDecl.Name {
-				targetEdge := findEdgeByName(edges, refType)
-				if targetEdge != nil {
-					relationships = append(relationships, &Relationship{
-						From: edge,
-						To:   targetEdge,
-						Type: UsesRel,
-					})
-				}
-			}
-		}
+	g.addImplements(defs)
 
-		// 5. Test relationships (infer from name)
-		testTarget := inferTestTarget(funcDecl.Name)
-		if testTarget != "" {
-			targetEdge := findEdgeByName(edges, testTarget)
-			if targetEdge != nil {
-				relationships = append(relationships, &Relationship{
-					From: edge,
-					To:   targetEdge,
-					Type: TestRel,
-				})
-			}
-		}
-	}
-
-	return edges, relationships, nil
+	return g.edges, g.relationships, nil
 }
 
 // parseTypeReference extracts the type name from a type reference string.
@@ -163,45 +56,39 @@ func ExtractEdges(def *model.Definition) ([]*Edge, []*Relationship, error) {
 //	"[]*MyType" -> "MyType"
 //	"map[string]MyType" -> "MyType"
 func parseTypeReference(typeRef string) string {
-	typeRef = strings.TrimSpace(typeRef)
+	_, name := splitTypeReference(typeRef)
+	return name
+}
 
-	// Handle map types: map[K]V - take the V part
-	if strings.HasPrefix(typeRef, "map[") {
-		idx := strings.LastIndex(typeRef, "]")
-		if idx != -1 && idx < len(typeRef)-1 {
-			typeRef = typeRef[idx+1:]
-			typeRef = strings.TrimSpace(typeRef)
-		}
-	}
+// splitTypeReference extracts the package qualifier and type name
+// from a type reference string. Built-in types return empty values.
+// Examples:
+//
+//	"*MyType" -> "", "MyType"
+//	"[]*oas.OAS" -> "oas", "OAS"
+//	"...http.Handler" -> "http", "Handler"
+//	"List[T]" -> "", "List"
+//	"map[string]cache.Map[K, V]" -> "cache", "Map"
+func splitTypeReference(typeRef string) (string, string) {
+	// Remove variadics, pointers, slices, maps, channels and type arguments
+	typeRef = model.TypeRef(strings.TrimSpace(typeRef))
 
-	// Remove array/slice brackets: [], []*,  [n]
-	for strings.HasPrefix(typeRef, "[") {
-		idx := strings.Index(typeRef, "]")
-		if idx == -1 {
-			break
-		}
-		typeRef = typeRef[idx+1:]
-		typeRef = strings.TrimSpace(typeRef)
+	// Skip func, struct and interface literals
+	if typeRef == "" || strings.ContainsAny(typeRef, "({ [") {
+		return "", ""
 	}
 
-	// Remove pointer prefix
-	typeRef = strings.TrimPrefix(typeRef, "*")
-	typeRef = strings.TrimSpace(typeRef)
-
-	// Take only the last part if it contains a dot (qualified name)
-	if strings.Contains(typeRef, ".") {
-		parts := strings.Split(typeRef, ".")
-		typeRef = parts[len(parts)-1]
+	var qualifier string
+	if idx := strings.LastIndex(typeRef, "."); idx != -1 {
+		qualifier, typeRef = typeRef[:idx], typeRef[idx+1:]
 	}
 
-	typeRef = strings.TrimSpace(typeRef)
-
 	// Skip built-in types
-	if isBuiltinType(typeRef) {
-		return ""
+	if qualifier == "" && isBuiltinType(typeRef) {
+		return "", ""
 	}
 
-	return typeRef
+	return qualifier, typeRef
 }
 
 // isBuiltinType checks if a type is a Go built-in type.
@@ -216,42 +103,13 @@ func isBuiltinType(t string) bool {
 	return builtins[t]
 }
 
-// findEdgeByName finds an edge in the list by symbol name (within same package).
-func findEdgeByName(edges []*Edge, name string) *Edge {
-	for _, e := range edges {
-		if e.SymbolName == name && e.Receiver == "" {
-			return e
-		}
-	}
-	return nil
-}
-
-// findEdgeInPackageOrExternal finds an edge by type name, checking local package first.
-func findEdgeInPackageOrExternal(edges []*Edge, importPath, typeName string) *Edge {
-	// First try to find in local edges
-	for _, e := range edges {
-		if e.SymbolName == typeName && e.Receiver == "" {
-			return e
-		}
-	}
-
-	// If not found locally, create placeholder for external type
-	// This allows tracking relationships to external types
-	return &Edge{
-		ImportPath: "external",
-		SymbolName: typeName,
-		SymbolKind: TypeKind,
-		IsExported: true,
-	}
-}
-
-// inferTestTarget extracts the target symbol name from a test function name.
+// InferTestTarget extracts the target symbol name from a test function name.
 // Examples:
 //
 //	"TestMyType" -> "MyType"
 //	"TestMyFunc_Case1" -> "MyFunc"
 //	"Test_myFunc" -> "" (don't infer for unexported targets)
-func inferTestTarget(testFuncName string) string {
+func InferTestTarget(testFuncName string) string {
 	if !strings.HasPrefix(testFuncName, "Test") {
 		return ""
 	}
@@ -245,7 +245,7 @@ func TestInferTestTarget(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := inferTestTarget(tt.name)
+			result := InferTestTarget(tt.name)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
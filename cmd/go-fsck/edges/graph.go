@@ -0,0 +1,312 @@
+package edges
+
+import (
+	"go/ast"
+	"slices"
+	"strings"
+
+	"github.com/titpetric/exp/cmd/go-fsck/implements"
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// graph collects symbols and relationships across packages.
+type graph struct {
+	edges         []*Edge
+	relationships []*Relationship
+
+	// symbols indexes edges by SymbolID.
+	symbols map[string]*Edge
+	// seen holds relationship keys to avoid duplicates.
+	seen map[string]bool
+}
+
+func newGraph() *graph {
+	return &graph{
+		edges:         make([]*Edge, 0),
+		relationships: make([]*Relationship, 0),
+		symbols:       make(map[string]*Edge),
+		seen:          make(map[string]bool),
+	}
+}
+
+// addSymbol adds an edge, returning an existing edge with the same symbol ID.
+func (g *graph) addSymbol(edge *Edge) *Edge {
+	id := edge.SymbolID()
+	if existing, ok := g.symbols[id]; ok {
+		return existing
+	}
+	g.symbols[id] = edge
+	g.edges = append(g.edges, edge)
+	return edge
+}
+
+// addRelationship adds a relationship, skipping duplicates.
+// It returns the added relationship or nil.
+func (g *graph) addRelationship(from, to *Edge, relType RelationshipType) *Relationship {
+	if from == nil || to == nil {
+		return nil
+	}
+
+	key := from.SymbolID() + " " + string(relType) + " " + to.SymbolID()
+	if g.seen[key] {
+		return nil
+	}
+	g.seen[key] = true
+
+	rel := &Relationship{
+		From: from,
+		To:   to,
+		Type: relType,
+	}
+	g.relationships = append(g.relationships, rel)
+	return rel
+}
+
+// addImplements adds relationships from concrete types to the interfaces they implement.
+func (g *graph) addImplements(defs []*model.Definition) {
+	for _, iface := range implements.Analyze(defs) {
+		to := g.lookup(iface.ImportPath, iface.Name)
+		for _, impl := range iface.Implementations {
+			rel := g.addRelationship(g.lookup(impl.ImportPath, impl.Name), to, ImplementsRel)
+			if rel != nil && impl.Pointer {
+				rel.Details = `{"pointer":true}`
+			}
+		}
+	}
+}
+
+// lookup finds a symbol without a receiver in a package.
+func (g *graph) lookup(importPath, name string) *Edge {
+	if name == "" {
+		return nil
+	}
+	return g.symbols[importPath+"#"+name]
+}
+
+// lookupOrExternal finds a type symbol in a package, or adds a placeholder
+// symbol for types declared outside of the model (e.g. stdlib types).
+func (g *graph) lookupOrExternal(importPath, name string) *Edge {
+	if edge := g.lookup(importPath, name); edge != nil {
+		return edge
+	}
+	return g.addSymbol(&Edge{
+		ImportPath: importPath,
+		SymbolName: name,
+		SymbolKind: TypeKind,
+		IsExported: ast.IsExported(name),
+	})
+}
+
+// addSymbols adds all the declarations in a definition as symbols.
+func (g *graph) addSymbols(def *model.Definition) {
+	importPath := internal.DefinitionImportPath(def)
+
+	add := func(decls model.DeclarationList, kind SymbolKind) {
+		for _, decl := range decls {
+			for _, name := range decl.GetNames() {
+				if name == "" {
+					continue
+				}
+				g.addSymbol(&Edge{
+					ImportPath: importPath,
+					SymbolName: name,
+					Receiver:   decl.Receiver,
+					SymbolKind: kind,
+					IsExported: decl.IsExported() && ast.IsExported(name),
+					File:       decl.File,
+					Line:       decl.Line,
+				})
+			}
+		}
+	}
+
+	add(def.Types, TypeKind)
+	add(def.Vars, VarKind)
+	add(def.Consts, ConstKind)
+	add(def.Funcs, FuncKind)
+}
+
+// addRelationships resolves the relationships for all types and functions in a definition.
+func (g *graph) addRelationships(def *model.Definition) {
+	importPath := internal.DefinitionImportPath(def)
+
+	for _, typeDecl := range def.Types {
+		from := g.lookup(importPath, typeDecl.Name)
+		if from == nil {
+			continue
+		}
+
+		scope := newFileScope(g, importPath, def.Imports.Get(typeDecl.File))
+		typeParams := typeDecl.TypeParams.Names()
+
+		// Constraint relationships
+		for _, constraint := range typeDecl.TypeParams.Constraints() {
+			g.addRelationship(from, scope.resolveType(constraint, nil), ConstraintRel)
+		}
+
+		// Type argument relationships for instantiated field types
+		for _, field := range typeDecl.Fields {
+			g.addTypeArgs(from, scope, field.Type, field.TypeInfo, typeParams)
+		}
+	}
+
+	for _, funcDecl := range def.Funcs {
+		from := g.symbols[(&Edge{
+			ImportPath: importPath,
+			SymbolName: funcDecl.Name,
+			Receiver:   funcDecl.Receiver,
+		}).SymbolID()]
+		if from == nil {
+			continue
+		}
+
+		scope := newFileScope(g, importPath, def.Imports.Get(funcDecl.File))
+
+		// Type parameters of the func and the receiver type are not type arguments
+		typeParams := append(funcDecl.TypeParams.Names(), model.TypeArgs(funcDecl.Receiver)...)
+
+		// 1. Receiver relationship
+		if funcDecl.Receiver != "" {
+			g.addRelationship(from, g.lookup(importPath, internal.ReceiverName(funcDecl.Receiver)), ReceiverRel)
+		}
+
+		// 2. Argument type relationships
+		for i, arg := range funcDecl.Arguments {
+			info := typeInfoAt(funcDecl.ArgumentTypes, i, len(funcDecl.Arguments))
+			g.addRelationship(from, scope.resolveType(arg, info), ArgumentRel)
+			g.addTypeArgs(from, scope, arg, info, typeParams)
+		}
+
+		// 3. Return type relationships
+		for i, ret := range funcDecl.Returns {
+			info := typeInfoAt(funcDecl.ReturnTypes, i, len(funcDecl.Returns))
+			g.addRelationship(from, scope.resolveType(ret, info), ReturnRel)
+			g.addTypeArgs(from, scope, ret, info, typeParams)
+		}
+
+		// Constraint relationships
+		for _, constraint := range funcDecl.TypeParams.Constraints() {
+			g.addRelationship(from, scope.resolveType(constraint, nil), ConstraintRel)
+		}
+
+		// 4. Uses relationships (from References)
+		for _, pkgName := range funcDecl.References.Keys() {
+			refPath, ok := scope.imports[pkgName]
+			if !ok {
+				continue
+			}
+			for _, name := range funcDecl.References[pkgName] {
+				g.addRelationship(from, g.lookup(refPath, name), UsesRel)
+			}
+		}
+
+		// 5. Test relationships (infer from name)
+		if testTarget := InferTestTarget(funcDecl.Name); testTarget != "" {
+			target := g.lookup(importPath, testTarget)
+			if target == nil {
+				// black box tests live in the `_test` package
+				target = g.lookup(strings.TrimSuffix(importPath, "_test"), testTarget)
+			}
+			g.addRelationship(from, target, TestRel)
+		}
+	}
+}
+
+// addTypeArgs adds relationships to the type arguments of an instantiated
+// generic type, e.g. `Set[model.Item]`, including nested instantiations.
+// Type arguments naming a type parameter in scope are skipped.
+func (g *graph) addTypeArgs(from *Edge, scope *fileScope, typeRef string, info *model.TypeInfo, typeParams []string) {
+	if info != nil {
+		ref := info.Ref()
+		if ref == nil {
+			return
+		}
+		for _, arg := range ref.TypeArgs {
+			g.addRelationship(from, scope.resolveType("", arg), TypeArgRel)
+			g.addTypeArgs(from, scope, "", arg, typeParams)
+		}
+		return
+	}
+
+	for _, arg := range model.TypeArgs(typeRef) {
+		if slices.Contains(typeParams, model.TypeRef(arg)) {
+			continue
+		}
+		g.addRelationship(from, scope.resolveType(arg, nil), TypeArgRel)
+		g.addTypeArgs(from, scope, arg, nil, typeParams)
+	}
+}
+
+// typeInfoAt returns the typed counterpart of a string value, if the
+// model was extracted with `--typed` and the values line up.
+func typeInfoAt(list []*model.TypeInfo, index, count int) *model.TypeInfo {
+	if len(list) != count {
+		return nil
+	}
+	return list[index]
+}
+
+// fileScope resolves type references in the context of a single file.
+type fileScope struct {
+	graph      *graph
+	importPath string
+
+	// imports maps the package name (or alias) to the import path.
+	imports map[string]string
+	// dotImports lists the import paths imported with `.`.
+	dotImports []string
+}
+
+func newFileScope(g *graph, importPath string, imports []string) *fileScope {
+	scope := &fileScope{
+		graph:      g,
+		importPath: importPath,
+	}
+	scope.imports, _ = model.StringSet{}.Map(imports)
+
+	for _, imported := range imports {
+		if strings.HasPrefix(imported, ". ") {
+			scope.dotImports = append(scope.dotImports, strings.Trim(imported[2:], `"`))
+		}
+	}
+	return scope
+}
+
+// resolveType resolves a type reference to a symbol. If type information
+// is available, it's used instead of parsing the type string.
+func (s *fileScope) resolveType(typeRef string, info *model.TypeInfo) *Edge {
+	if info != nil {
+		ref := info.Ref()
+		if ref == nil {
+			return nil
+		}
+		return s.graph.lookupOrExternal(ref.ImportPath, ref.Name)
+	}
+
+	qualifier, name := splitTypeReference(typeRef)
+	if name == "" {
+		return nil
+	}
+
+	if qualifier != "" {
+		importPath, ok := s.imports[qualifier]
+		if !ok {
+			importPath = qualifier
+		}
+		return s.graph.lookupOrExternal(importPath, name)
+	}
+
+	if edge := s.graph.lookup(s.importPath, name); edge != nil {
+		return edge
+	}
+
+	for _, importPath := range s.dotImports {
+		if edge := s.graph.lookup(importPath, name); edge != nil {
+			return edge
+		}
+	}
+
+	// Unresolved local names are usually type parameters.
+	return nil
+}
@@ -0,0 +1,246 @@
+package edges
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func TestExtractAll_CrossPackage(t *testing.T) {
+	oas := &model.Definition{
+		Package: newTestPackage("github.com/x/oas"),
+		Types: model.DeclarationList{
+			&model.Declaration{Kind: model.TypeKind, Name: "OAS", File: "oas.go", Line: 5},
+		},
+		Funcs: model.DeclarationList{
+			&model.Declaration{Kind: model.FuncKind, Name: "Load", File: "oas.go", Line: 10},
+		},
+	}
+
+	api := &model.Definition{
+		Package: newTestPackage("github.com/x/api"),
+		Imports: model.StringSet{
+			"api.go": []string{`"context"`, `spec "github.com/x/oas"`},
+		},
+		Types: model.DeclarationList{
+			&model.Declaration{Kind: model.TypeKind, Name: "Service", File: "api.go", Line: 5},
+		},
+		Funcs: model.DeclarationList{
+			&model.Declaration{
+				Kind:      model.FuncKind,
+				Name:      "Spec",
+				Receiver:  "*Service",
+				File:      "api.go",
+				Line:      10,
+				Arguments: []string{"context.Context"},
+				Returns:   []string{"*spec.OAS", "error"},
+				References: model.StringSet{
+					"spec": []string{"Load"},
+				},
+			},
+		},
+	}
+
+	edges, rels, err := ExtractAll([]*model.Definition{oas, api})
+	require.NoError(t, err)
+
+	// OAS, Load, Service, Service.Spec and the external context.Context
+	assert.Len(t, edges, 5)
+
+	receiver := findRelsByType(rels, ReceiverRel)
+	require.Len(t, receiver, 1)
+	assert.Equal(t, "github.com/x/api#Service", receiver[0].To.SymbolID())
+
+	args := findRelsByType(rels, ArgumentRel)
+	require.Len(t, args, 1)
+	assert.Equal(t, "context#Context", args[0].To.SymbolID())
+	assert.Empty(t, args[0].To.File)
+
+	returns := findRelsByType(rels, ReturnRel)
+	require.Len(t, returns, 1)
+	assert.Equal(t, "github.com/x/oas#OAS", returns[0].To.SymbolID())
+	assert.Equal(t, "oas.go", returns[0].To.File)
+
+	uses := findRelsByType(rels, UsesRel)
+	require.Len(t, uses, 1)
+	assert.Equal(t, "github.com/x/oas#Load", uses[0].To.SymbolID())
+}
+
+func TestExtractAll_Typed(t *testing.T) {
+	api := &model.Definition{
+		Package: newTestPackage("github.com/x/api"),
+		Imports: model.StringSet{
+			"api.go": []string{`. "github.com/x/oas"`},
+		},
+		Funcs: model.DeclarationList{
+			&model.Declaration{
+				Kind:    model.FuncKind,
+				Name:    "New",
+				File:    "api.go",
+				Returns: []string{"*OAS"},
+				ReturnTypes: []*model.TypeInfo{
+					{
+						Type: "*github.com/x/oas.OAS",
+						Kind: "pointer",
+						Elem: &model.TypeInfo{Type: "github.com/x/oas.OAS", ImportPath: "github.com/x/oas", Name: "OAS", Kind: "struct"},
+					},
+				},
+			},
+		},
+	}
+
+	_, rels, err := ExtractAll([]*model.Definition{api})
+	require.NoError(t, err)
+
+	returns := findRelsByType(rels, ReturnRel)
+	require.Len(t, returns, 1)
+	assert.Equal(t, "github.com/x/oas#OAS", returns[0].To.SymbolID())
+}
+
+func TestExtractAll_BlackBoxTest(t *testing.T) {
+	pkg := &model.Definition{
+		Package: newTestPackage("github.com/x/oas"),
+		Funcs: model.DeclarationList{
+			&model.Declaration{Kind: model.FuncKind, Name: "Load", File: "oas.go"},
+		},
+	}
+	tests := &model.Definition{
+		Package: newTestPackage("github.com/x/oas_test"),
+		Funcs: model.DeclarationList{
+			&model.Declaration{Kind: model.FuncKind, Name: "TestLoad", File: "oas_test.go"},
+		},
+	}
+
+	_, rels, err := ExtractAll([]*model.Definition{pkg, tests})
+	require.NoError(t, err)
+
+	testRels := findRelsByType(rels, TestRel)
+	require.Len(t, testRels, 1)
+	assert.Equal(t, "github.com/x/oas#Load", testRels[0].To.SymbolID())
+}
+
+func TestSplitTypeReference(t *testing.T) {
+	tests := []struct {
+		typeRef   string
+		qualifier string
+		name      string
+	}{
+		{"MyType", "", "MyType"},
+		{"[]*oas.OAS", "oas", "OAS"},
+		{"...http.Handler", "http", "Handler"},
+		{"map[string][]*model.Field", "model", "Field"},
+		{"List[T]", "", "List"},
+		{"map[string]cache.Map[K, V]", "cache", "Map"},
+		{"*Pair[Key[int], []V]", "", "Pair"},
+		{"chan *Event", "", "Event"},
+		{"func() error", "", ""},
+		{"[]string", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typeRef, func(t *testing.T) {
+			qualifier, name := splitTypeReference(tt.typeRef)
+			assert.Equal(t, tt.qualifier, qualifier)
+			assert.Equal(t, tt.name, name)
+		})
+	}
+}
+
+func TestExtractAll_Implements(t *testing.T) {
+	def := &model.Definition{
+		Package: newTestPackage("github.com/x/store"),
+		Types: model.DeclarationList{
+			&model.Declaration{
+				Kind: model.TypeKind, Name: "Getter", Type: "interface", File: "store.go",
+				Fields: model.FieldList{
+					{Name: "Get", Type: "Get (key string) string"},
+				},
+			},
+			&model.Declaration{Kind: model.TypeKind, Name: "Memory", File: "store.go"},
+		},
+		Funcs: model.DeclarationList{
+			&model.Declaration{Kind: model.FuncKind, Name: "Get", Receiver: "*Memory", File: "store.go", Signature: "Get (k string) string"},
+		},
+	}
+
+	_, rels, err := ExtractAll([]*model.Definition{def})
+	require.NoError(t, err)
+
+	impls := findRelsByType(rels, ImplementsRel)
+	require.Len(t, impls, 1)
+	assert.Equal(t, "github.com/x/store#Memory -[implements]-> github.com/x/store#Getter", impls[0].String())
+	assert.Equal(t, `{"pointer":true}`, impls[0].Details)
+}
+
+func TestExtractAll_Generics(t *testing.T) {
+	def := &model.Definition{
+		Package: newTestPackage("github.com/x/pool"),
+		Imports: model.StringSet{
+			"pool.go": []string{`"fmt"`},
+		},
+		Types: model.DeclarationList{
+			&model.Declaration{Kind: model.TypeKind, Name: "Reseter", Type: "interface", File: "pool.go"},
+			&model.Declaration{Kind: model.TypeKind, Name: "Item", File: "pool.go"},
+			&model.Declaration{
+				Kind: model.TypeKind, Name: "Allocator", File: "pool.go",
+				TypeParams: model.TypeParamList{{Name: "T", Constraint: "Reseter"}},
+			},
+			&model.Declaration{
+				Kind: model.TypeKind, Name: "Set", File: "pool.go",
+				TypeParams: model.TypeParamList{{Name: "K", Constraint: "comparable | fmt.Stringer"}},
+				Fields: model.FieldList{
+					{Name: "items", Type: "map[K]*Allocator[K]"},
+				},
+			},
+		},
+		Funcs: model.DeclarationList{
+			&model.Declaration{
+				Kind: model.FuncKind, Name: "New", File: "pool.go",
+				TypeParams: model.TypeParamList{{Name: "T", Constraint: "Reseter"}},
+				Arguments:  []string{"func() T"},
+				Returns:    []string{"*Allocator[T]"},
+			},
+			&model.Declaration{
+				Kind: model.FuncKind, Name: "Items", File: "pool.go",
+				Returns: []string{"map[string]*Allocator[Item]"},
+			},
+			&model.Declaration{
+				Kind: model.FuncKind, Name: "Get", Receiver: "*Allocator[T]", File: "pool.go",
+				Arguments: []string{"Set[T]"},
+			},
+		},
+	}
+
+	_, rels, err := ExtractAll([]*model.Definition{def})
+	require.NoError(t, err)
+
+	relStrings := func(relType RelationshipType) []string {
+		var result []string
+		for _, rel := range findRelsByType(rels, relType) {
+			result = append(result, rel.String())
+		}
+		return result
+	}
+
+	assert.ElementsMatch(t, []string{
+		"github.com/x/pool#Allocator -[constraint]-> github.com/x/pool#Reseter",
+		"github.com/x/pool#Set -[constraint]-> fmt#Stringer",
+		"github.com/x/pool#New -[constraint]-> github.com/x/pool#Reseter",
+	}, relStrings(ConstraintRel))
+
+	assert.ElementsMatch(t, []string{
+		"github.com/x/pool#Items -[type_arg]-> github.com/x/pool#Item",
+	}, relStrings(TypeArgRel))
+
+	assert.ElementsMatch(t, []string{
+		"github.com/x/pool#New -[return]-> github.com/x/pool#Allocator",
+		"github.com/x/pool#Items -[return]-> github.com/x/pool#Allocator",
+	}, relStrings(ReturnRel))
+
+	receiver := findRelsByType(rels, ReceiverRel)
+	require.Len(t, receiver, 1)
+	assert.Equal(t, "github.com/x/pool#Allocator", receiver[0].To.SymbolID())
+}
@@ -19,11 +19,14 @@ const (
 type RelationshipType string
 
 const (
-	ReceiverRel RelationshipType = "receiver" // Function has receiver type
-	ArgumentRel RelationshipType = "argument" // Function parameter uses type
-	ReturnRel   RelationshipType = "return"   // Function returns type
-	UsesRel     RelationshipType = "uses"     // Function body references symbol
-	TestRel     RelationshipType = "test"     // Test function covers symbol
+	ReceiverRel   RelationshipType = "receiver"   // Function has receiver type
+	ArgumentRel   RelationshipType = "argument"   // Function parameter uses type
+	ReturnRel     RelationshipType = "return"     // Function returns type
+	UsesRel       RelationshipType = "uses"       // Function body references symbol
+	TestRel       RelationshipType = "test"       // Test function covers symbol
+	ImplementsRel RelationshipType = "implements" // Type implements interface
+	ConstraintRel RelationshipType = "constraint" // Type parameter is constrained by type
+	TypeArgRel    RelationshipType = "type_arg"   // Generic type is instantiated with type
 )
 
 // Edge represents a single symbol definition in the codebase.
@@ -82,7 +85,8 @@ type Relationship struct {
 	// To is the target symbol (e.g., a type that is used).
 	To *Edge
 
-	// Type is the nature of the relationship (receiver, argument, return, uses, test).
+	// Type is the nature of the relationship (receiver, argument, return, uses, test,
+	// implements, constraint, type_arg).
 	Type RelationshipType
 
 	// Details is optional metadata as JSON string (e.g., {"index": 0} for argument position).
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 )
 
 // Options holds configuration for the edges command.
@@ -30,8 +31,10 @@ func NewOptions() *Options {
 // PrintHelp prints the help message.
 func (opts *Options) PrintHelp() {
 	fmt.Print(`Usage: go-fsck edges [options]
+       go-fsck edges query <query> [symbol...] [options]
 
 Extract symbol edges and relationships from a go-fsck model.
+Use "go-fsck edges query help" for the available graph queries.
 
 Options:
   -i string
@@ -44,3 +47,79 @@ Options:
       Show this help message
 `)
 }
+
+// QueryOptions holds configuration for the edges query command.
+type QueryOptions struct {
+	InputFile string
+	Format    string
+	Types     string
+	Depth     int
+	Exported  bool
+
+	// Args holds the query name and the symbol IDs.
+	Args []string
+}
+
+// NewQueryOptions parses command-line flags and returns QueryOptions.
+// Flags may be given before or after the positional arguments.
+func NewQueryOptions() *QueryOptions {
+	opts := &QueryOptions{}
+
+	fs := flag.NewFlagSet("edges query", flag.ContinueOnError)
+	fs.StringVar(&opts.InputFile, "i", "edges.db", "Input database file")
+	fs.StringVar(&opts.Format, "format", "text", "Output format (text, json, dot)")
+	fs.StringVar(&opts.Types, "type", "", "Relationship types to follow (csv)")
+	fs.IntVar(&opts.Depth, "depth", 0, "Impact traversal depth (0 = unlimited)")
+	fs.BoolVar(&opts.Exported, "exported", false, "Only list exported symbols (untested)")
+
+	args := os.Args[3:]
+	for {
+		if err := fs.Parse(args); err != nil || fs.NArg() == 0 {
+			break
+		}
+		opts.Args = append(opts.Args, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	return opts
+}
+
+// RelationshipTypes returns the relationship types to follow.
+func (opts *QueryOptions) RelationshipTypes() []RelationshipType {
+	var result []RelationshipType
+	for _, t := range strings.Split(opts.Types, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			result = append(result, RelationshipType(t))
+		}
+	}
+	return result
+}
+
+// PrintHelp prints the help message.
+func (opts *QueryOptions) PrintHelp() {
+	fmt.Print(`Usage: go-fsck edges query <query> [symbol...] [options]
+
+Query the symbol graph written by go-fsck edges.
+
+Symbols are given as "import_path#Name" or "import_path#Receiver.Name".
+
+Queries:
+  callers <symbol>      Symbols with a relationship to the symbol
+  callees <symbol>      Symbols the symbol has a relationship to
+  impact <symbol>       Transitive set of symbols depending on the symbol
+  path <from> <to>      Shortest path between two symbols
+  untested              Functions and types without a test relationship
+
+Options:
+  -i string
+      Input database file (default "edges.db")
+  -format string
+      Output format: text, json, dot (default "text")
+  -type string
+      Relationship types to follow, csv (receiver, argument, return, uses, test)
+  -depth int
+      Impact traversal depth, 0 is unlimited
+  -exported
+      Only list exported symbols (untested)
+`)
+}
@@ -0,0 +1,276 @@
+package edges
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// QueryResult holds the symbols and relationships returned by a graph query.
+type QueryResult struct {
+	// Query is the name of the query (callers, callees, impact, path, untested).
+	Query string
+
+	// Symbols are the symbols matched by the query.
+	Symbols []*Edge
+
+	// Relationships are the relationships traversed by the query.
+	Relationships []*Relationship `json:",omitempty"`
+}
+
+const symbolColumns = "id, import_path, symbol_name, receiver, symbol_kind, is_exported, file, line"
+
+// symbolCache keeps one *Edge per database row, so query results share pointers.
+type symbolCache map[int64]*Edge
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func (c symbolCache) scan(row rowScanner) (int64, *Edge, error) {
+	var (
+		id       int64
+		receiver sql.NullString
+		edge     = &Edge{}
+	)
+	err := row.Scan(&id, &edge.ImportPath, &edge.SymbolName, &receiver, &edge.SymbolKind, &edge.IsExported, &edge.File, &edge.Line)
+	if err != nil {
+		return 0, nil, err
+	}
+	edge.Receiver = receiver.String
+
+	if existing, ok := c[id]; ok {
+		return id, existing, nil
+	}
+	c[id] = edge
+	return id, edge, nil
+}
+
+// lookupSymbol returns the database id and edge for a symbol ID.
+func (db *DB) lookupSymbol(cache symbolCache, symbolID string) (int64, *Edge, error) {
+	importPath, symbolName, receiver, err := ParseSymbolID(symbolID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	row := db.conn.QueryRow("SELECT "+symbolColumns+" FROM symbols WHERE import_path = ? AND symbol_name = ? AND COALESCE(receiver, '') = ?", importPath, symbolName, receiver)
+	id, edge, err := cache.scan(row)
+	if err == sql.ErrNoRows {
+		return 0, nil, fmt.Errorf("symbol not found: %s", symbolID)
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to lookup symbol %s: %w", symbolID, err)
+	}
+	return id, edge, nil
+}
+
+// neighbour is a relationship with the database id of the symbol on the other end.
+type neighbour struct {
+	id  int64
+	rel *Relationship
+}
+
+// neighbours returns the relationships pointing to (incoming) or from the symbol.
+func (db *DB) neighbours(cache symbolCache, id int64, edge *Edge, incoming bool, types []RelationshipType) ([]neighbour, error) {
+	column, other := "from_id", "to_id"
+	if incoming {
+		column, other = "to_id", "from_id"
+	}
+
+	query := "SELECT r.relationship_type, COALESCE(r.details, ''), s." + strings.ReplaceAll(symbolColumns, ", ", ", s.") +
+		" FROM relationships r INNER JOIN symbols s ON s.id = r." + other +
+		" WHERE r." + column + " = ?"
+	args := []any{id}
+	if len(types) > 0 {
+		query += " AND r.relationship_type IN (?" + strings.Repeat(", ?", len(types)-1) + ")"
+		for _, t := range types {
+			args = append(args, string(t))
+		}
+	}
+	query += " ORDER BY s.import_path, s.receiver, s.symbol_name, r.relationship_type"
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query relationships: %w", err)
+	}
+	defer rows.Close()
+
+	var result []neighbour
+	for rows.Next() {
+		var (
+			relType RelationshipType
+			details string
+		)
+		otherID, otherEdge, err := cache.scan(scanPrefix{rows, []any{&relType, &details}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan relationship: %w", err)
+		}
+
+		rel := &Relationship{From: edge, To: otherEdge, Type: relType, Details: details}
+		if incoming {
+			rel.From, rel.To = otherEdge, edge
+		}
+		result = append(result, neighbour{id: otherID, rel: rel})
+	}
+	return result, rows.Err()
+}
+
+// scanPrefix scans leading columns into prefix before the symbol columns.
+type scanPrefix struct {
+	row    rowScanner
+	prefix []any
+}
+
+func (s scanPrefix) Scan(dest ...any) error {
+	return s.row.Scan(append(s.prefix, dest...)...)
+}
+
+// Callers returns the relationships pointing to the symbol.
+func (db *DB) Callers(symbolID string, types ...RelationshipType) (*QueryResult, error) {
+	return db.adjacent("callers", symbolID, true, types)
+}
+
+// Callees returns the relationships from the symbol to other symbols.
+func (db *DB) Callees(symbolID string, types ...RelationshipType) (*QueryResult, error) {
+	return db.adjacent("callees", symbolID, false, types)
+}
+
+func (db *DB) adjacent(query, symbolID string, incoming bool, types []RelationshipType) (*QueryResult, error) {
+	cache := symbolCache{}
+	id, edge, err := db.lookupSymbol(cache, symbolID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := db.neighbours(cache, id, edge, incoming, types)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &QueryResult{Query: query}
+	seen := map[int64]bool{}
+	for _, n := range list {
+		result.Relationships = append(result.Relationships, n.rel)
+		if !seen[n.id] {
+			seen[n.id] = true
+			result.Symbols = append(result.Symbols, cache[n.id])
+		}
+	}
+	return result, nil
+}
+
+// Impact returns the transitive set of symbols that depend on the symbol,
+// following incoming relationships. A depth of 0 doesn't limit traversal.
+func (db *DB) Impact(symbolID string, depth int, types ...RelationshipType) (*QueryResult, error) {
+	cache := symbolCache{}
+	id, _, err := db.lookupSymbol(cache, symbolID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &QueryResult{Query: "impact"}
+	seen := map[int64]bool{id: true}
+	queue := []int64{id}
+
+	for level := 1; len(queue) > 0 && (depth == 0 || level <= depth); level++ {
+		next := []int64{}
+		for _, current := range queue {
+			list, err := db.neighbours(cache, current, cache[current], true, types)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range list {
+				result.Relationships = append(result.Relationships, n.rel)
+				if seen[n.id] {
+					continue
+				}
+				seen[n.id] = true
+				result.Symbols = append(result.Symbols, cache[n.id])
+				next = append(next, n.id)
+			}
+		}
+		queue = next
+	}
+
+	return result, nil
+}
+
+// Path returns the shortest path between two symbols, following outgoing relationships.
+func (db *DB) Path(fromID, toID string, types ...RelationshipType) (*QueryResult, error) {
+	cache := symbolCache{}
+	from, _, err := db.lookupSymbol(cache, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, _, err := db.lookupSymbol(cache, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	// via holds the relationship used to reach a symbol.
+	via := map[int64]neighbour{}
+	seen := map[int64]bool{from: true}
+	queue := []int64{from}
+
+	for len(queue) > 0 && !seen[to] {
+		current := queue[0]
+		queue = queue[1:]
+
+		list, err := db.neighbours(cache, current, cache[current], false, types)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range list {
+			if seen[n.id] {
+				continue
+			}
+			seen[n.id] = true
+			via[n.id] = neighbour{id: current, rel: n.rel}
+			queue = append(queue, n.id)
+		}
+	}
+
+	if !seen[to] {
+		return nil, fmt.Errorf("no path from %s to %s", fromID, toID)
+	}
+
+	result := &QueryResult{Query: "path"}
+	for current := to; current != from; current = via[current].id {
+		result.Relationships = append([]*Relationship{via[current].rel}, result.Relationships...)
+	}
+	result.Symbols = append(result.Symbols, cache[from])
+	for _, rel := range result.Relationships {
+		result.Symbols = append(result.Symbols, rel.To)
+	}
+	return result, nil
+}
+
+// Untested returns the functions and types declared in the model
+// that have no incoming test relationship.
+func (db *DB) Untested(exportedOnly bool) (*QueryResult, error) {
+	query := "SELECT " + symbolColumns + " FROM symbols s" +
+		" WHERE s.file != '' AND s.file NOT LIKE '%\\_test.go' ESCAPE '\\' AND s.import_path NOT LIKE '%\\_test' ESCAPE '\\'" +
+		" AND s.symbol_kind IN ('func', 'type')" +
+		" AND NOT EXISTS (SELECT 1 FROM relationships r WHERE r.to_id = s.id AND r.relationship_type = 'test')"
+	if exportedOnly {
+		query += " AND s.is_exported = 1"
+	}
+	query += " ORDER BY s.import_path, s.receiver, s.symbol_name"
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query untested symbols: %w", err)
+	}
+	defer rows.Close()
+
+	cache := symbolCache{}
+	result := &QueryResult{Query: "untested"}
+	for rows.Next() {
+		_, edge, err := cache.scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan symbol: %w", err)
+		}
+		result.Symbols = append(result.Symbols, edge)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,79 @@
+package edges
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// RenderText writes the query result as plain text. Relationships are
+// printed one per line, or the symbols if the query has no relationships.
+func (r *QueryResult) RenderText(w io.Writer) error {
+	if len(r.Relationships) == 0 {
+		for _, symbol := range r.Symbols {
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s:%d\n", symbol.SymbolID(), symbol.SymbolKind, symbol.File, symbol.Line); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, rel := range r.Relationships {
+		if _, err := fmt.Fprintln(w, rel.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderJSON writes the query result as indented JSON.
+func (r *QueryResult) RenderJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// RenderDOT writes the query result as a graphviz digraph.
+func (r *QueryResult) RenderDOT(w io.Writer) error {
+	fmt.Fprintf(w, "digraph %s {\n", strconv.Quote(r.Query))
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box];")
+
+	seen := map[string]bool{}
+	node := func(e *Edge) {
+		id := e.SymbolID()
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		fmt.Fprintf(w, "  %s [label=%s];\n", strconv.Quote(id), strconv.Quote(e.ImportPath+"\n"+e.FullName()))
+	}
+
+	for _, symbol := range r.Symbols {
+		node(symbol)
+	}
+	for _, rel := range r.Relationships {
+		node(rel.From)
+		node(rel.To)
+	}
+	for _, rel := range r.Relationships {
+		fmt.Fprintf(w, "  %s -> %s [label=%s];\n", strconv.Quote(rel.From.SymbolID()), strconv.Quote(rel.To.SymbolID()), strconv.Quote(string(rel.Type)))
+	}
+
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// Render writes the query result in the given format (text, json, dot).
+func (r *QueryResult) Render(w io.Writer, format string) error {
+	switch format {
+	case "", "text":
+		return r.RenderText(w)
+	case "json":
+		return r.RenderJSON(w)
+	case "dot":
+		return r.RenderDOT(w)
+	}
+	return fmt.Errorf("unknown format: %q", format)
+}
@@ -0,0 +1,142 @@
+package edges
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// newQueryTestDB creates a graph where handler.Serve uses service.Get,
+// service.Get returns model.User, and service.Get is covered by a test.
+func newQueryTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	defs := []*model.Definition{
+		{
+			Package: newTestPackage("github.com/x/model"),
+			Types: model.DeclarationList{
+				{Kind: model.TypeKind, Name: "User", File: "user.go", Line: 3},
+			},
+		},
+		{
+			Package: newTestPackage("github.com/x/service"),
+			Imports: model.StringSet{"service.go": {`"github.com/x/model"`}},
+			Funcs: model.DeclarationList{
+				{Kind: model.FuncKind, Name: "Get", File: "service.go", Line: 5, Returns: []string{"*model.User"}},
+				{Kind: model.FuncKind, Name: "Delete", File: "service.go", Line: 9},
+				{Kind: model.FuncKind, Name: "TestGet", File: "service_test.go", Line: 5},
+			},
+		},
+		{
+			Package: newTestPackage("github.com/x/handler"),
+			Imports: model.StringSet{"handler.go": {`"github.com/x/service"`}},
+			Funcs: model.DeclarationList{
+				{Kind: model.FuncKind, Name: "Serve", File: "handler.go", Line: 7, References: model.StringSet{"service": {"Get"}}},
+			},
+		},
+	}
+
+	edges, rels, err := ExtractAll(defs)
+	require.NoError(t, err)
+
+	db, err := NewDB(filepath.Join(t.TempDir(), "edges.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	require.NoError(t, db.InsertAll(edges, rels))
+	return db
+}
+
+func TestDB_CallersCallees(t *testing.T) {
+	db := newQueryTestDB(t)
+
+	callers, err := db.Callers("github.com/x/service#Get")
+	require.NoError(t, err)
+	require.Len(t, callers.Symbols, 2)
+	assert.Equal(t, "github.com/x/handler#Serve", callers.Symbols[0].SymbolID())
+	assert.Equal(t, "github.com/x/service#TestGet", callers.Symbols[1].SymbolID())
+
+	callers, err = db.Callers("github.com/x/service#Get", UsesRel)
+	require.NoError(t, err)
+	require.Len(t, callers.Relationships, 1)
+
+	callees, err := db.Callees("github.com/x/service#Get")
+	require.NoError(t, err)
+	require.Len(t, callees.Relationships, 1)
+	assert.Equal(t, "github.com/x/service#Get -[return]-> github.com/x/model#User", callees.Relationships[0].String())
+
+	_, err = db.Callers("github.com/x/service#Missing")
+	assert.Error(t, err)
+}
+
+func TestDB_Impact(t *testing.T) {
+	db := newQueryTestDB(t)
+
+	impact, err := db.Impact("github.com/x/model#User", 0)
+	require.NoError(t, err)
+
+	ids := []string{}
+	for _, symbol := range impact.Symbols {
+		ids = append(ids, symbol.SymbolID())
+	}
+	assert.ElementsMatch(t, []string{
+		"github.com/x/service#Get",
+		"github.com/x/handler#Serve",
+		"github.com/x/service#TestGet",
+	}, ids)
+
+	impact, err = db.Impact("github.com/x/model#User", 1)
+	require.NoError(t, err)
+	assert.Len(t, impact.Symbols, 1)
+}
+
+func TestDB_Path(t *testing.T) {
+	db := newQueryTestDB(t)
+
+	path, err := db.Path("github.com/x/handler#Serve", "github.com/x/model#User")
+	require.NoError(t, err)
+	require.Len(t, path.Relationships, 2)
+	assert.Len(t, path.Symbols, 3)
+
+	_, err = db.Path("github.com/x/model#User", "github.com/x/handler#Serve")
+	assert.Error(t, err)
+}
+
+func TestDB_Untested(t *testing.T) {
+	db := newQueryTestDB(t)
+
+	untested, err := db.Untested(true)
+	require.NoError(t, err)
+
+	ids := []string{}
+	for _, symbol := range untested.Symbols {
+		ids = append(ids, symbol.SymbolID())
+	}
+	assert.Equal(t, []string{
+		"github.com/x/handler#Serve",
+		"github.com/x/model#User",
+		"github.com/x/service#Delete",
+	}, ids)
+}
+
+func TestQueryResult_Render(t *testing.T) {
+	db := newQueryTestDB(t)
+
+	result, err := db.Callees("github.com/x/service#Get")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, result.Render(&buf, "dot"))
+	assert.Contains(t, buf.String(), `"github.com/x/service#Get" -> "github.com/x/model#User" [label="return"];`)
+
+	buf.Reset()
+	require.NoError(t, result.Render(&buf, "json"))
+	assert.Contains(t, buf.String(), `"Query": "callees"`)
+
+	assert.Error(t, result.Render(&buf, "yaml"))
+}
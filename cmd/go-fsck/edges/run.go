@@ -5,10 +5,16 @@ import (
 	"os"
 
 	"golang.org/x/exp/slices"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
 )
 
 // Run is the entrypoint for `go-fsck edges`.
 func Run() error {
+	if len(os.Args) > 2 && os.Args[2] == "query" {
+		return RunQuery()
+	}
+
 	cfg := NewOptions()
 
 	if slices.Contains(os.Args, "help") {
@@ -20,5 +26,112 @@ func Run() error {
 }
 
 func runEdges(cfg *Options) error {
-	return fmt.Errorf("edges command not yet implemented")
+	defs, err := loader.ReadFile(cfg.InputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read model: %w", err)
+	}
+
+	edges, relationships, err := ExtractAll(defs)
+	if err != nil {
+		return fmt.Errorf("failed to extract edges: %w", err)
+	}
+
+	// Start from an empty database, relationships are not unique.
+	if cfg.OutputFile != ":memory:" {
+		if err := os.Remove(cfg.OutputFile); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", cfg.OutputFile, err)
+		}
+	}
+
+	db, err := NewDB(cfg.OutputFile)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.InsertAll(edges, relationships); err != nil {
+		return err
+	}
+
+	if cfg.Verbose {
+		symbols, err := db.SymbolCount()
+		if err != nil {
+			return err
+		}
+		rels, err := db.RelationshipCount()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %d symbols and %d relationships from %d packages to %s\n", symbols, rels, len(defs), cfg.OutputFile)
+	}
+
+	return nil
+}
+
+// RunQuery is the entrypoint for `go-fsck edges query`.
+func RunQuery() error {
+	cfg := NewQueryOptions()
+
+	if slices.Contains(os.Args, "help") || len(cfg.Args) == 0 {
+		cfg.PrintHelp()
+		return nil
+	}
+
+	return runQuery(cfg)
+}
+
+func runQuery(cfg *QueryOptions) error {
+	if _, err := os.Stat(cfg.InputFile); err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+
+	db, err := NewDB(cfg.InputFile)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var (
+		query  = cfg.Args[0]
+		args   = cfg.Args[1:]
+		types  = cfg.RelationshipTypes()
+		result *QueryResult
+	)
+
+	expectArgs := func(n int) error {
+		if len(args) != n {
+			return fmt.Errorf("query %s expects %d symbol argument(s), got %d", query, n, len(args))
+		}
+		return nil
+	}
+
+	switch query {
+	case "callers":
+		if err = expectArgs(1); err == nil {
+			result, err = db.Callers(args[0], types...)
+		}
+	case "callees":
+		if err = expectArgs(1); err == nil {
+			result, err = db.Callees(args[0], types...)
+		}
+	case "impact":
+		if err = expectArgs(1); err == nil {
+			result, err = db.Impact(args[0], cfg.Depth, types...)
+		}
+	case "path":
+		if err = expectArgs(2); err == nil {
+			result, err = db.Path(args[0], args[1], types...)
+		}
+	case "untested":
+		if err = expectArgs(0); err == nil {
+			result, err = db.Untested(cfg.Exported)
+		}
+	default:
+		err = fmt.Errorf("unknown query: %q", query)
+	}
+	if err != nil {
+		return err
+	}
+
+	return result.Render(os.Stdout, cfg.Format)
 }
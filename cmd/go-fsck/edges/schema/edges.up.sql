@@ -16,7 +16,7 @@ CREATE TABLE IF NOT EXISTS relationships (
   id INTEGER PRIMARY KEY AUTOINCREMENT,
   from_id INTEGER NOT NULL,
   to_id INTEGER NOT NULL,
-  relationship_type TEXT NOT NULL,  -- one of: receiver, argument, return, uses, test
+  relationship_type TEXT NOT NULL,  -- one of: receiver, argument, return, uses, test, implements, constraint, type_arg
   details TEXT,                     -- JSON metadata (e.g., {"index": 0} for argument position)
   FOREIGN KEY(from_id) REFERENCES symbols(id) ON DELETE CASCADE,
   FOREIGN KEY(to_id) REFERENCES symbols(id) ON DELETE CASCADE
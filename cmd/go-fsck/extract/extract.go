@@ -4,26 +4,29 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
 	"strings"
 
+	"golang.org/x/exp/slices"
+
 	"github.com/titpetric/exp/cmd/go-fsck/internal"
 	"github.com/titpetric/exp/cmd/go-fsck/internal/telemetry"
 	"github.com/titpetric/exp/cmd/go-fsck/model"
 	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
 )
 
-func loadModuleTree(ctx context.Context, cfg *options, modules []internal.Module, pattern string) ([]*model.Definition, error) {
+func loadModuleTree(ctx context.Context, cfg *options, modules []internal.Module, pattern string, cache *loader.Cache) ([]*model.Definition, error) {
 	result := []*model.Definition{}
 
 	// Get absolute path of source for comparison
 	absSourcePath, _ := filepath.Abs(cfg.sourcePath)
 
 	for _, m := range modules {
-		defs, err := walkPackage(ctx, m.Dir, pattern, cfg.includeTests, cfg.verbose)
+		defs, err := walkPackage(ctx, m.Dir, pattern, cfg.loaderOptions(), cache)
 		if err != nil {
 			return nil, err
 		}
@@ -52,6 +55,11 @@ func getDefinitions(cfg *options) ([]*model.Definition, error) {
 		pattern = "./..."
 	}
 
+	cache, err := cfg.cache()
+	if err != nil {
+		return nil, err
+	}
+
 	defs := []*model.Definition{}
 
 	if pattern == "./..." {
@@ -60,7 +68,7 @@ func getDefinitions(cfg *options) ([]*model.Definition, error) {
 			return nil, err
 		}
 
-		d, err := loadModuleTree(ctx, cfg, modules, pattern)
+		d, err := loadModuleTree(ctx, cfg, modules, pattern, cache)
 		if err != nil {
 			return nil, err
 		}
@@ -68,7 +76,7 @@ func getDefinitions(cfg *options) ([]*model.Definition, error) {
 	}
 
 	if pattern == "." {
-		d, err := walkPackage(ctx, cfg.sourcePath, pattern, cfg.includeTests, cfg.verbose)
+		d, err := walkPackage(ctx, cfg.sourcePath, pattern, cfg.loaderOptions(), cache)
 		if err != nil {
 			return nil, err
 		}
@@ -92,60 +100,155 @@ func getDefinitions(cfg *options) ([]*model.Definition, error) {
 	return defs, nil
 }
 
-func walkPackage(ctx context.Context, sourcePath string, pattern string, includeTests bool, verbose bool) ([]*model.Definition, error) {
+func walkPackage(ctx context.Context, sourcePath string, pattern string, opts *loader.Options, cache *loader.Cache) ([]*model.Definition, error) {
 	defer telemetry.Start("extract.walkPackage " + sourcePath).End()
 	defer runtime.GC()
 
-	// fmt.Println("walking:", sourcePath, pattern, "tests", includeTests, "verbose", verbose)
+	if cache != nil {
+		return walkPackageCached(ctx, sourcePath, pattern, opts, cache)
+	}
+
+	// fmt.Println("walking:", sourcePath, pattern, "tests", opts.IncludeTests, "verbose", opts.Verbose)
 	packages, err := internal.ListPackages(sourcePath, pattern)
 	if err != nil {
 		return nil, err
 	}
 
+	if !opts.IncludeTests {
+		packages = slices.DeleteFunc(packages, func(pkg *model.Package) bool {
+			return pkg.TestPackage
+		})
+	}
+
+	results, err := loader.LoadAll(ctx, packages, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	defs := []*model.Definition{}
-	for _, pkg := range packages {
+	for i, pkg := range packages {
+		defs = append(defs, packageDefinitions(pkg, results[i])...)
+	}
+	return defs, nil
+}
+
+// walkPackageCached lists the packages without loading them, and only
+// loads the packages which aren't in the cache.
+func walkPackageCached(ctx context.Context, sourcePath string, pattern string, opts *loader.Options, cache *loader.Cache) ([]*model.Definition, error) {
+	sourcePath, err := filepath.Abs(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	packages, err := internal.ListPackageFiles(sourcePath, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		loaded = make([][]*model.Definition, len(packages))
+		keys   = make([]string, len(packages))
+		misses = []string{}
+	)
+
+	for i, pkg := range packages {
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
 		}
 
-		if !includeTests {
-			if pkg.TestPackage {
-				continue
-			}
+		if !opts.IncludeTests && pkg.TestPackage {
+			continue
+		}
+
+		key, err := cache.Key(pkg, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if d, ok := cache.Get(key); ok {
+			loaded[i] = d
+			continue
 		}
 
-		//span := telemetry.Start("extract.walkPackage " + pkg.ImportPath)
+		keys[i] = key
+		if !slices.Contains(misses, pkg.Path) {
+			misses = append(misses, pkg.Path)
+		}
+	}
 
-		d, err := loader.Load(pkg, includeTests, false)
+	if opts.Verbose {
+		log.Printf("Cache: %d packages, %d folders to load", len(packages), len(misses))
+	}
+
+	if len(misses) > 0 {
+		fresh, err := internal.LoadPackages(sourcePath, misses...)
 		if err != nil {
 			return nil, err
 		}
 
-		// White box test include whole package scope. Lie.
-		if pkg.TestPackage {
-			if !strings.HasSuffix(pkg.Package, "_test") {
-				pkg.Package += "_test"
-				pkg.ImportPath += "_test" // More about the binary, it's test scope even if not black box.
-			}
+		byID := make(map[string]*model.Package, len(fresh))
+		for _, pkg := range fresh {
+			byID[pkg.ID] = pkg
 		}
 
-		for _, v := range d {
-			v.Package.ID = pkg.ID
-			v.Package.ImportPath = pkg.ImportPath
-			v.Package.Path = pkg.Path
-			v.Package.Package = pkg.Package
-			v.Package.TestPackage = pkg.TestPackage
+		var (
+			missIndex = []int{}
+			missing   = []*model.Package{}
+		)
+		for i, pkg := range packages {
+			if keys[i] == "" {
+				continue
+			}
+
+			in, ok := byID[pkg.ID]
+			if !ok {
+				return nil, fmt.Errorf("Error loading package %s: not found", pkg.ID)
+			}
+			missIndex = append(missIndex, i)
+			missing = append(missing, in)
 		}
 
-		defs = append(defs, d...)
+		results, err := loader.LoadAll(ctx, missing, opts)
+		if err != nil {
+			return nil, err
+		}
 
-		runtime.GC() // add some gc pressure
+		for j, i := range missIndex {
+			if err := cache.Put(keys[i], results[j]); err != nil {
+				return nil, err
+			}
+			loaded[i] = results[j]
+		}
+	}
 
-		//span.End()
+	defs := []*model.Definition{}
+	for i, pkg := range packages {
+		defs = append(defs, packageDefinitions(pkg, loaded[i])...)
 	}
 	return defs, nil
 }
 
+// packageDefinitions attaches the package information to definitions.
+func packageDefinitions(pkg *model.Package, defs []*model.Definition) []*model.Definition {
+	// White box test include whole package scope. Lie.
+	if pkg.TestPackage {
+		if !strings.HasSuffix(pkg.Package, "_test") {
+			pkg.Package += "_test"
+			pkg.ImportPath += "_test" // More about the binary, it's test scope even if not black box.
+		}
+	}
+
+	for _, v := range defs {
+		v.Package.ID = pkg.ID
+		v.Package.ImportPath = pkg.ImportPath
+		v.Package.Path = pkg.Path
+		v.Package.Package = pkg.Package
+		v.Package.TestPackage = pkg.TestPackage
+	}
+
+	return defs
+}
+
 func extract(cfg *options) error {
 	definitions, err := getDefinitions(cfg)
 	if err != nil {
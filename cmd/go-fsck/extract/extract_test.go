@@ -0,0 +1,63 @@
+package extract
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDefinitions_build(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":               "module example.com/app\n\ngo 1.21\n",
+		"store.go":             "package store\n\nfunc Open() {}\n",
+		"store_windows.go":     "package store\n\nfunc openWindows() {}\n",
+		"store_integration.go": "//go:build integration\n\npackage store\n\nfunc openIntegration() {}\n",
+	}
+	for name, contents := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644))
+	}
+
+	// The package loading changes the working directory.
+	t.Chdir(dir)
+
+	funcs := func(cfg *options) []string {
+		t.Helper()
+
+		cfg.sourcePath = dir
+		defs, err := getDefinitions(cfg)
+		require.NoError(t, err)
+
+		var result []string
+		for _, def := range defs {
+			for _, fn := range def.Funcs {
+				result = append(result, fn.Name)
+			}
+		}
+		return result
+	}
+
+	cacheDir := t.TempDir()
+
+	testCases := []struct {
+		name string
+		cfg  *options
+		want []string
+	}{
+		{"linux", &options{goos: "linux", goarch: "amd64"}, []string{"Open"}},
+		{"windows", &options{goos: "windows", goarch: "amd64"}, []string{"Open", "openWindows"}},
+		{"tags", &options{goos: "linux", goarch: "amd64", tags: []string{"integration"}}, []string{"Open", "openIntegration"}},
+		{"all platforms", &options{allPlatforms: true}, []string{"Open", "openIntegration", "openWindows"}},
+		{"cached linux", &options{goos: "linux", goarch: "amd64", useCache: true, cacheDir: cacheDir}, []string{"Open"}},
+		{"cached windows", &options{goos: "windows", goarch: "amd64", useCache: true, cacheDir: cacheDir}, []string{"Open", "openWindows"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tc.want, funcs(tc.cfg))
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 
 	flag "github.com/spf13/pflag"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
 )
 
 type options struct {
@@ -15,10 +17,20 @@ type options struct {
 
 	includeTests   bool
 	includeSources bool
+	typed          bool
+
+	useCache bool
+	cacheDir string
+
+	goos         string
+	goarch       string
+	tags         []string
+	allPlatforms bool
 
 	prettyJSON bool
 	recursive  bool
 	verbose    bool
+	jobs       int
 }
 
 func NewOptions() *options {
@@ -36,9 +48,17 @@ func NewOptions() *options {
 	flag.StringVarP(&cfg.sourcePath, "source-path", "i", cfg.sourcePath, "source path")
 	flag.BoolVar(&cfg.includeTests, "include-tests", cfg.includeTests, "include test files")
 	flag.BoolVar(&cfg.includeSources, "include-sources", cfg.includeSources, "include sources")
+	flag.BoolVar(&cfg.typed, "typed", cfg.typed, "include go/types resolved type information")
+	flag.BoolVar(&cfg.useCache, "cache", cfg.useCache, "reuse definitions of unchanged packages from the cache")
+	flag.StringVar(&cfg.cacheDir, "cache-dir", cfg.cacheDir, "cache location (default: user cache dir)")
+	flag.StringVar(&cfg.goos, "goos", cfg.goos, "evaluate build constraints for GOOS (default: current)")
+	flag.StringVar(&cfg.goarch, "goarch", cfg.goarch, "evaluate build constraints for GOARCH (default: current)")
+	flag.StringSliceVar(&cfg.tags, "tags", cfg.tags, "build tags to satisfy")
+	flag.BoolVar(&cfg.allPlatforms, "all-platforms", cfg.allPlatforms, "include files for all platforms and tags")
 	flag.BoolVar(&cfg.prettyJSON, "pretty-json", cfg.prettyJSON, "print pretty json")
 	flag.BoolVarP(&cfg.recursive, "recursive", "r", cfg.recursive, "recurse packages")
 	flag.BoolVarP(&cfg.verbose, "verbose", "v", cfg.verbose, "verbose output")
+	flag.IntVarP(&cfg.jobs, "jobs", "j", cfg.jobs, "number of packages to load in parallel (default GOMAXPROCS)")
 	flag.Parse()
 
 	cfg.outputFile, _ = filepath.Abs(cfg.outputFile)
@@ -50,3 +70,39 @@ func PrintHelp() {
 	fmt.Printf("Usage: %s extract <sourcePath> <options>:\n\n", path.Base(os.Args[0]))
 	flag.PrintDefaults()
 }
+
+func (cfg *options) loaderOptions() *loader.Options {
+	return &loader.Options{
+		IncludeTests: cfg.includeTests,
+		Verbose:      cfg.verbose,
+		Typed:        cfg.typed,
+		Jobs:         cfg.jobs,
+		Build:        cfg.build(),
+	}
+}
+
+func (cfg *options) build() *loader.BuildContext {
+	result := loader.NewBuildContext(cfg.goos, cfg.goarch, cfg.tags)
+	result.All = cfg.allPlatforms
+	return result
+}
+
+// cache returns the extraction cache, or nil if caching is disabled.
+// The cache isn't used with `--typed`, as the type information depends
+// on the imported packages and not only on the package source.
+func (cfg *options) cache() (*loader.Cache, error) {
+	if !cfg.useCache || cfg.typed {
+		return nil, nil
+	}
+
+	dir := cfg.cacheDir
+	if dir == "" {
+		var err error
+		dir, err = loader.DefaultCacheDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return loader.NewCache(dir)
+}
@@ -0,0 +1,287 @@
+// Package implements computes interface satisfaction over a go-fsck model.
+package implements
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// Interface holds an interface type and the types that implement it.
+type Interface struct {
+	ImportPath string
+	Name       string
+	File       string
+	Line       int
+
+	// Methods holds the normalized method set, including embedded interfaces.
+	Methods []string `json:",omitempty"`
+
+	// Unresolved lists embedded interfaces declared outside the model.
+	// Implementations are not computed if any are present.
+	Unresolved []string `json:",omitempty"`
+
+	// Implementations lists the concrete types satisfying the interface.
+	Implementations []*Implementation
+}
+
+// String returns the qualified interface name.
+func (i *Interface) String() string {
+	return i.ImportPath + "." + i.Name
+}
+
+// Resolved returns true if the full method set of the interface is known.
+func (i *Interface) Resolved() bool {
+	return len(i.Unresolved) == 0
+}
+
+// Implementation holds a concrete type implementing an interface.
+type Implementation struct {
+	ImportPath string
+	Name       string
+	File       string
+	Line       int
+
+	// Pointer is true when only the pointer type (*T) implements the interface.
+	Pointer bool `json:",omitempty"`
+}
+
+// String returns the qualified type name, with a `*` for pointer receivers.
+func (i *Implementation) String() string {
+	if i.Pointer {
+		return "*" + i.ImportPath + "." + i.Name
+	}
+	return i.ImportPath + "." + i.Name
+}
+
+// method is a normalized method signature.
+type method struct {
+	name      string
+	signature string
+}
+
+func (m method) String() string {
+	return m.name + " " + m.signature
+}
+
+// concrete holds a non-interface type and its method sets.
+type concrete struct {
+	impl *Implementation
+
+	// value holds methods with a value receiver (T),
+	// pointer holds methods with a pointer receiver (*T).
+	value   map[string]string
+	pointer map[string]string
+}
+
+// iface holds an interface as declared, before embeds are resolved.
+type iface struct {
+	out     *Interface
+	methods []method
+	embeds  []string
+
+	// constraint is true for type set interfaces (`~int | ~float64`).
+	constraint bool
+
+	// resolved holds the method set including embeds, once resolved.
+	resolved []method
+	done     bool
+}
+
+// Analyze computes the implementations for every interface in the model.
+//
+// A type implements an interface if its method set contains all the
+// interface methods. Pointer receiver methods are only in the method
+// set of *T, in which case the implementation is marked with Pointer.
+// Empty interfaces and type set constraints are skipped. Methods promoted
+// from embedded struct fields are not considered.
+func Analyze(defs []*model.Definition) []*Interface {
+	var (
+		interfaces = map[string]*iface{}
+		types      = map[string]*concrete{}
+		typeOrder  = []string{}
+	)
+
+	for _, def := range defs {
+		pkgPath := internal.DefinitionImportPath(def)
+
+		for _, decl := range def.Types {
+			if decl.Name == "" || decl.IsTestScope() {
+				continue
+			}
+
+			key := pkgPath + "." + decl.Name
+			if decl.Type == "interface" {
+				interfaces[key] = newIface(def, decl)
+				continue
+			}
+
+			if _, ok := types[key]; !ok {
+				typeOrder = append(typeOrder, key)
+				types[key] = &concrete{
+					impl: &Implementation{
+						ImportPath: pkgPath,
+						Name:       decl.Name,
+						File:       decl.File,
+						Line:       decl.Line,
+					},
+					value:   map[string]string{},
+					pointer: map[string]string{},
+				}
+			}
+		}
+	}
+
+	for _, def := range defs {
+		pkgPath := internal.DefinitionImportPath(def)
+
+		for _, fn := range def.Funcs {
+			if fn.Receiver == "" || fn.IsTestScope() {
+				continue
+			}
+
+			t, ok := types[pkgPath+"."+internal.ReceiverName(fn.Receiver)]
+			if !ok {
+				continue
+			}
+
+			name, signature, ok := newScope(def, fn.File).signature(fn.Signature)
+			if !ok {
+				continue
+			}
+
+			if strings.HasPrefix(fn.Receiver, "*") {
+				t.pointer[name] = signature
+			} else {
+				t.value[name] = signature
+			}
+		}
+	}
+
+	result := []*Interface{}
+	for _, in := range interfaces {
+		methods := in.resolve(interfaces, map[string]bool{})
+		if in.constraint || (len(methods) == 0 && in.out.Resolved()) {
+			continue
+		}
+
+		for _, m := range methods {
+			in.out.Methods = append(in.out.Methods, m.String())
+		}
+		sort.Strings(in.out.Methods)
+
+		if in.out.Resolved() {
+			for _, key := range typeOrder {
+				if impl := implements(types[key], in.out.ImportPath, methods); impl != nil {
+					in.out.Implementations = append(in.out.Implementations, impl)
+				}
+			}
+		}
+
+		sort.Slice(in.out.Implementations, func(i, j int) bool {
+			return in.out.Implementations[i].String() < in.out.Implementations[j].String()
+		})
+
+		result = append(result, in.out)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].String() < result[j].String()
+	})
+
+	return result
+}
+
+func newIface(def *model.Definition, decl *model.Declaration) *iface {
+	sc := newScope(def, decl.File)
+	result := &iface{
+		out: &Interface{
+			ImportPath: internal.DefinitionImportPath(def),
+			Name:       decl.Name,
+			File:       decl.File,
+			Line:       decl.Line,
+		},
+	}
+
+	for _, field := range decl.Fields {
+		if field.Name != "" {
+			name, signature, ok := sc.signature(field.Type)
+			if ok {
+				result.methods = append(result.methods, method{name, signature})
+			}
+			continue
+		}
+
+		switch {
+		case field.Embed == "":
+			// Models extracted before embeds were recorded.
+			result.out.Unresolved = append(result.out.Unresolved, "(embedded)")
+		case strings.ContainsAny(field.Embed, "~|") || isBuiltin(field.Embed):
+			result.constraint = true
+		default:
+			result.embeds = append(result.embeds, sc.qualifyName(field.Embed))
+		}
+	}
+
+	return result
+}
+
+// resolve returns the method set of an interface including embeds.
+// Embedded interfaces outside of the model are recorded as unresolved.
+func (in *iface) resolve(interfaces map[string]*iface, visiting map[string]bool) []method {
+	if in.done {
+		return in.resolved
+	}
+
+	key := in.out.String()
+	if visiting[key] {
+		return nil
+	}
+	visiting[key] = true
+
+	result := append([]method{}, in.methods...)
+	for _, embed := range in.embeds {
+		embedded, ok := interfaces[embed]
+		if !ok {
+			in.out.Unresolved = append(in.out.Unresolved, embed)
+			continue
+		}
+
+		result = append(result, embedded.resolve(interfaces, visiting)...)
+		in.out.Unresolved = append(in.out.Unresolved, embedded.out.Unresolved...)
+		if embedded.constraint {
+			in.constraint = true
+		}
+	}
+
+	in.resolved, in.done = result, true
+	return result
+}
+
+// implements checks the value and pointer method sets of a type against
+// the interface methods, returning nil if the type doesn't implement it.
+func implements(t *concrete, ifacePath string, methods []method) *Implementation {
+	pointer := false
+	for _, m := range methods {
+		// unexported methods can only be implemented in the same package
+		if !ast.IsExported(m.name) && t.impl.ImportPath != ifacePath {
+			return nil
+		}
+
+		if sig, ok := t.value[m.name]; ok && sig == m.signature {
+			continue
+		}
+		if sig, ok := t.pointer[m.name]; ok && sig == m.signature {
+			pointer = true
+			continue
+		}
+		return nil
+	}
+
+	impl := *t.impl
+	impl.Pointer = pointer
+	return &impl
+}
@@ -0,0 +1,127 @@
+package implements
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func testDefinitions() []*model.Definition {
+	return []*model.Definition{
+		{
+			Package: model.Package{Package: "store", ImportPath: "github.com/x/store"},
+			Imports: model.StringSet{
+				"store.go": {`"context"`},
+			},
+			Types: model.DeclarationList{
+				{
+					Kind: model.TypeKind, Name: "Reader", Type: "interface", File: "store.go",
+					Fields: model.FieldList{
+						{Name: "Get", Type: "Get (ctx context.Context, id string) (*Item, error)"},
+					},
+				},
+				{
+					Kind: model.TypeKind, Name: "ReadWriter", Type: "interface", File: "store.go",
+					Fields: model.FieldList{
+						{Type: "interface", Embed: "Reader"},
+						{Name: "Set", Type: "Set (ctx context.Context, item *Item) error"},
+					},
+				},
+				{
+					Kind: model.TypeKind, Name: "Closer", Type: "interface", File: "store.go",
+					Fields: model.FieldList{
+						{Type: "interface", Embed: "io.Closer"},
+					},
+				},
+				{
+					Kind: model.TypeKind, Name: "Number", Type: "interface", File: "store.go",
+					Fields: model.FieldList{
+						{Type: "interface", Embed: "~int | ~float64"},
+					},
+				},
+				{
+					Kind: model.TypeKind, Name: "Deleter", Type: "interface", File: "store.go",
+					Fields: model.FieldList{
+						{Name: "Delete", Type: "Delete (string) error"},
+					},
+				},
+				{Kind: model.TypeKind, Name: "Item", File: "store.go"},
+			},
+		},
+		{
+			Package: model.Package{Package: "memory", ImportPath: "github.com/x/memory"},
+			Imports: model.StringSet{
+				"memory.go": {`stdctx "context"`, `"github.com/x/store"`},
+			},
+			Types: model.DeclarationList{
+				{Kind: model.TypeKind, Name: "Memory", File: "memory.go"},
+				{Kind: model.TypeKind, Name: "Cache", File: "memory.go"},
+			},
+			Funcs: model.DeclarationList{
+				{Kind: model.FuncKind, Name: "Get", Receiver: "Memory", File: "memory.go", Signature: "Get (_ stdctx.Context, key string) (*store.Item, error)"},
+				{Kind: model.FuncKind, Name: "Set", Receiver: "*Memory", File: "memory.go", Signature: "Set (ctx stdctx.Context, item *store.Item) error"},
+				{Kind: model.FuncKind, Name: "Get", Receiver: "*Cache", File: "memory.go", Signature: "Get (ctx stdctx.Context, key string) (*store.Item, error)"},
+			},
+		},
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	result := Analyze(testDefinitions())
+
+	byName := map[string]*Interface{}
+	for _, in := range result {
+		byName[in.Name] = in
+	}
+
+	// Empty and constraint interfaces are skipped.
+	assert.NotContains(t, byName, "Number")
+
+	reader := byName["Reader"]
+	require.NotNil(t, reader)
+	require.Len(t, reader.Implementations, 2)
+	assert.Equal(t, "*github.com/x/memory.Cache", reader.Implementations[0].String())
+	assert.Equal(t, "github.com/x/memory.Memory", reader.Implementations[1].String())
+
+	readWriter := byName["ReadWriter"]
+	require.NotNil(t, readWriter)
+	assert.Len(t, readWriter.Methods, 2)
+	require.Len(t, readWriter.Implementations, 1)
+	assert.True(t, readWriter.Implementations[0].Pointer)
+
+	closer := byName["Closer"]
+	require.NotNil(t, closer)
+	assert.False(t, closer.Resolved())
+	assert.Equal(t, []string{"io.Closer"}, closer.Unresolved)
+
+	deleter := byName["Deleter"]
+	require.NotNil(t, deleter)
+	assert.Empty(t, deleter.Implementations)
+}
+
+func TestFilter(t *testing.T) {
+	result := Analyze(testDefinitions())
+
+	single := filter(&options{single: true}, result)
+	require.Len(t, single, 1)
+	assert.Equal(t, "ReadWriter", single[0].Name)
+
+	unimplemented := filter(&options{unimplemented: true}, result)
+	require.Len(t, unimplemented, 1)
+	assert.Equal(t, "Deleter", unimplemented[0].Name)
+}
+
+func TestScope_Signature(t *testing.T) {
+	sc := &scope{
+		importPath: "github.com/x/store",
+		imports:    map[string]string{"ctx": "context"},
+	}
+
+	name, sig, ok := sc.signature("Get (c ctx.Context, a, b []byte, opts ...Option) (map[string]*Item, error)")
+	require.True(t, ok)
+	assert.Equal(t, "Get", name)
+	assert.Equal(t, "func(context.Context, []uint8, []uint8, ...github.com/x/store.Option) (map[string]*github.com/x/store.Item, error)", sig)
+}
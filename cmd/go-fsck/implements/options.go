@@ -0,0 +1,46 @@
+package implements
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+)
+
+type options struct {
+	inputFile string
+
+	single        bool
+	unimplemented bool
+
+	json    bool
+	verbose bool
+	args    []string
+
+	fs *internal.FlagSet
+}
+
+// NewOptions parses command-line flags and returns the implements options.
+func NewOptions() *options {
+	cfg := &options{
+		inputFile: "go-fsck.json",
+	}
+
+	cfg.fs = internal.NewFlagSet("implements")
+	cfg.fs.StringVarP(&cfg.inputFile, "input-file", "i", cfg.inputFile, "input file")
+	cfg.fs.BoolVar(&cfg.single, "single", cfg.single, "only list interfaces with a single implementation")
+	cfg.fs.BoolVar(&cfg.unimplemented, "unimplemented", cfg.unimplemented, "only list interfaces without implementations")
+	cfg.fs.BoolVar(&cfg.json, "json", cfg.json, "print results as json")
+	cfg.fs.BoolVarP(&cfg.verbose, "verbose", "v", cfg.verbose, "verbose output")
+
+	cfg.args = internal.ParseArgs(cfg.fs)
+
+	return cfg
+}
+
+// PrintHelp displays usage information for the implements command.
+func (o *options) PrintHelp() {
+	fmt.Printf("Usage: %s implements <options>:\n\n", path.Base(os.Args[0]))
+	o.fs.PrintDefaults()
+}
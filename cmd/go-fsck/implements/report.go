@@ -0,0 +1,107 @@
+package implements
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fbiville/markdown-table-formatter/pkg/markdown"
+	"golang.org/x/exp/slices"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
+)
+
+func getDefinitions(cfg *options) ([]*model.Definition, error) {
+	// Read the exported go-fsck.json data.
+	defs, err := loader.ReadFile(cfg.inputFile)
+	if err == nil {
+		return defs, nil
+	}
+
+	// list current local packages
+	packages, err := internal.ListPackages(".", "./...")
+	if err != nil {
+		return nil, err
+	}
+
+	packages = slices.DeleteFunc(packages, func(pkg *model.Package) bool {
+		return pkg.TestPackage
+	})
+
+	results, err := loader.LoadAll(context.Background(), packages, &loader.Options{
+		Verbose: cfg.verbose,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defs = []*model.Definition{}
+	for _, d := range results {
+		defs = append(defs, d...)
+	}
+
+	return defs, nil
+}
+
+// filter returns the interfaces matching the --single and --unimplemented flags.
+func filter(cfg *options, interfaces []*Interface) []*Interface {
+	if !cfg.single && !cfg.unimplemented {
+		return interfaces
+	}
+
+	result := []*Interface{}
+	for _, in := range interfaces {
+		if !in.Resolved() {
+			continue
+		}
+
+		count := len(in.Implementations)
+		if (cfg.single && count == 1) || (cfg.unimplemented && count == 0) {
+			result = append(result, in)
+		}
+	}
+	return result
+}
+
+func report(cfg *options) error {
+	defs, err := getDefinitions(cfg)
+	if err != nil {
+		return err
+	}
+
+	results := filter(cfg, Analyze(defs))
+
+	if cfg.json {
+		b, err := json.Marshal(results)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	table := [][]string{}
+	for _, result := range results {
+		impls := make([]string, 0, len(result.Implementations))
+		for _, impl := range result.Implementations {
+			impls = append(impls, impl.String())
+		}
+		if !result.Resolved() {
+			impls = append(impls, "(unresolved: "+strings.Join(result.Unresolved, ", ")+")")
+		}
+
+		table = append(table, []string{result.String(), fmt.Sprint(len(result.Implementations)), strings.Join(impls, ", ")})
+	}
+
+	t, err := markdown.NewTableFormatterBuilder().WithPrettyPrint().Build("Interface", "Count", "Implementations").Format(table)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(t)
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package implements
+
+import (
+	"os"
+
+	"golang.org/x/exp/slices"
+)
+
+// Run is the entrypoint for `go-fsck implements`.
+func Run() (err error) {
+	cfg := NewOptions()
+
+	if slices.Contains(os.Args, "help") {
+		cfg.PrintHelp()
+		return nil
+	}
+
+	return report(cfg)
+}
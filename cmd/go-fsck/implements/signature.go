@@ -0,0 +1,159 @@
+package implements
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// scope qualifies type names in the context of a source file.
+type scope struct {
+	// importPath is the package declaring the file.
+	importPath string
+	// imports maps package names (or aliases) to import paths.
+	imports map[string]string
+}
+
+func newScope(def *model.Definition, file string) *scope {
+	imports, _ := def.Imports.Map(def.Imports.Get(file))
+	return &scope{
+		importPath: internal.DefinitionImportPath(def),
+		imports:    imports,
+	}
+}
+
+// qualifyName returns the fully qualified reference for a type name,
+// e.g. `oas.OAS` becomes `github.com/x/oas.OAS`.
+func (s *scope) qualifyName(name string) string {
+	expr, err := parser.ParseExpr(name)
+	if err != nil {
+		return name
+	}
+	return s.qualify(expr)
+}
+
+// signature normalizes a method signature in the form of `Name (a, b int) error`
+// into a comparable string with fully qualified types and no parameter names.
+func (s *scope) signature(sig string) (name string, normalized string, ok bool) {
+	idx := strings.Index(sig, " (")
+	if idx == -1 {
+		return "", "", false
+	}
+
+	name = sig[:idx]
+	expr, err := parser.ParseExpr("func" + sig[idx:])
+	if err != nil {
+		return "", "", false
+	}
+
+	fn, ok := expr.(*ast.FuncType)
+	if !ok {
+		return "", "", false
+	}
+
+	return name, s.qualify(fn), true
+}
+
+func (s *scope) fieldList(list *ast.FieldList) string {
+	if list == nil {
+		return ""
+	}
+	result := []string{}
+	for _, field := range list.List {
+		typeName := s.qualify(field.Type)
+		for i := 0; i < max(len(field.Names), 1); i++ {
+			result = append(result, typeName)
+		}
+	}
+	return strings.Join(result, ", ")
+}
+
+// qualify renders a type expression with fully qualified names.
+func (s *scope) qualify(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "byte":
+			return "uint8"
+		case "rune":
+			return "int32"
+		}
+		if isBuiltin(t.Name) {
+			return t.Name
+		}
+		return s.importPath + "." + t.Name
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			if importPath, ok := s.imports[x.Name]; ok {
+				return importPath + "." + t.Sel.Name
+			}
+			return x.Name + "." + t.Sel.Name
+		}
+	case *ast.StarExpr:
+		return "*" + s.qualify(t.X)
+	case *ast.Ellipsis:
+		return "..." + s.qualify(t.Elt)
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "[]" + s.qualify(t.Elt)
+		}
+		return "[" + render(t.Len) + "]" + s.qualify(t.Elt)
+	case *ast.MapType:
+		return "map[" + s.qualify(t.Key) + "]" + s.qualify(t.Value)
+	case *ast.ChanType:
+		switch t.Dir {
+		case ast.SEND:
+			return "chan<- " + s.qualify(t.Value)
+		case ast.RECV:
+			return "<-chan " + s.qualify(t.Value)
+		}
+		return "chan " + s.qualify(t.Value)
+	case *ast.ParenExpr:
+		return s.qualify(t.X)
+	case *ast.IndexExpr:
+		return s.qualify(t.X) + "[" + s.qualify(t.Index) + "]"
+	case *ast.IndexListExpr:
+		args := make([]string, 0, len(t.Indices))
+		for _, index := range t.Indices {
+			args = append(args, s.qualify(index))
+		}
+		return s.qualify(t.X) + "[" + strings.Join(args, ", ") + "]"
+	case *ast.FuncType:
+		result := "func(" + s.fieldList(t.Params) + ")"
+		if t.Results != nil && len(t.Results.List) > 0 {
+			result += " (" + s.fieldList(t.Results) + ")"
+		}
+		return result
+	case *ast.InterfaceType:
+		if t.Methods == nil || len(t.Methods.List) == 0 {
+			return "any"
+		}
+	}
+	return render(expr)
+}
+
+func render(node ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// isBuiltin checks if a type name is a Go built-in type.
+func isBuiltin(name string) bool {
+	switch name {
+	case "string", "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64", "complex64", "complex128",
+		"bool", "byte", "rune", "error", "any", "comparable":
+		return true
+	}
+	return false
+}
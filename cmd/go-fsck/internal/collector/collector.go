@@ -6,6 +6,7 @@ import (
 	"go/ast"
 	"go/printer"
 	"go/token"
+	"go/types"
 	"log"
 	"os"
 	"path"
@@ -25,6 +26,7 @@ type (
 
 type collector struct {
 	fset *token.FileSet
+	info *types.Info
 
 	definition map[string]*Definition
 	seen       map[string]bool
@@ -208,8 +210,10 @@ func (v *collector) Visit(node ast.Node, push bool, stack []ast.Node) bool {
 
 		names := v.Names(node)
 
+		// Files for different platforms may declare the same names,
+		// the seen keys are scoped to the file.
 		for _, name := range names {
-			if v.isSeen("decl:" + packageName + "." + name) {
+			if v.isSeen("decl:" + filename + ":" + packageName + "." + name) {
 				return true
 			}
 		}
@@ -218,6 +222,7 @@ func (v *collector) Visit(node ast.Node, push bool, stack []ast.Node) bool {
 			Names:         names,
 			File:          v.relativeFile(filename),
 			Line:          v.fset.Position(node.Pos()).Line,
+			EndLine:       v.fset.Position(node.End()).Line,
 			SelfContained: IsSelfContainedType(node),
 			Source:        v.getSource(file, node),
 			Doc:           strings.TrimSpace(v.getSource(file, node.Doc)),
@@ -228,9 +233,11 @@ func (v *collector) Visit(node ast.Node, push bool, stack []ast.Node) bool {
 		}
 
 		for _, name := range names {
-			v.setSeen("decl:" + packageName + "." + name)
+			v.setSeen("decl:" + filename + ":" + packageName + "." + name)
 		}
 
+		v.collectDeclTypes(def, node)
+
 		switch node.Tok {
 		case token.CONST:
 			def.Kind = model.ConstKind
@@ -258,10 +265,10 @@ func (v *collector) Visit(node ast.Node, push bool, stack []ast.Node) bool {
 		if packageName != "" {
 			key = packageName + "." + name
 		}
-		if v.isSeen("func:" + key) {
+		if v.isSeen("func:" + filename + ":" + key) {
 			return true
 		}
-		v.setSeen("func:" + key)
+		v.setSeen("func:" + filename + ":" + key)
 
 		def := v.collectFuncDeclaration(file, node, filename, stack)
 		if def != nil {
@@ -319,10 +326,12 @@ func (v *collector) collectFuncDeclaration(file *ast.File, decl *ast.FuncDecl, f
 		Kind:       model.FuncKind,
 		File:       v.relativeFile(filename),
 		Line:       v.fset.Position(decl.Pos()).Line,
+		EndLine:    v.fset.Position(decl.End()).Line,
 		Name:       decl.Name.Name,
 		Arguments:  args,
 		Returns:    returns,
-		Signature:  v.functionDef(decl),
+		TypeParams: v.typeParams(file, decl.Type.TypeParams),
+		Signature:  v.functionDef(file, decl),
 		References: collectFuncReferences(decl),
 		Source:     source,
 		Complexity: complexity(decl, source),
@@ -332,6 +341,8 @@ func (v *collector) collectFuncDeclaration(file *ast.File, decl *ast.FuncDecl, f
 		declaration.Receiver = v.symbolType(file, decl.Recv.List[0].Type)
 	}
 
+	v.collectFuncTypes(declaration, file, decl)
+
 	return declaration
 }
 
@@ -390,9 +401,9 @@ func (p *collector) functionBindings(file *ast.File, decl *ast.FuncDecl) (args [
 	return
 }
 
-func (p *collector) functionDef(fun *ast.FuncDecl) string {
+func (p *collector) functionDef(file *ast.File, fun *ast.FuncDecl) string {
 	var fset = p.fset
-	name := fun.Name.Name
+	name := fun.Name.Name + p.typeParams(file, fun.Type.TypeParams).String()
 	params := make([]string, 0)
 	for _, p := range fun.Type.Params.List {
 		var typeNameBuf bytes.Buffer
@@ -12,6 +12,10 @@ func (p *collector) collectStructFields(out *model.Declaration, file *ast.File,
 	for _, spec := range decl.Specs {
 		switch obj := spec.(type) {
 		case *ast.TypeSpec:
+			if obj.TypeParams != nil {
+				out.TypeParams = p.typeParams(file, obj.TypeParams)
+			}
+
 			switch val := obj.Type.(type) {
 			case *ast.StructType:
 				p.parseStruct(out, file, obj, val)
@@ -20,15 +24,18 @@ func (p *collector) collectStructFields(out *model.Declaration, file *ast.File,
 				for _, field := range val.Methods.List {
 					if len(field.Names) == 0 {
 						out.Fields = append(out.Fields, &model.Field{
-							Type: "interface",
+							Type:     "interface",
+							Embed:    p.symbolType(file, field.Type),
+							TypeInfo: p.typeOf(field.Type),
 						})
 						continue
 					}
 					for _, name := range field.Names {
 						//fmt.Println(name, p.functionType(name.Name, field.Type.(*ast.FuncType)))
 						out.Fields = append(out.Fields, &model.Field{
-							Name: name.Name,
-							Type: p.functionType(name.Name, field.Type.(*ast.FuncType)),
+							Name:     name.Name,
+							Type:     p.functionType(name.Name, field.Type.(*ast.FuncType)),
+							TypeInfo: p.typeOf(field.Type),
 						})
 					}
 				}
@@ -49,34 +56,32 @@ func (p *collector) collectStructFields(out *model.Declaration, file *ast.File,
 	}
 }
 
-func (p *collector) parseStruct(structInfo *model.Declaration, file *ast.File, spec *ast.TypeSpec, obj *ast.StructType) {
-	goPath := structInfo.Name
-
-	if spec != nil && spec.TypeParams != nil {
-		names := []string{}
-		if spec != nil && spec.TypeParams != nil {
-			for _, field := range spec.TypeParams.List { // loop over all TypeParam fields
-				var constraint string
-				switch t := field.Type.(type) {
-				case *ast.Ident:
-					constraint = t.Name
-				case *ast.SelectorExpr:
-					if x, ok := t.X.(*ast.Ident); ok {
-						constraint = x.Name + "." + t.Sel.Name
-					}
-				default:
-					constraint = "unknown"
-				}
+// typeParams returns the type parameters of a generic type or func.
+func (p *collector) typeParams(file *ast.File, list *ast.FieldList) model.TypeParamList {
+	if list == nil || len(list.List) == 0 {
+		return nil
+	}
 
-				// combine field names with constraint
-				for _, ident := range field.Names { // loop over Names inside this Field
-					names = append(names, ident.Name+" "+constraint)
-				}
-			}
+	var result model.TypeParamList
+	for _, field := range list.List {
+		// symbolType would render inline interfaces as `any`
+		constraint := p.getSource(file, field.Type)
+		if _, ok := field.Type.(*ast.InterfaceType); !ok {
+			constraint = p.symbolType(file, field.Type)
 		}
 
-		structInfo.Arguments = names
+		for _, name := range field.Names {
+			result = append(result, &model.TypeParam{
+				Name:       name.Name,
+				Constraint: constraint,
+			})
+		}
 	}
+	return result
+}
+
+func (p *collector) parseStruct(structInfo *model.Declaration, file *ast.File, spec *ast.TypeSpec, obj *ast.StructType) {
+	goPath := structInfo.Name
 
 	for _, field := range obj.Fields.List {
 		//pos := p.fileset.Position(field.Pos())
@@ -122,6 +127,7 @@ func (p *collector) parseStruct(structInfo *model.Declaration, file *ast.File, s
 			Tag:  tagValue,
 
 			JSONName: jsonName,
+			TypeInfo: p.typeOf(field.Type),
 		}
 
 		structInfo.Fields = append(structInfo.Fields, v)
@@ -0,0 +1,205 @@
+package collector
+
+import (
+	"go/ast"
+	"go/types"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// WithTypesInfo enables collection of go/types resolved type information.
+// The info is usually taken from a `*packages.Package` loaded with syntax.
+func (v *collector) WithTypesInfo(info *types.Info) *collector {
+	v.info = info
+	return v
+}
+
+// typed returns true if type information is being collected.
+func (v *collector) typed() bool {
+	return v.info != nil
+}
+
+// NewTypeInfo converts a go/types type into the model representation.
+func NewTypeInfo(t types.Type) *model.TypeInfo {
+	if t == nil {
+		return nil
+	}
+
+	result := &model.TypeInfo{
+		Type: types.TypeString(t, nil),
+		Kind: typeKind(t),
+	}
+
+	switch t := t.(type) {
+	case *types.Alias:
+		result.Alias = true
+		setTypeName(result, t.Obj())
+		result.TypeParams = typeParams(t.TypeParams())
+		result.TypeArgs = typeArgs(t.TypeArgs())
+	case *types.Named:
+		setTypeName(result, t.Obj())
+		result.TypeParams = typeParams(t.TypeParams())
+		result.TypeArgs = typeArgs(t.TypeArgs())
+	case *types.Basic:
+		result.Name = t.Name()
+	case *types.TypeParam:
+		result.Name = t.Obj().Name()
+	case *types.Pointer:
+		result.Elem = NewTypeInfo(t.Elem())
+	case *types.Slice:
+		result.Elem = NewTypeInfo(t.Elem())
+	case *types.Array:
+		result.Elem = NewTypeInfo(t.Elem())
+	case *types.Chan:
+		result.Elem = NewTypeInfo(t.Elem())
+	case *types.Map:
+		result.Key = NewTypeInfo(t.Key())
+		result.Elem = NewTypeInfo(t.Elem())
+	}
+
+	return result
+}
+
+func setTypeName(out *model.TypeInfo, obj *types.TypeName) {
+	out.Name = obj.Name()
+	if pkg := obj.Pkg(); pkg != nil {
+		out.ImportPath = pkg.Path()
+	}
+}
+
+func typeParams(list *types.TypeParamList) []string {
+	if list.Len() == 0 {
+		return nil
+	}
+	result := make([]string, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		param := list.At(i)
+		result = append(result, param.Obj().Name()+" "+types.TypeString(param.Constraint(), nil))
+	}
+	return result
+}
+
+func typeArgs(list *types.TypeList) []*model.TypeInfo {
+	if list.Len() == 0 {
+		return nil
+	}
+	result := make([]*model.TypeInfo, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		result = append(result, NewTypeInfo(list.At(i)))
+	}
+	return result
+}
+
+// typeKind returns the kind of the underlying type.
+func typeKind(t types.Type) string {
+	if _, ok := t.(*types.TypeParam); ok {
+		return "typeparam"
+	}
+	switch t.Underlying().(type) {
+	case *types.Basic:
+		return "basic"
+	case *types.Struct:
+		return "struct"
+	case *types.Interface:
+		return "interface"
+	case *types.Map:
+		return "map"
+	case *types.Slice:
+		return "slice"
+	case *types.Array:
+		return "array"
+	case *types.Pointer:
+		return "pointer"
+	case *types.Chan:
+		return "chan"
+	case *types.Signature:
+		return "func"
+	}
+	return ""
+}
+
+// typeOf returns the type info for an expression.
+func (v *collector) typeOf(expr ast.Expr) *model.TypeInfo {
+	if !v.typed() || expr == nil {
+		return nil
+	}
+	return NewTypeInfo(v.info.TypeOf(expr))
+}
+
+// objectType returns the type info for a declared identifier.
+func (v *collector) objectType(ident *ast.Ident) *model.TypeInfo {
+	if !v.typed() || ident == nil {
+		return nil
+	}
+	obj := v.info.Defs[ident]
+	if obj == nil {
+		return nil
+	}
+	return NewTypeInfo(obj.Type())
+}
+
+// collectDeclTypes fills the TypeInfo for single name type, var and const declarations.
+func (v *collector) collectDeclTypes(out *model.Declaration, decl *ast.GenDecl) {
+	if !v.typed() || len(decl.Specs) != 1 {
+		return
+	}
+
+	switch spec := decl.Specs[0].(type) {
+	case *ast.TypeSpec:
+		out.TypeInfo = v.objectType(spec.Name)
+	case *ast.ValueSpec:
+		if len(spec.Names) == 1 {
+			out.TypeInfo = v.objectType(spec.Names[0])
+		}
+	}
+}
+
+// collectFuncTypes fills the receiver, argument and return types for a function.
+// Arguments and returns are deduplicated the same way as the string values,
+// so ArgumentTypes and ReturnTypes line up with Arguments and Returns.
+func (v *collector) collectFuncTypes(out *model.Declaration, file *ast.File, decl *ast.FuncDecl) {
+	if !v.typed() {
+		return
+	}
+
+	fn, ok := v.info.Defs[decl.Name].(*types.Func)
+	if !ok {
+		return
+	}
+	sig := fn.Type().(*types.Signature)
+
+	if recv := sig.Recv(); recv != nil {
+		out.ReceiverType = NewTypeInfo(recv.Type())
+	}
+
+	out.ArgumentTypes = v.tupleTypes(file, sig.Params(), decl.Type.Params)
+	out.ReturnTypes = v.tupleTypes(file, sig.Results(), decl.Type.Results)
+}
+
+// tupleTypes maps a signature tuple back onto the ast field list,
+// returning one type per field, deduplicated by the source type string.
+func (v *collector) tupleTypes(file *ast.File, tuple *types.Tuple, fields *ast.FieldList) []*model.TypeInfo {
+	if fields == nil || tuple.Len() == 0 {
+		return nil
+	}
+
+	var (
+		result []*model.TypeInfo
+		seen   = map[string]bool{}
+		index  int
+	)
+	for _, field := range fields.List {
+		if index >= tuple.Len() {
+			break
+		}
+
+		key := v.symbolType(file, field.Type)
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, NewTypeInfo(tuple.At(index).Type()))
+		}
+
+		index += max(len(field.Names), 1)
+	}
+	return result
+}
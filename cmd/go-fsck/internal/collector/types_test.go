@@ -0,0 +1,138 @@
+package collector
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const typedSource = `package sample
+
+type Number interface {
+	~int | ~float64
+}
+
+type List[T Number] struct {
+	Items []*T
+	Index map[string]List[int]
+}
+
+type Alias = List[float64]
+
+func (l *List[T]) Add(a, b T, rest ...T) (List[T], error) {
+	return *l, nil
+}
+`
+
+func TestCollector_Typed(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", typedSource, parser.ParseComments)
+	require.NoError(t, err)
+
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Defs:  map[*ast.Ident]types.Object{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}
+	conf := types.Config{}
+	_, err = conf.Check("example.com/sample", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+
+	sink := NewCollector(fset).WithTypesInfo(info)
+	inspector.New([]*ast.File{file}).WithStack(nil, sink.Visit)
+
+	defs := sink.Clean(false)
+	require.Len(t, defs, 1)
+
+	list := defs[0].Types.Find(func(d *Declaration) bool { return d.Name == "List" })
+	require.NotNil(t, list)
+	require.NotNil(t, list.TypeInfo)
+	assert.Equal(t, "example.com/sample", list.TypeInfo.ImportPath)
+	assert.Equal(t, "struct", list.TypeInfo.Kind)
+	assert.Equal(t, []string{"T example.com/sample.Number"}, list.TypeInfo.TypeParams)
+
+	require.Len(t, list.Fields, 2)
+	items := list.Fields[0].TypeInfo
+	assert.Equal(t, "slice", items.Kind)
+	assert.Equal(t, "pointer", items.Elem.Kind)
+	assert.Equal(t, "typeparam", items.Elem.Elem.Kind)
+
+	index := list.Fields[1].TypeInfo
+	assert.Equal(t, "map", index.Kind)
+	assert.Equal(t, "string", index.Key.Name)
+	assert.Equal(t, "example.com/sample.List", index.Elem.QualifiedName())
+	require.Len(t, index.Elem.TypeArgs, 1)
+	assert.Equal(t, "int", index.Elem.TypeArgs[0].Name)
+	assert.Equal(t, "example.com/sample.List", index.Ref().QualifiedName())
+
+	alias := defs[0].Types.Find(func(d *Declaration) bool { return d.Name == "Alias" })
+	require.NotNil(t, alias)
+	assert.True(t, alias.TypeInfo.Alias)
+
+	add := defs[0].Funcs.Find(func(d *Declaration) bool { return d.Name == "Add" })
+	require.NotNil(t, add)
+	assert.Equal(t, 14, add.Line)
+	assert.Equal(t, 16, add.EndLine)
+	assert.Equal(t, "pointer", add.ReceiverType.Kind)
+	require.Len(t, add.ArgumentTypes, len(add.Arguments))
+	assert.Equal(t, "T", add.ArgumentTypes[0].Name)
+	assert.Equal(t, "slice", add.ArgumentTypes[1].Kind)
+	require.Len(t, add.ReturnTypes, len(add.Returns))
+	assert.Equal(t, "error", add.ReturnTypes[1].Name)
+}
+
+const genericSource = `package sample
+
+import "fmt"
+
+type Pair[K comparable, V fmt.Stringer | ~string] struct {
+	Key   K
+	Value V
+}
+
+type Number interface {
+	~int | ~float64
+}
+
+func Sum[T interface{ ~int }, R Number](values ...T) R {
+	return 0
+}
+
+func (p *Pair[K, V]) Get() V {
+	return p.Value
+}
+`
+
+func TestCollector_TypeParams(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", genericSource, parser.ParseComments)
+	require.NoError(t, err)
+
+	sink := NewCollector(fset)
+	inspector.New([]*ast.File{file}).WithStack(nil, sink.Visit)
+
+	defs := sink.Clean(false)
+	require.Len(t, defs, 1)
+
+	pair := defs[0].Types.Find(func(d *Declaration) bool { return d.Name == "Pair" })
+	require.NotNil(t, pair)
+	assert.Equal(t, "[K comparable, V fmt.Stringer | ~string]", pair.TypeParams.String())
+	assert.Empty(t, pair.Arguments)
+
+	sum := defs[0].Funcs.Find(func(d *Declaration) bool { return d.Name == "Sum" })
+	require.NotNil(t, sum)
+	assert.Equal(t, "[T interface{ ~int }, R Number]", sum.TypeParams.String())
+	assert.Equal(t, "Sum[T interface{ ~int }, R Number] (values ...T) R", sum.Signature)
+
+	get := defs[0].Funcs.Find(func(d *Declaration) bool { return d.Name == "Get" })
+	require.NotNil(t, get)
+	assert.Nil(t, get.TypeParams)
+	assert.Equal(t, "*Pair[K, V]", get.Receiver)
+	assert.Equal(t, []string{"Pair.Get"}, get.Keys())
+}
@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"strings"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// DefinitionImportPath returns the import path for a definition,
+// falling back to the package name if the import path is not set.
+func DefinitionImportPath(def *model.Definition) string {
+	if def.ImportPath != "" {
+		return def.ImportPath
+	}
+	return def.Package.Name()
+}
+
+// FuncName returns the function name, prefixed with the receiver type
+// for methods, e.g. `Store.Get`.
+func FuncName(receiver, name string) string {
+	if receiver != "" {
+		return strings.TrimLeft(receiver, "*") + "." + name
+	}
+	return name
+}
+
+// ReceiverName trims pointers and type parameters from a receiver.
+func ReceiverName(receiver string) string {
+	name := strings.TrimLeft(receiver, "*")
+	if idx := strings.Index(name, "["); idx != -1 {
+		name = name[:idx]
+	}
+	return name
+}
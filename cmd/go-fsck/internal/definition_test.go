@@ -0,0 +1,26 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func TestDefinitionImportPath(t *testing.T) {
+	assert.Equal(t, "github.com/x/store", internal.DefinitionImportPath(&model.Definition{Package: model.Package{ImportPath: "github.com/x/store", Package: "store"}}))
+	assert.Equal(t, "store", internal.DefinitionImportPath(&model.Definition{Package: model.Package{Package: "store"}}))
+}
+
+func TestFuncName(t *testing.T) {
+	assert.Equal(t, "New", internal.FuncName("", "New"))
+	assert.Equal(t, "Store.Get", internal.FuncName("*Store", "Get"))
+}
+
+func TestReceiverName(t *testing.T) {
+	assert.Equal(t, "Item", internal.ReceiverName("Item"))
+	assert.Equal(t, "Item", internal.ReceiverName("*Item"))
+	assert.Equal(t, "List", internal.ReceiverName("*List[T]"))
+}
@@ -3,6 +3,7 @@ package files
 import (
 	"bytes"
 	"fmt"
+	"go/format"
 	"os"
 )
 
@@ -15,6 +16,7 @@ type File struct {
 }
 
 // Flush writes the File's content to the specified Filename.
+// The content is formatted with go/format.
 func (f *File) Flush() error {
 	// Skip writing if there are no types to write
 	if len(f.Types) == 0 {
@@ -26,7 +28,7 @@ func (f *File) Flush() error {
 	// Write package declaration
 	buffer.WriteString(fmt.Sprintf("package %s\n\n", f.Package))
 
-	// Write import statements
+	// Write import statements, empty values separate import groups
 	if len(f.Imports) > 0 {
 		buffer.WriteString("import (\n")
 		for _, imp := range f.Imports {
@@ -40,6 +42,12 @@ func (f *File) Flush() error {
 		buffer.WriteString(fmt.Sprintf("%s\n\n", typ))
 	}
 
+	// Format the source like gofmt
+	body, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return fmt.Errorf("error formatting %s: %w", f.Filename, err)
+	}
+
 	// Write buffer contents to file
-	return os.WriteFile(f.Filename, buffer.Bytes(), 0644)
+	return os.WriteFile(f.Filename, body, 0644)
 }
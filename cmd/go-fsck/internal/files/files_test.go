@@ -47,7 +47,6 @@ type MyType struct {
 type AnotherType struct {
 	Age int
 }
-
 `
 
 	if string(content) != expectedContent {
@@ -0,0 +1,30 @@
+package internal
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	versionSuffix = regexp.MustCompile(`/v[0-9]+$`)
+	invalidChars  = regexp.MustCompile(`[^A-Za-z0-9_]`)
+)
+
+// PackageName guesses the package name from an import path, following
+// the common conventions of `/v2` suffixes, `go-` prefixes and
+// `gopkg.in/yaml.v3` style versions.
+func PackageName(importPath string) string {
+	name := path.Base(versionSuffix.ReplaceAllString(importPath, ""))
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+	name, _, _ = strings.Cut(name, ".")
+	return invalidChars.ReplaceAllString(name, "")
+}
+
+// IsStdlib returns true for standard library imports, which
+// don't have a domain in the first path element.
+func IsStdlib(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
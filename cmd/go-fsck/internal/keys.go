@@ -0,0 +1,19 @@
+package internal
+
+import "sort"
+
+// SortedKeys returns the sorted, unique keys of the maps.
+func SortedKeys[T any](maps ...map[string]T) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				result = append(result, key)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
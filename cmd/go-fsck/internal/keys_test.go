@@ -0,0 +1,14 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+)
+
+func TestSortedKeys(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, internal.SortedKeys(map[string]int{"c": 1, "a": 2}, map[string]int{"b": 3, "a": 4}))
+	assert.Empty(t, internal.SortedKeys[bool]())
+}
@@ -0,0 +1,21 @@
+package internal
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchPath returns true if name matches the pattern of a `.go-fsck.yml`
+// config. A pattern ending with `/...` matches the folder and anything
+// below it, other patterns use path.Match.
+func MatchPath(pattern, name string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	if pattern == "..." {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return name == prefix || strings.HasPrefix(name, prefix+"/")
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
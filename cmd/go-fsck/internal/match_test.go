@@ -0,0 +1,18 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+)
+
+func TestMatchPath(t *testing.T) {
+	assert.True(t, internal.MatchPath("./...", "model/loader"))
+	assert.True(t, internal.MatchPath("./model/...", "model"))
+	assert.True(t, internal.MatchPath("model/...", "model/loader/cache.go"))
+	assert.False(t, internal.MatchPath("model/...", "modelx"))
+	assert.True(t, internal.MatchPath("cmd/*", "cmd/go-fsck"))
+	assert.False(t, internal.MatchPath("cmd/*", "cmd/go-fsck/model"))
+}
@@ -18,6 +18,35 @@ func ListPackages(rootPath string, pattern string) ([]*model.Package, error) {
 	span := telemetry.Start("internal.ListPackages")
 	defer span.End()
 
+	return loadPackages(rootPath, syntaxMode, pattern)
+}
+
+// ListPackageFiles returns a slice of local packages like ListPackages,
+// without parsing and type checking the packages. The packages only
+// hold the package names and file lists. Use LoadPackages to load the
+// syntax for a subset of the packages.
+func ListPackageFiles(rootPath string, pattern string) ([]*model.Package, error) {
+	span := telemetry.Start("internal.ListPackageFiles")
+	defer span.End()
+
+	return loadPackages(rootPath, filesMode, pattern)
+}
+
+// LoadPackages loads the packages matching patterns (e.g. `./model`)
+// in the specified root directory, along with their test variants.
+func LoadPackages(rootPath string, patterns ...string) ([]*model.Package, error) {
+	span := telemetry.Start("internal.LoadPackages")
+	defer span.End()
+
+	return loadPackages(rootPath, syntaxMode, patterns...)
+}
+
+const (
+	filesMode  = packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedModule
+	syntaxMode = filesMode | packages.LoadSyntax
+)
+
+func loadPackages(rootPath string, mode packages.LoadMode, patterns ...string) ([]*model.Package, error) {
 	if err := os.Chdir(rootPath); err != nil {
 		return nil, err
 	}
@@ -27,7 +56,7 @@ func ListPackages(rootPath string, pattern string) ([]*model.Package, error) {
 		return nil, err
 	}
 
-	packages, err := listPackages(pattern)
+	packages, err := listPackages(mode, patterns...)
 	if err != nil || len(packages) == 0 {
 		return nil, err
 	}
@@ -82,11 +111,11 @@ func cleanPackages(pkgs []*packages.Package, workDir string) []*model.Package {
 	return results
 }
 
-func listPackages(pattern string) ([]*packages.Package, error) {
+func listPackages(mode packages.LoadMode, patterns ...string) ([]*packages.Package, error) {
 	cfg := &packages.Config{
-		Mode:  packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedModule | packages.LoadSyntax,
+		Mode:  mode,
 		Tests: true,
 	}
 
-	return packages.Load(cfg, pattern)
+	return packages.Load(cfg, patterns...)
 }
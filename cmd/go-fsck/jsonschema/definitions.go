@@ -1,6 +1,8 @@
 package jsonschema
 
 import (
+	"context"
+
 	"github.com/titpetric/exp/cmd/go-fsck/internal"
 	"github.com/titpetric/exp/cmd/go-fsck/model"
 	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
@@ -14,14 +16,15 @@ func getDefinitions(cfg *options) ([]*model.Definition, error) {
 		return nil, err
 	}
 
-	defs := []*model.Definition{}
+	results, err := loader.LoadAll(context.Background(), packages, &loader.Options{})
+	if err != nil {
+		return nil, err
+	}
 
-	for _, pkg := range packages {
-		d, err := loader.Load(pkg, false, false)
-		if err != nil {
-			return nil, err
-		}
+	defs := []*model.Definition{}
 
+	for i, pkg := range packages {
+		d := results[i]
 		for _, v := range d {
 			v.Package.ID = pkg.ID
 			v.Package.ImportPath = pkg.ImportPath
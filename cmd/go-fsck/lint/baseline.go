@@ -0,0 +1,122 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/titpetric/exp/cmd/go-fsck/lint/rules"
+)
+
+const defaultBaselineFile = ".go-fsck-baseline.json"
+
+// Baseline holds known issues which are not reported.
+//
+// Issues are keyed by file, symbol and rule ID. Line numbers are not
+// part of the key, so unrelated changes to a file don't invalidate the
+// baseline. Count holds the number of issues with the same key.
+type Baseline struct {
+	Issues []*BaselineIssue `json:"issues"`
+}
+
+// BaselineIssue is a known issue in the baseline.
+type BaselineIssue struct {
+	File   string `json:"file"`
+	Symbol string `json:"symbol,omitempty"`
+	Rule   string `json:"rule"`
+	Count  int    `json:"count"`
+}
+
+func (b *BaselineIssue) key() string {
+	return b.File + "\x00" + b.Symbol + "\x00" + b.Rule
+}
+
+func newBaselineIssue(issue *rules.Issue) *BaselineIssue {
+	file := issue.Path()
+	if file == "" {
+		// issues reported for a package, e.g. import collisions
+		file = issue.PackagePath
+	}
+	return &BaselineIssue{
+		File:   file,
+		Symbol: issue.SymbolName(),
+		Rule:   issue.RuleID(),
+		Count:  1,
+	}
+}
+
+// NewBaseline creates a baseline from issues.
+func NewBaseline(issues []*rules.Issue) *Baseline {
+	byKey := map[string]*BaselineIssue{}
+	for _, issue := range issues {
+		entry := newBaselineIssue(issue)
+		if existing, ok := byKey[entry.key()]; ok {
+			existing.Count++
+			continue
+		}
+		byKey[entry.key()] = entry
+	}
+
+	result := &Baseline{
+		Issues: make([]*BaselineIssue, 0, len(byKey)),
+	}
+	for _, entry := range byKey {
+		result.Issues = append(result.Issues, entry)
+	}
+	sort.Slice(result.Issues, func(i, j int) bool {
+		return result.Issues[i].key() < result.Issues[j].key()
+	})
+	return result
+}
+
+// LoadBaseline reads a baseline file.
+func LoadBaseline(filename string) (*Baseline, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Baseline{}
+	if err := json.Unmarshal(b, result); err != nil {
+		return nil, fmt.Errorf("error reading baseline %s: %w", filename, err)
+	}
+	return result, nil
+}
+
+// Save writes the baseline to a file.
+func (b *Baseline) Save(filename string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0o644)
+}
+
+// Apply removes known issues from the results. It returns the number
+// of baseline issues that are no longer reported.
+func (b *Baseline) Apply(results []*result) int {
+	known := map[string]int{}
+	for _, entry := range b.Issues {
+		known[entry.key()] += entry.Count
+	}
+
+	for _, r := range results {
+		issues := make([]*rules.Issue, 0, len(r.issues))
+		for _, issue := range r.issues {
+			key := newBaselineIssue(issue).key()
+			if known[key] > 0 {
+				known[key]--
+				continue
+			}
+			issues = append(issues, issue)
+		}
+		r.issues = issues
+	}
+
+	stale := 0
+	for _, count := range known {
+		stale += count
+	}
+	return stale
+}
@@ -0,0 +1,61 @@
+package lint
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/lint/rules"
+)
+
+func TestBaseline(t *testing.T) {
+	issue := func(line int, symbol, issueType string) *rules.Issue {
+		return &rules.Issue{
+			Rule:        "func-args",
+			PackagePath: "./model",
+			File:        "model.go",
+			Line:        line,
+			Symbol:      symbol,
+			IssueType:   issueType,
+		}
+	}
+
+	baseline := NewBaseline([]*rules.Issue{
+		issue(10, "Load", "arg-order"),
+		issue(20, "Save", "arg-order"),
+		issue(30, "Save", "arg-order"),
+		{Rule: "imports", PackagePath: "./model", IssueType: "import-collision"},
+	})
+
+	require.Len(t, baseline.Issues, 3)
+	assert.Equal(t, &BaselineIssue{File: "./model", Rule: "imports/import-collision", Count: 1}, baseline.Issues[0])
+	assert.Equal(t, &BaselineIssue{File: "model/model.go", Symbol: "Save", Rule: "func-args/arg-order", Count: 2}, baseline.Issues[2])
+
+	filename := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, baseline.Save(filename))
+
+	loaded, err := LoadBaseline(filename)
+	require.NoError(t, err)
+	assert.Equal(t, baseline, loaded)
+
+	results := []*result{
+		{
+			issues: []*rules.Issue{
+				// moved lines are still known
+				issue(15, "Load", "arg-order"),
+				issue(25, "Save", "arg-order"),
+				// new issues are reported
+				issue(40, "Load", "duplicate-type"),
+				issue(50, "Delete", "arg-order"),
+			},
+		},
+	}
+
+	stale := loaded.Apply(results)
+	assert.Equal(t, 2, stale)
+	require.Len(t, results[0].issues, 2)
+	assert.Equal(t, 40, results[0].issues[0].Line)
+	assert.Equal(t, "Delete", results[0].issues[1].Symbol)
+}
@@ -0,0 +1,114 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/lint/rules"
+)
+
+// Config holds the go-fsck configuration from `.go-fsck.yml`.
+type Config struct {
+	Lint LintConfig `yaml:"lint"`
+}
+
+// LintConfig holds the lint configuration.
+type LintConfig struct {
+	// Rules lists the rules to run if `--rules` is not given.
+	Rules []string `yaml:"rules,omitempty"`
+
+	// Exclude suppresses issues for all rules.
+	Exclude Exclude `yaml:"exclude,omitempty"`
+
+	// Settings holds per-rule configuration, keyed by rule name.
+	Settings map[string]*RuleConfig `yaml:"settings,omitempty"`
+}
+
+// RuleConfig holds the configuration for a single rule.
+type RuleConfig struct {
+	// Severity overrides the rule severity, defaults to error.
+	Severity rules.Severity `yaml:"severity,omitempty"`
+
+	// Exclude suppresses issues for the rule.
+	Exclude Exclude `yaml:"exclude,omitempty"`
+
+	// Options are passed to rules implementing rules.Configurable.
+	Options map[string]any `yaml:"options,omitempty"`
+}
+
+// Exclude holds path and symbol patterns for suppressing issues.
+//
+// Paths are matched against the file path relative to the module root
+// with path.Match, a trailing `/...` matches everything under a folder.
+// Symbols are matched against the symbol name, and `Type.Method` for
+// methods.
+type Exclude struct {
+	Paths   []string `yaml:"paths,omitempty"`
+	Symbols []string `yaml:"symbols,omitempty"`
+}
+
+// LoadConfig reads the config file. A missing file results in an empty config.
+func LoadConfig(filename string) (*Config, error) {
+	config := &Config{}
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(b, config); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", filename, err)
+	}
+
+	for name, setting := range config.Lint.Settings {
+		if setting == nil {
+			config.Lint.Settings[name] = &RuleConfig{}
+			continue
+		}
+		if setting.Severity != "" && !setting.Severity.Valid() {
+			return nil, fmt.Errorf("error reading %s: invalid severity %q for rule %q", filename, setting.Severity, name)
+		}
+	}
+
+	return config, nil
+}
+
+// Setting returns the configuration for a rule.
+func (c *LintConfig) Setting(name string) *RuleConfig {
+	if setting, ok := c.Settings[name]; ok && setting != nil {
+		return setting
+	}
+	return &RuleConfig{}
+}
+
+// Match returns true if the issue matches any of the exclude patterns.
+func (e Exclude) Match(issue *rules.Issue) bool {
+	if filename := issue.Path(); filename != "" {
+		for _, pattern := range e.Paths {
+			// Patterns without a folder, e.g. `*_test.go`, match the base name.
+			if internal.MatchPath(pattern, filename) || internal.MatchPath(pattern, path.Base(filename)) {
+				return true
+			}
+		}
+	}
+
+	if issue.Symbol != "" {
+		for _, pattern := range e.Symbols {
+			if ok, _ := path.Match(pattern, issue.Symbol); ok {
+				return true
+			}
+			if ok, _ := path.Match(pattern, issue.SymbolName()); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,105 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/lint/rules"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func TestLoadConfig(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), ".go-fsck.yml")
+
+	config, err := LoadConfig(filename)
+	require.NoError(t, err)
+	assert.Empty(t, config.Lint.Rules)
+
+	require.NoError(t, os.WriteFile(filename, []byte(`
+lint:
+  rules: [godoc, func-args]
+  exclude:
+    paths: [internal/...]
+  settings:
+    godoc:
+      severity: warning
+      exclude:
+        symbols: ["New*"]
+      options:
+        max-lines: 5
+`), 0o644))
+
+	config, err = LoadConfig(filename)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"godoc", "func-args"}, config.Lint.Rules)
+	assert.Equal(t, rules.SeverityWarning, config.Lint.Setting("godoc").Severity)
+	assert.Equal(t, 5, config.Lint.Setting("godoc").Options["max-lines"])
+	assert.Empty(t, config.Lint.Setting("func-args").Severity)
+
+	require.NoError(t, os.WriteFile(filename, []byte("lint: {settings: {godoc: {severity: fatal}}}"), 0o644))
+	_, err = LoadConfig(filename)
+	assert.Error(t, err)
+}
+
+func TestExclude_Match(t *testing.T) {
+	exclude := Exclude{
+		Paths:   []string{"internal/...", "*_gen.go"},
+		Symbols: []string{"New*", "Server.Handle"},
+	}
+
+	tests := []struct {
+		issue *rules.Issue
+		match bool
+	}{
+		{&rules.Issue{PackagePath: "./internal/x", File: "x.go", Symbol: "Foo"}, true},
+		{&rules.Issue{PackagePath: "./model", File: "types_gen.go", Symbol: "Foo"}, true},
+		{&rules.Issue{PackagePath: "./model", File: "model.go", Symbol: "NewModel"}, true},
+		{&rules.Issue{PackagePath: "./model", File: "model.go", Symbol: "Handle", Receiver: "*Server"}, true},
+		{&rules.Issue{PackagePath: "./model", File: "model.go", Symbol: "Model"}, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, exclude.Match(tt.issue), tt.issue.String())
+	}
+}
+
+func TestFilterIssues(t *testing.T) {
+	defs := []*model.Definition{
+		{
+			Package: model.Package{Path: "./model"},
+			Funcs: model.DeclarationList{
+				{Name: "Load", File: "model.go", Line: 10, Doc: "go-fsck:ignore func-args"},
+				{Name: "Save", File: "model.go", Line: 20, Doc: "go-fsck:ignore"},
+			},
+		},
+		{
+			Package: model.Package{Path: "./legacy"},
+			Doc:     "Package legacy is going away.\ngo-fsck:ignore godoc",
+		},
+	}
+
+	issues := []*rules.Issue{
+		{Rule: "godoc", PackagePath: "./model", File: "model.go", Line: 10, Symbol: "Load"},
+		{Rule: "func-args", PackagePath: "./model", File: "model.go", Line: 10, Symbol: "Load"},
+		{Rule: "godoc", PackagePath: "./model", File: "model.go", Line: 20, Symbol: "Save"},
+		{Rule: "godoc", PackagePath: "./legacy", File: "legacy.go", Line: 5, Symbol: "Old"},
+	}
+
+	config := &Config{}
+	config.Lint.Settings = map[string]*RuleConfig{
+		"godoc": {Severity: rules.SeverityWarning},
+	}
+
+	result := filterIssues(config, newIgnoreIndex(defs), "godoc", issues)
+	require.Len(t, result, 1)
+	assert.Equal(t, "Load", result[0].Symbol)
+	assert.Equal(t, "godoc", result[0].Rule)
+	assert.Equal(t, rules.SeverityWarning, result[0].Severity)
+
+	result = filterIssues(&Config{}, newIgnoreIndex(defs), "func-args", issues[1:2])
+	assert.Empty(t, result)
+}
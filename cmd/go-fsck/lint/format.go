@@ -0,0 +1,122 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/titpetric/exp/cmd/go-fsck/lint/rules"
+)
+
+// formats lists the supported output formats.
+var formats = []string{"text", "json", "sarif", "github"}
+
+func render(w io.Writer, cfg *options, results []*result) error {
+	switch cfg.format {
+	case "json":
+		return renderJSON(w, results)
+	case "sarif":
+		return renderSARIF(w, results)
+	case "github":
+		return renderGithub(w, results)
+	}
+
+	if cfg.summarize {
+		return renderSummary(w, results)
+	}
+	return renderText(w, results)
+}
+
+func renderText(w io.Writer, results []*result) error {
+	for _, r := range results {
+		for _, issue := range r.issues {
+			fmt.Fprintln(w, issue.String())
+		}
+	}
+	return nil
+}
+
+func renderSummary(w io.Writer, results []*result) error {
+	for _, r := range results {
+		yamlData, err := yaml.Marshal(map[string]interface{}{
+			r.rule.Name(): r.stats,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(w, string(yamlData))
+	}
+	return nil
+}
+
+func renderJSON(w io.Writer, results []*result) error {
+	var allIssues []interface{}
+	for _, r := range results {
+		if len(r.issues) > 0 {
+			allIssues = append(allIssues, map[string]interface{}{
+				"rule":    r.rule.Name(),
+				"issues":  r.issues,
+				"summary": r.stats,
+			})
+		}
+	}
+	if len(allIssues) == 0 {
+		return nil
+	}
+
+	jsonBytes, err := json.MarshalIndent(allIssues, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, string(jsonBytes))
+	return nil
+}
+
+// renderGithub prints issues as GitHub Actions workflow commands,
+// which show up as annotations on pull requests.
+func renderGithub(w io.Writer, results []*result) error {
+	for _, r := range results {
+		for _, issue := range r.issues {
+			params := []string{}
+			if filename := issue.Path(); filename != "" {
+				params = append(params, "file="+escapeGithubProperty(filename))
+				if issue.Line > 0 {
+					params = append(params, fmt.Sprintf("line=%d", issue.Line))
+				}
+			}
+			params = append(params, "title="+escapeGithubProperty(issue.RuleID()))
+
+			fmt.Fprintf(w, "::%s %s::%s\n", githubLevel(issue.Severity), strings.Join(params, ","), escapeGithubData(issueMessage(issue)))
+		}
+	}
+	return nil
+}
+
+func githubLevel(severity rules.Severity) string {
+	switch severity {
+	case rules.SeverityWarning:
+		return "warning"
+	case rules.SeverityInfo:
+		return "notice"
+	}
+	return "error"
+}
+
+func escapeGithubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeGithubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// issueMessage returns the issue description, prefixed with the symbol.
+func issueMessage(issue *rules.Issue) string {
+	if issue.Symbol == "" {
+		return issue.Description
+	}
+	return issue.SymbolName() + ": " + issue.Description
+}
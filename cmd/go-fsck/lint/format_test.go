@@ -0,0 +1,83 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/lint/rules"
+)
+
+func testResults() []*result {
+	return []*result{
+		{
+			rule: rules.NewGodocLinter(),
+			issues: []*rules.Issue{
+				{
+					Rule:        "godoc",
+					Severity:    rules.SeverityWarning,
+					File:        "model.go",
+					Line:        12,
+					Symbol:      "Load",
+					Receiver:    "*Model",
+					IssueType:   "missing-godoc",
+					Description: "exported symbol lacks godoc comment",
+					PackagePath: "./model",
+				},
+			},
+		},
+		{
+			rule: rules.NewImportsLinter(),
+			issues: []*rules.Issue{
+				{
+					Rule:        "imports",
+					Severity:    rules.SeverityError,
+					IssueType:   "import-collision",
+					Description: "Import conflict for model, a/model != b/model",
+				},
+			},
+		},
+	}
+}
+
+func TestRenderGithub(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, render(&buf, &options{format: "github"}, testResults()))
+
+	expected := "::warning file=model/model.go,line=12,title=godoc/missing-godoc::Model.Load: exported symbol lacks godoc comment\n" +
+		"::error title=imports/import-collision::Import conflict for model, a/model != b/model\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestRenderSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, render(&buf, &options{format: "sarif"}, testResults()))
+
+	log := sarifLog{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Equal(t, "2.1.0", log.Version)
+
+	// all issue types from godoc and imports are listed
+	require.Len(t, run.Tool.Driver.Rules, 4)
+	assert.Equal(t, "godoc/godoc-format", run.Tool.Driver.Rules[0].ID)
+
+	require.Len(t, run.Results, 2)
+
+	godoc := run.Results[0]
+	assert.Equal(t, "godoc/missing-godoc", godoc.RuleID)
+	assert.Equal(t, "godoc/missing-godoc", run.Tool.Driver.Rules[godoc.RuleIndex].ID)
+	assert.Equal(t, "warning", godoc.Level)
+	require.Len(t, godoc.Locations, 1)
+	assert.Equal(t, "model/model.go", godoc.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 12, godoc.Locations[0].PhysicalLocation.Region.StartLine)
+
+	imports := run.Results[1]
+	assert.Equal(t, "error", imports.Level)
+	assert.Empty(t, imports.Locations)
+}
@@ -0,0 +1,64 @@
+package lint
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/titpetric/exp/cmd/go-fsck/lint/rules"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// ignoreIndex holds the rules suppressed with `//go-fsck:ignore`
+// comments. Declaration comments suppress issues reported for the
+// declaration, the package doc comment suppresses issues in the package.
+// A nil rule list suppresses all rules.
+type ignoreIndex map[string][]string
+
+func newIgnoreIndex(defs []*model.Definition) ignoreIndex {
+	index := ignoreIndex{}
+
+	add := func(key, doc string) {
+		names, ok := rules.ParseIgnore(doc)
+		if !ok {
+			return
+		}
+		if existing, seen := index[key]; seen && (existing == nil || names == nil) {
+			index[key] = nil
+			return
+		}
+		index[key] = append(index[key], names...)
+	}
+
+	for _, def := range defs {
+		add(def.Package.Path, def.Doc)
+
+		for _, decls := range []model.DeclarationList{def.Types, def.Funcs, def.Consts, def.Vars} {
+			for _, decl := range decls {
+				add(ignoreKey(def.Package.Path, decl.File, decl.Line), decl.Doc)
+			}
+		}
+	}
+
+	return index
+}
+
+func ignoreKey(packagePath, file string, line int) string {
+	return fmt.Sprintf("%s/%s:%d", packagePath, file, line)
+}
+
+// Ignored returns true if the issue is suppressed.
+func (i ignoreIndex) Ignored(issue *rules.Issue) bool {
+	keys := []string{issue.PackagePath}
+	if issue.File != "" {
+		keys = append(keys, ignoreKey(issue.PackagePath, issue.File, issue.Line))
+	}
+
+	for _, key := range keys {
+		names, ok := i[key]
+		if ok && (names == nil || slices.Contains(names, issue.Rule)) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,11 +1,12 @@
 package lint
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
+	"os"
 
-	"gopkg.in/yaml.v3"
+	"golang.org/x/exp/slices"
 
 	"github.com/titpetric/exp/cmd/go-fsck/internal"
 	"github.com/titpetric/exp/cmd/go-fsck/lint/rules"
@@ -25,155 +26,136 @@ func getDefinitions(cfg *options) ([]*model.Definition, error) {
 		return nil, err
 	}
 
-	defs := []*model.Definition{}
-	getDef := func(in *model.Definition) *model.Definition {
-		for _, def := range defs {
-			if def.Package.Equal(in.Package) {
-				return def
-			}
-		}
-		return nil
+	results, err := loader.LoadAll(context.Background(), packages, &loader.Options{
+		Verbose: cfg.verbose,
+		Jobs:    cfg.jobs,
+		Build:   loader.NewBuildContext(cfg.goos, cfg.goarch, cfg.tags),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defs := model.DefinitionList{}
+	for _, d := range results {
+		defs = append(defs, d...)
 	}
 
-	for _, pkg := range packages {
-		d, err := loader.Load(pkg, false, cfg.verbose)
+	defs = defs.Merge(func(d *model.Definition) string {
+		return d.ImportPath
+	})
+
+	return defs, nil
+}
+
+// newRules creates and configures the active rules.
+func newRules(cfg *options, config *Config) ([]rules.Rule, error) {
+	result := []rules.Rule{}
+	for _, name := range cfg.GetRules(config) {
+		rule, err := rules.New(name)
 		if err != nil {
 			return nil, err
 		}
 
-		for _, in := range d {
-			def := getDef(in)
-			if def != nil {
-				def.Merge(in)
-				continue
+		if configurable, ok := rule.(rules.Configurable); ok {
+			if err := configurable.Configure(config.Lint.Setting(name).Options); err != nil {
+				return nil, fmt.Errorf("error configuring lint rule %q: %w", name, err)
 			}
-			defs = append(defs, in)
 		}
+
+		result = append(result, rule)
 	}
+	return result, nil
+}
 
-	return defs, nil
+// filterIssues drops excluded and ignored issues, and sets the issue severity.
+func filterIssues(config *Config, ignores ignoreIndex, name string, issues []*rules.Issue) []*rules.Issue {
+	setting := config.Lint.Setting(name)
+
+	result := make([]*rules.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if ignores.Ignored(issue) || config.Lint.Exclude.Match(issue) || setting.Exclude.Match(issue) {
+			continue
+		}
+
+		switch {
+		case setting.Severity != "":
+			issue.Severity = setting.Severity
+		case issue.Severity == "":
+			issue.Severity = rules.SeverityError
+		}
+
+		result = append(result, issue)
+	}
+	return result
+}
+
+// result holds the issues and statistics reported by a rule.
+type result struct {
+	rule   rules.Rule
+	issues []*rules.Issue
+	stats  rules.RuleStatistics
 }
 
 func lint(cfg *options) error {
+	if !slices.Contains(formats, cfg.format) {
+		return fmt.Errorf("unknown output format %q, expected one of %v", cfg.format, formats)
+	}
+
+	config, err := LoadConfig(cfg.configFile)
+	if err != nil {
+		return err
+	}
+
+	activeRules, err := newRules(cfg, config)
+	if err != nil {
+		return err
+	}
+
 	defs, err := getDefinitions(cfg)
 	if err != nil {
 		return err
 	}
 
-	var allIssues []interface{}
-	hasErrors := false
+	ignores := newIgnoreIndex(defs)
 
-	// Run enabled linters
-	activeRules := cfg.GetRules()
-	for _, ruleName := range activeRules {
-		switch ruleName {
-		case "imports":
-			// Check import collisions (always enabled)
-			importsLinter := rules.NewImportsLinter()
-			importsLinter.Lint(defs)
-			importsIssues := importsLinter.Issues()
-			totalSymbols := len(defs)
-			if len(importsIssues) > 0 {
-				hasErrors = true
-				if cfg.jsonOut {
-					allIssues = append(allIssues, map[string]interface{}{
-						"rule":   "import-collision",
-						"issues": importsIssues,
-					})
-				} else if cfg.summarize {
-					stats := importsLinter.GetStatistics(totalSymbols)
-					yamlData, _ := yaml.Marshal(map[string]interface{}{
-						"imports": stats,
-					})
-					fmt.Print(string(yamlData))
-				} else {
-					for _, err := range importsIssues {
-						fmt.Println(err)
-					}
-				}
-			}
+	results := make([]*result, 0, len(activeRules))
+	for _, rule := range activeRules {
+		rule.Lint(defs)
 
-		case "godoc":
-			linter := rules.NewGodocLinter()
-			linter.Lint(defs)
-			issues := linter.Issues()
-			totalSymbols := len(defs) // Count all definitions as symbols for godoc
-			if len(issues) > 0 {
-				hasErrors = true
-				if cfg.jsonOut {
-					allIssues = append(allIssues, map[string]interface{}{
-						"rule":   "godoc",
-						"issues": issues,
-					})
-				} else if cfg.summarize {
-					stats := linter.GetStatistics(totalSymbols)
-					yamlData, _ := yaml.Marshal(map[string]interface{}{
-						"godoc": stats,
-					})
-					fmt.Print(string(yamlData))
-				} else {
-					for _, issue := range issues {
-						fmt.Println(issue.String())
-					}
-				}
-			}
+		results = append(results, &result{
+			rule:   rule,
+			issues: filterIssues(config, ignores, rule.Name(), rule.Issues()),
+			stats:  rule.GetStatistics(len(defs)),
+		})
+	}
 
-		case "func-args":
-			linter := rules.NewFuncArgsLinter()
-			linter.Lint(defs)
-			issues := linter.Issues()
-			if len(issues) > 0 {
-				hasErrors = true
-				if cfg.jsonOut {
-					allIssues = append(allIssues, map[string]interface{}{
-						"rule":    "func-args",
-						"issues":  issues,
-						"summary": linter.IssueSummary(),
-					})
-				} else if !cfg.summarize {
-					for _, issue := range issues {
-						fmt.Println(issue.String())
-					}
-				}
-			}
-			if cfg.summarize {
-				stats := linter.GetStatistics(len(defs))
-				yamlData, _ := yaml.Marshal(map[string]interface{}{
-					"func-args": stats,
-				})
-				fmt.Print(string(yamlData))
-			}
+	if cfg.writeBaseline {
+		return writeBaseline(cfg, results)
+	}
 
-		case "func-returns":
-			linter := rules.NewFuncReturnsLinter()
-			linter.Lint(defs)
-			issues := linter.Issues()
-			if len(issues) > 0 {
+	if cfg.baselineFile != "" {
+		baseline, err := LoadBaseline(cfg.baselineFile)
+		if err != nil {
+			return err
+		}
+		stale := baseline.Apply(results)
+		if cfg.verbose && stale > 0 {
+			fmt.Fprintf(os.Stderr, "%d baseline issues have been fixed, consider updating %s\n", stale, cfg.baselineFile)
+		}
+	}
+
+	hasErrors := false
+	for _, r := range results {
+		r.stats.ReportedIssues = len(r.issues)
+		for _, issue := range r.issues {
+			if issue.Severity == rules.SeverityError {
 				hasErrors = true
-				if cfg.jsonOut {
-					allIssues = append(allIssues, map[string]interface{}{
-						"rule":   "func-returns",
-						"issues": issues,
-					})
-				} else if !cfg.summarize {
-					for _, issue := range issues {
-						fmt.Println(issue.String())
-					}
-				}
-			}
-			if cfg.summarize {
-				stats := linter.GetStatistics(len(defs))
-				yamlData, _ := yaml.Marshal(map[string]interface{}{
-					"func-returns": stats,
-				})
-				fmt.Print(string(yamlData))
 			}
 		}
 	}
 
-	if cfg.jsonOut && len(allIssues) > 0 {
-		jsonBytes, _ := json.MarshalIndent(allIssues, "", "  ")
-		fmt.Println(string(jsonBytes))
+	if err := render(os.Stdout, cfg, results); err != nil {
+		return err
 	}
 
 	if !hasErrors {
@@ -182,3 +164,22 @@ func lint(cfg *options) error {
 
 	return errors.New("Linter not passing")
 }
+
+func writeBaseline(cfg *options, results []*result) error {
+	issues := []*rules.Issue{}
+	for _, r := range results {
+		issues = append(issues, r.issues...)
+	}
+
+	filename := cfg.baselineFile
+	if filename == "" {
+		filename = defaultBaselineFile
+	}
+
+	if err := NewBaseline(issues).Save(filename); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote %d issues to %s\n", len(issues), filename)
+	return nil
+}
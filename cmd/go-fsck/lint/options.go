@@ -4,17 +4,28 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/lint/rules"
 )
 
 type options struct {
-	verbose   bool
-	summarize bool
-	jsonOut   bool
-	rules     []string
-	exclude   []string
-	args      []string
+	verbose    bool
+	summarize  bool
+	format     string
+	configFile string
+	rules      []string
+	exclude    []string
+	args       []string
+	jobs       int
+
+	goos   string
+	goarch string
+	tags   []string
+
+	baselineFile  string
+	writeBaseline bool
 
 	fs *internal.FlagSet
 }
@@ -22,29 +33,49 @@ type options struct {
 // NewOptions parses command-line flags and returns the lint options.
 func NewOptions() *options {
 	cfg := &options{
-		rules: []string{"imports", "godoc", "func-args", "func-returns"},
+		format:     "text",
+		configFile: ".go-fsck.yml",
+		rules:      []string{"imports", "godoc", "func-args", "func-returns"},
 	}
 
 	cfg.fs = internal.NewFlagSet("lint")
 	cfg.fs.BoolVarP(&cfg.verbose, "verbose", "v", cfg.verbose, "verbose output")
+	cfg.fs.IntVarP(&cfg.jobs, "jobs", "j", cfg.jobs, "number of packages to load in parallel (default GOMAXPROCS)")
 	cfg.fs.BoolVarP(&cfg.summarize, "summarize", "", cfg.summarize, "summarize linter issues instead of raw logs")
-	cfg.fs.BoolVarP(&cfg.jsonOut, "json", "", cfg.jsonOut, "output results as JSON")
+	cfg.fs.StringVarP(&cfg.format, "format", "", cfg.format, "output format (text, json, sarif, github)")
+	jsonOut := cfg.fs.Bool("json", false, "output results as JSON (same as --format json)")
+	cfg.fs.StringVarP(&cfg.configFile, "config", "", cfg.configFile, "config file with rule settings")
+	cfg.fs.StringVarP(&cfg.baselineFile, "baseline", "", cfg.baselineFile, "baseline file with known issues to skip")
+	cfg.fs.BoolVarP(&cfg.writeBaseline, "write-baseline", "", cfg.writeBaseline, "write current issues to the baseline file (default "+defaultBaselineFile+")")
+	cfg.fs.StringVar(&cfg.goos, "goos", cfg.goos, "evaluate build constraints for GOOS (default: current)")
+	cfg.fs.StringVar(&cfg.goarch, "goarch", cfg.goarch, "evaluate build constraints for GOARCH (default: current)")
+	cfg.fs.StringSliceVar(&cfg.tags, "tags", cfg.tags, "build tags to satisfy")
 	cfg.fs.StringSliceVarP(&cfg.rules, "rules", "", cfg.rules, "linter rules to run")
 	cfg.fs.StringSliceVarP(&cfg.exclude, "exclude", "", cfg.exclude, "linter rules to exclude")
 
 	cfg.args = internal.ParseArgs(cfg.fs)
 
+	if *jsonOut {
+		cfg.format = "json"
+	}
+
 	return cfg
 }
 
-// GetRules returns the active rules after applying exclusions.
-func (o *options) GetRules() []string {
+// GetRules returns the active rules after applying exclusions. The rules
+// from the config file are used unless `--rules` is passed.
+func (o *options) GetRules(config *Config) []string {
+	active := o.rules
+	if len(config.Lint.Rules) > 0 && (o.fs == nil || !o.fs.Changed("rules")) {
+		active = config.Lint.Rules
+	}
+
 	if len(o.exclude) == 0 {
-		return o.rules
+		return active
 	}
 
-	result := make([]string, 0, len(o.rules))
-	for _, rule := range o.rules {
+	result := make([]string, 0, len(active))
+	for _, rule := range active {
 		var excluded bool
 		for _, ex := range o.exclude {
 			if ex == rule {
@@ -63,4 +94,5 @@ func (o *options) GetRules() []string {
 func (o *options) PrintHelp() {
 	fmt.Printf("Usage: %s lint <options>:\n\n", path.Base(os.Args[0]))
 	o.fs.PrintDefaults()
+	fmt.Printf("\nAvailable rules: %s\n", strings.Join(rules.Names(), ", "))
 }
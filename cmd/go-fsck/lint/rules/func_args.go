@@ -2,40 +2,14 @@ package rules
 
 import (
 	"fmt"
-	"path/filepath"
 	"strings"
 
 	"github.com/titpetric/exp/cmd/go-fsck/model"
 )
 
-// FuncArgsIssue represents a function argument ordering issue.
-type FuncArgsIssue struct {
-	File        string
-	Line        int
-	Symbol      string
-	Receiver    string
-	IssueType   string
-	Description string
-	PackagePath string
-}
-
-// String formats the func args issue as a string.
-func (f *FuncArgsIssue) String() string {
-	file := f.File
-	if f.PackagePath != "" && f.PackagePath != "." {
-		file = strings.TrimPrefix(f.PackagePath, "."+string(filepath.Separator)) + string(filepath.Separator) + file
-	}
-	loc := fmt.Sprintf("%s:%d", file, f.Line)
-	symbol := f.Symbol
-	if f.Receiver != "" {
-		symbol = f.Receiver + "." + symbol
-	}
-	return fmt.Sprintf("%s: %s %s", loc, symbol, f.Description)
-}
-
 // FuncArgsLinter checks function argument ordering.
 type FuncArgsLinter struct {
-	issues          []*FuncArgsIssue
+	issues          []*Issue
 	totalSymbols    int
 	consideredFuncs int
 	passingFuncs    int
@@ -47,12 +21,25 @@ type FuncArgsLinter struct {
 // NewFuncArgsLinter creates a new func args linter.
 func NewFuncArgsLinter() *FuncArgsLinter {
 	return &FuncArgsLinter{
-		issues:        []*FuncArgsIssue{},
+		issues:        []*Issue{},
 		argCountStats: make(map[int]int),
 		argCountValid: make(map[int]int),
 	}
 }
 
+// Name returns the rule name.
+func (fa *FuncArgsLinter) Name() string {
+	return "func-args"
+}
+
+// IssueTypes returns the issue types reported by the rule.
+func (fa *FuncArgsLinter) IssueTypes() map[string]string {
+	return map[string]string{
+		"arg-order":      "Function arguments should be ordered by type.",
+		"duplicate-type": "Functions should not take multiple arguments of the same type.",
+	}
+}
+
 // Lint checks function argument ordering in definitions.
 func (fa *FuncArgsLinter) Lint(defs []*model.Definition) {
 	fa.defs = defs // Store for interface type lookup
@@ -105,7 +92,8 @@ func (fa *FuncArgsLinter) checkFunctionArgs(def *model.Definition, decl *model.D
 		if len(variadics) > 0 {
 			expectedOrder = append(expectedOrder, variadics...)
 		}
-		fa.issues = append(fa.issues, &FuncArgsIssue{
+		fa.issues = append(fa.issues, &Issue{
+			Rule:        fa.Name(),
 			File:        decl.File,
 			Line:        decl.Line,
 			Symbol:      decl.Name,
@@ -119,7 +107,8 @@ func (fa *FuncArgsLinter) checkFunctionArgs(def *model.Definition, decl *model.D
 
 	// Check for duplicate types (in non-variadic args)
 	if hasDuplicateTypes(nonVariadic) {
-		fa.issues = append(fa.issues, &FuncArgsIssue{
+		fa.issues = append(fa.issues, &Issue{
+			Rule:        fa.Name(),
 			File:        decl.File,
 			Line:        decl.Line,
 			Symbol:      decl.Name,
@@ -154,7 +143,8 @@ func (fa *FuncArgsLinter) checkFunctionArgs(def *model.Definition, decl *model.D
 		if len(variadics) > 0 {
 			expectedOrder = append(expectedOrder, variadics...)
 		}
-		fa.issues = append(fa.issues, &FuncArgsIssue{
+		fa.issues = append(fa.issues, &Issue{
+			Rule:        fa.Name(),
 			File:        decl.File,
 			Line:        decl.Line,
 			Symbol:      decl.Name,
@@ -500,13 +490,13 @@ func isCorrectOrder(args []string, expected []string) bool {
 }
 
 // Issues returns all func args issues found.
-func (fa *FuncArgsLinter) Issues() []*FuncArgsIssue {
+func (fa *FuncArgsLinter) Issues() []*Issue {
 	return fa.issues
 }
 
 // IssueSummary returns statistics about the issues as a map for backward compatibility.
 func (fa *FuncArgsLinter) IssueSummary() map[string]interface{} {
-	stats := fa.GetStatistics()
+	stats := fa.GetStatistics(fa.totalSymbols)
 	return map[string]interface{}{
 		"total_symbols":      stats.TotalSymbols,
 		"considered_funcs":   stats.ConsideredFuncs,
@@ -521,7 +511,7 @@ func (fa *FuncArgsLinter) IssueSummary() map[string]interface{} {
 // GetStatistics returns structured statistics for YAML output.
 // Parameter totalSymbols is provided for consistency with other linters but func-args
 // tracks its own total via Lint() method.
-func (fa *FuncArgsLinter) GetStatistics(totalSymbols ...int) RuleStatistics {
+func (fa *FuncArgsLinter) GetStatistics(totalSymbols int) RuleStatistics {
 	// Build argument count breakdown
 	argBreakdown := make([]ArgCount, 0)
 	for i := 0; i <= 10; i++ {
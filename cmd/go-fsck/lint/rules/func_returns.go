@@ -2,40 +2,13 @@ package rules
 
 import (
 	"fmt"
-	"path/filepath"
-	"strings"
 
 	"github.com/titpetric/exp/cmd/go-fsck/model"
 )
 
-// FuncReturnsIssue represents a function return ordering issue.
-type FuncReturnsIssue struct {
-	File        string
-	Line        int
-	Symbol      string
-	Receiver    string
-	IssueType   string
-	Description string
-	PackagePath string
-}
-
-// String formats the func returns issue as a string.
-func (f *FuncReturnsIssue) String() string {
-	file := f.File
-	if f.PackagePath != "" && f.PackagePath != "." {
-		file = strings.TrimPrefix(f.PackagePath, "."+string(filepath.Separator)) + string(filepath.Separator) + file
-	}
-	loc := fmt.Sprintf("%s:%d", file, f.Line)
-	symbol := f.Symbol
-	if f.Receiver != "" {
-		symbol = f.Receiver + "." + symbol
-	}
-	return fmt.Sprintf("%s: %s %s", loc, symbol, f.Description)
-}
-
 // FuncReturnsLinter checks function return value ordering.
 type FuncReturnsLinter struct {
-	issues           []*FuncReturnsIssue
+	issues           []*Issue
 	totalSymbols     int
 	consideredFuncs  int
 	passingFuncs     int
@@ -46,12 +19,24 @@ type FuncReturnsLinter struct {
 // NewFuncReturnsLinter creates a new func returns linter.
 func NewFuncReturnsLinter() *FuncReturnsLinter {
 	return &FuncReturnsLinter{
-		issues:           []*FuncReturnsIssue{},
+		issues:           []*Issue{},
 		returnCountStats: make(map[int]int),
 		returnCountValid: make(map[int]int),
 	}
 }
 
+// Name returns the rule name.
+func (fr *FuncReturnsLinter) Name() string {
+	return "func-returns"
+}
+
+// IssueTypes returns the issue types reported by the rule.
+func (fr *FuncReturnsLinter) IssueTypes() map[string]string {
+	return map[string]string{
+		"return-order": "Function return values should end with bool and error.",
+	}
+}
+
 // Lint checks function return value ordering in definitions.
 func (fr *FuncReturnsLinter) Lint(defs []*model.Definition) {
 	for _, def := range defs {
@@ -94,7 +79,8 @@ func (fr *FuncReturnsLinter) checkFunctionReturns(def *model.Definition, decl *m
 	// Check ordering: error and bool should be last
 	expected := getExpectedReturnOrder(returns)
 	if !isCorrectOrder(returns, expected) {
-		fr.issues = append(fr.issues, &FuncReturnsIssue{
+		fr.issues = append(fr.issues, &Issue{
+			Rule:        fr.Name(),
 			File:        decl.File,
 			Line:        decl.Line,
 			Symbol:      decl.Name,
@@ -135,12 +121,12 @@ func getExpectedReturnOrder(returns []string) []string {
 }
 
 // Issues returns all func returns issues found.
-func (fr *FuncReturnsLinter) Issues() []*FuncReturnsIssue {
+func (fr *FuncReturnsLinter) Issues() []*Issue {
 	return fr.issues
 }
 
 // GetStatistics returns structured statistics for YAML output.
-func (fr *FuncReturnsLinter) GetStatistics(totalSymbols ...int) RuleStatistics {
+func (fr *FuncReturnsLinter) GetStatistics(totalSymbols int) RuleStatistics {
 	returnBreakdown := make([]ArgCount, 0)
 	for i := 0; i <= 10; i++ {
 		if count, ok := fr.returnCountStats[i]; ok && count > 0 {
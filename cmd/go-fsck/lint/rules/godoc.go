@@ -3,52 +3,50 @@ package rules
 
 import (
 	"fmt"
-	"path/filepath"
 	"strings"
 
 	"github.com/titpetric/exp/cmd/go-fsck/model"
 )
 
-// GodocIssue represents a godoc linting issue.
-type GodocIssue struct {
-	File        string
-	Line        int
-	Symbol      string
-	Receiver    string
-	IssueType   string
-	Description string
-	PackagePath string // Package path for better file path reporting
-}
-
-// String formats the godoc issue as a string.
-func (g *GodocIssue) String() string {
-	file := g.File
-	if g.PackagePath != "" && g.PackagePath != "." {
-		file = strings.TrimPrefix(g.PackagePath, "."+string(filepath.Separator)) + string(filepath.Separator) + file
-	}
-	loc := fmt.Sprintf("%s:%d", file, g.Line)
-	symbol := g.Symbol
-	if g.Receiver != "" {
-		symbol = g.Receiver + "." + symbol
-	}
-	return fmt.Sprintf("%s %s: %s - %s", loc, symbol, g.IssueType, g.Description)
-}
-
 // GodocLinter checks godoc compliance for exported symbols.
 type GodocLinter struct {
-	issues []*GodocIssue
+	issues []*Issue
+
+	// maxLines is the doc comment length over which a godoc-verbose issue is reported.
+	maxLines int
 }
 
 // NewGodocLinter creates a new godoc linter.
 func NewGodocLinter() *GodocLinter {
 	return &GodocLinter{
-		issues: []*GodocIssue{},
+		issues:   []*Issue{},
+		maxLines: 11,
+	}
+}
+
+// Name returns the rule name.
+func (g *GodocLinter) Name() string {
+	return "godoc"
+}
+
+// Configure sets the rule options from the config file.
+func (g *GodocLinter) Configure(options map[string]any) error {
+	opts := struct {
+		MaxLines int `yaml:"max-lines"`
+	}{
+		MaxLines: g.maxLines,
 	}
+	if err := DecodeOptions(options, &opts); err != nil {
+		return err
+	}
+	g.maxLines = opts.MaxLines
+	return nil
 }
 
-// newIssue creates a new GodocIssue with package path information.
-func (g *GodocLinter) newIssue(pkg model.Package, decl *model.Declaration, issueType, description string) *GodocIssue {
-	return &GodocIssue{
+// newIssue creates a new Issue with package path information.
+func (g *GodocLinter) newIssue(pkg model.Package, decl *model.Declaration, issueType, description string) *Issue {
+	return &Issue{
+		Rule:        g.Name(),
 		File:        decl.File,
 		Line:        decl.Line,
 		Symbol:      decl.Name,
@@ -59,6 +57,15 @@ func (g *GodocLinter) newIssue(pkg model.Package, decl *model.Declaration, issue
 	}
 }
 
+// IssueTypes returns the issue types reported by the rule.
+func (g *GodocLinter) IssueTypes() map[string]string {
+	return map[string]string{
+		"missing-godoc": "Exported symbols should have a godoc comment.",
+		"godoc-format":  "Godoc should start with the symbol name and end with punctuation.",
+		"godoc-verbose": "Godoc should not be lengthy.",
+	}
+}
+
 // Lint checks the declarations for godoc compliance.
 func (g *GodocLinter) Lint(defs []*model.Definition) {
 	for _, def := range defs {
@@ -135,13 +142,13 @@ func (g *GodocLinter) checkDeclarationList(def *model.Definition, decls model.De
 
 		// For a group of declarations: check if the first one has a comment.
 		// If it does, consider all undocumented declarations in the group as having inherited documentation.
-		firstHasDoc := strings.TrimSpace(group.decls[0].Doc) != ""
+		firstHasDoc := TrimIgnore(group.decls[0].Doc) != ""
 
 		for _, decl := range group.decls {
-			if strings.TrimSpace(decl.Doc) == "" && !firstHasDoc {
+			if TrimIgnore(decl.Doc) == "" && !firstHasDoc {
 				// No doc on this declaration and no group comment
 				g.issues = append(g.issues, g.newIssue(def.Package, decl, "missing-godoc", "exported symbol lacks godoc comment"))
-			} else if strings.TrimSpace(decl.Doc) != "" {
+			} else if TrimIgnore(decl.Doc) != "" {
 				// Check format for documented declarations
 				g.validateGodoc(def.Package, decl)
 			}
@@ -150,12 +157,11 @@ func (g *GodocLinter) checkDeclarationList(def *model.Definition, decls model.De
 }
 
 func (g *GodocLinter) validateGodoc(pkg model.Package, decl *model.Declaration) {
-	// Check if godoc exists
-	decl.Doc = strings.TrimSpace(decl.Doc)
-	doc := decl.Doc
+	// Check if godoc exists, ignoring `//go-fsck:ignore` directives
+	doc := TrimIgnore(decl.Doc)
 	symbol := decl.Name
 
-	if decl.Doc == "" {
+	if doc == "" {
 		g.issues = append(g.issues, g.newIssue(pkg, decl, "missing-godoc", "exported symbol lacks godoc comment"))
 		return
 	}
@@ -192,7 +198,7 @@ func (g *GodocLinter) validateGodoc(pkg model.Package, decl *model.Declaration)
 
 	// Count newlines (hints at overly verbose docs)
 	lineCount := strings.Count(doc, "\n")
-	if lineCount > 10 {
+	if g.maxLines > 0 && lineCount+1 > g.maxLines {
 		g.issues = append(g.issues, g.newIssue(pkg, decl, "godoc-verbose", fmt.Sprintf("godoc is lengthy (%d lines) - may indicate code smell", lineCount+1)))
 		return
 	}
@@ -203,7 +209,7 @@ func hasFinalPunctuation(ch byte) bool {
 }
 
 // Issues returns all godoc issues found.
-func (g *GodocLinter) Issues() []*GodocIssue {
+func (g *GodocLinter) Issues() []*Issue {
 	return g.issues
 }
 
@@ -0,0 +1,53 @@
+package rules
+
+import (
+	"strings"
+)
+
+// IgnoreDirective is the comment prefix used to suppress issues,
+// e.g. `//go-fsck:ignore godoc func-args`. Without rule names,
+// all rules are suppressed.
+const IgnoreDirective = "go-fsck:ignore"
+
+// ParseIgnore returns the rules suppressed by ignore directives in a
+// doc comment. An empty list with ok=true suppresses all rules.
+func ParseIgnore(doc string) (rules []string, ok bool) {
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "//"))
+		if !strings.HasPrefix(line, IgnoreDirective) {
+			continue
+		}
+
+		args := strings.TrimPrefix(line, IgnoreDirective)
+		if args != "" && args[0] != ' ' && args[0] != '\t' {
+			continue
+		}
+
+		names := strings.FieldsFunc(args, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(names) == 0 {
+			return nil, true
+		}
+		rules, ok = append(rules, names...), true
+	}
+	return rules, ok
+}
+
+// TrimIgnore removes ignore directives from a doc comment.
+func TrimIgnore(doc string) string {
+	if !strings.Contains(doc, IgnoreDirective) {
+		return strings.TrimSpace(doc)
+	}
+
+	lines := strings.Split(doc, "\n")
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "//"))
+		if strings.HasPrefix(trimmed, IgnoreDirective) {
+			continue
+		}
+		result = append(result, line)
+	}
+	return strings.TrimSpace(strings.Join(result, "\n"))
+}
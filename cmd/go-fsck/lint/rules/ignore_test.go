@@ -0,0 +1,60 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func TestParseIgnore(t *testing.T) {
+	tests := []struct {
+		doc   string
+		rules []string
+		ok    bool
+	}{
+		{"Foo does things.", nil, false},
+		{"Foo does things.\ngo-fsck:ignore godoc", []string{"godoc"}, true},
+		{"//go-fsck:ignore godoc, func-args", []string{"godoc", "func-args"}, true},
+		{"go-fsck:ignore func-args\ngo-fsck:ignore", nil, true},
+		{"go-fsck:ignored godoc", nil, false},
+	}
+
+	for _, tt := range tests {
+		rules, ok := ParseIgnore(tt.doc)
+		if ok != tt.ok || !reflect.DeepEqual(rules, tt.rules) {
+			t.Errorf("ParseIgnore(%q) = %v, %v; expected %v, %v", tt.doc, rules, ok, tt.rules, tt.ok)
+		}
+	}
+}
+
+func TestGodocLinter_Ignore(t *testing.T) {
+	linter := NewGodocLinter()
+	linter.checkDeclarationList(&model.Definition{}, model.DeclarationList{
+		{
+			Name: "Render",
+			Kind: model.FuncKind,
+			Doc:  "Render writes the output.\ngo-fsck:ignore func-args",
+			File: "render.go",
+			Line: 1,
+		},
+	})
+	if len(linter.Issues()) != 0 {
+		t.Errorf("expected ignore directive to be trimmed from godoc, got %v", linter.Issues())
+	}
+}
+
+func TestGodocLinter_Configure(t *testing.T) {
+	linter := NewGodocLinter()
+	if err := linter.Configure(map[string]any{"max-lines": 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	linter.validateGodoc(model.Package{}, &model.Declaration{
+		Name: "Render",
+		Doc:  "Render writes\nthe output\nto stdout.",
+	})
+	if len(linter.Issues()) != 1 || linter.Issues()[0].IssueType != "godoc-verbose" {
+		t.Errorf("expected godoc-verbose issue, got %v", linter.Issues())
+	}
+}
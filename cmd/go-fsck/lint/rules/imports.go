@@ -6,13 +6,25 @@ import (
 
 // ImportsLinter checks for import naming collisions.
 type ImportsLinter struct {
-	issues []error
+	issues []*Issue
 }
 
 // NewImportsLinter creates a new imports linter.
 func NewImportsLinter() *ImportsLinter {
 	return &ImportsLinter{
-		issues: []error{},
+		issues: []*Issue{},
+	}
+}
+
+// Name returns the rule name.
+func (l *ImportsLinter) Name() string {
+	return "imports"
+}
+
+// IssueTypes returns the issue types reported by the rule.
+func (l *ImportsLinter) IssueTypes() map[string]string {
+	return map[string]string{
+		"import-collision": "Import names should not collide or use inconsistent aliases.",
 	}
 }
 
@@ -20,12 +32,19 @@ func NewImportsLinter() *ImportsLinter {
 func (l *ImportsLinter) Lint(defs []*model.Definition) {
 	for _, def := range defs {
 		_, importCollisions := def.Imports.Map(def.Imports.All())
-		l.issues = append(l.issues, importCollisions...)
+		for _, err := range importCollisions {
+			l.issues = append(l.issues, &Issue{
+				Rule:        l.Name(),
+				IssueType:   "import-collision",
+				Description: err.Error(),
+				PackagePath: def.Package.Path,
+			})
+		}
 	}
 }
 
 // Issues returns all import collision issues found.
-func (l *ImportsLinter) Issues() []error {
+func (l *ImportsLinter) Issues() []*Issue {
 	return l.issues
 }
 
@@ -0,0 +1,79 @@
+package rules
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Severity is the reporting level of an issue.
+type Severity string
+
+const (
+	// SeverityError issues fail the lint run.
+	SeverityError Severity = "error"
+	// SeverityWarning issues are reported but don't fail the lint run.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo issues are informational.
+	SeverityInfo Severity = "info"
+)
+
+// Valid returns true if the severity is a known level.
+func (s Severity) Valid() bool {
+	switch s {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return true
+	}
+	return false
+}
+
+// Issue represents a single issue reported by a rule.
+type Issue struct {
+	Rule        string
+	Severity    Severity `json:",omitempty"`
+	File        string   `json:",omitempty"`
+	Line        int      `json:",omitempty"`
+	Symbol      string   `json:",omitempty"`
+	Receiver    string   `json:",omitempty"`
+	IssueType   string
+	Description string
+	PackagePath string `json:",omitempty"` // Package path for better file path reporting
+}
+
+// RuleID returns a stable identifier for the issue, in the form of
+// `rule/issue-type`, e.g. `godoc/missing-godoc`.
+func (i *Issue) RuleID() string {
+	if i.IssueType == "" {
+		return i.Rule
+	}
+	return i.Rule + "/" + i.IssueType
+}
+
+// Path returns the file path of the issue, prefixed with the package path.
+func (i *Issue) Path() string {
+	if i.File == "" || i.PackagePath == "" || i.PackagePath == "." {
+		return i.File
+	}
+	return path.Join(strings.TrimPrefix(i.PackagePath, "./"), i.File)
+}
+
+// SymbolName returns the symbol name, prefixed with the receiver for methods.
+func (i *Issue) SymbolName() string {
+	if i.Receiver == "" {
+		return i.Symbol
+	}
+	return strings.TrimLeft(i.Receiver, "*") + "." + i.Symbol
+}
+
+// String formats the issue as a string.
+func (i *Issue) String() string {
+	if i.File == "" {
+		return i.Description
+	}
+
+	loc := fmt.Sprintf("%s:%d", i.Path(), i.Line)
+	if i.Symbol == "" {
+		return fmt.Sprintf("%s: %s (%s)", loc, i.Description, i.RuleID())
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", loc, i.SymbolName(), i.Description, i.RuleID())
+}
@@ -0,0 +1,82 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// Rule is implemented by linter rules.
+type Rule interface {
+	// Name returns the rule name used with `--rules` and in the config file.
+	Name() string
+
+	// Lint checks the definitions, collecting issues.
+	Lint(defs []*model.Definition)
+
+	// Issues returns the issues found.
+	Issues() []*Issue
+
+	// GetStatistics returns structured statistics for YAML output.
+	GetStatistics(totalSymbols int) RuleStatistics
+}
+
+// Configurable is implemented by rules that take options from the config file.
+type Configurable interface {
+	Configure(options map[string]any) error
+}
+
+// Documented is implemented by rules describing the issue types they
+// report. The result is keyed by issue type.
+type Documented interface {
+	IssueTypes() map[string]string
+}
+
+var registry = map[string]func() Rule{}
+
+func init() {
+	Register("imports", func() Rule { return NewImportsLinter() })
+	Register("godoc", func() Rule { return NewGodocLinter() })
+	Register("func-args", func() Rule { return NewFuncArgsLinter() })
+	Register("func-returns", func() Rule { return NewFuncReturnsLinter() })
+}
+
+// Register adds a rule constructor to the registry. Registering a rule
+// with the name of an existing rule replaces it.
+func Register(name string, constructor func() Rule) {
+	registry[name] = constructor
+}
+
+// New creates a registered rule by name.
+func New(name string) (Rule, error) {
+	constructor, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown lint rule %q", name)
+	}
+	return constructor(), nil
+}
+
+// Names returns the sorted names of all registered rules.
+func Names() []string {
+	result := make([]string, 0, len(registry))
+	for name := range registry {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// DecodeOptions decodes rule options from the config file into dest.
+func DecodeOptions(options map[string]any, dest any) error {
+	if len(options) == 0 {
+		return nil
+	}
+	b, err := yaml.Marshal(options)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(b, dest)
+}
@@ -2,20 +2,20 @@ package rules
 
 // RuleStatistics represents unified statistics for any linter rule.
 type RuleStatistics struct {
-	TotalSymbols      int            `yaml:"total_symbols"`
-	ConsideredFuncs   int            `yaml:"considered_funcs,omitempty"`
-	PassingFuncs      int            `yaml:"passing_funcs,omitempty"`
-	ReportedIssues    int            `yaml:"reported_issues"`
-	ArgOrderIssues    int            `yaml:"arg_order_issues,omitempty"`
-	DuplicateIssues   int            `yaml:"duplicate_issues,omitempty"`
-	ImportCollisions  int            `yaml:"import_collisions,omitempty"`
-	IssueBreakdown    map[string]int `yaml:"issue_breakdown,omitempty"`
-	ArgumentBreakdown []ArgCount     `yaml:"argument_breakdown,omitempty"`
+	TotalSymbols      int            `json:"total_symbols" yaml:"total_symbols"`
+	ConsideredFuncs   int            `json:"considered_funcs,omitempty" yaml:"considered_funcs,omitempty"`
+	PassingFuncs      int            `json:"passing_funcs,omitempty" yaml:"passing_funcs,omitempty"`
+	ReportedIssues    int            `json:"reported_issues" yaml:"reported_issues"`
+	ArgOrderIssues    int            `json:"arg_order_issues,omitempty" yaml:"arg_order_issues,omitempty"`
+	DuplicateIssues   int            `json:"duplicate_issues,omitempty" yaml:"duplicate_issues,omitempty"`
+	ImportCollisions  int            `json:"import_collisions,omitempty" yaml:"import_collisions,omitempty"`
+	IssueBreakdown    map[string]int `json:"issue_breakdown,omitempty" yaml:"issue_breakdown,omitempty"`
+	ArgumentBreakdown []ArgCount     `json:"argument_breakdown,omitempty" yaml:"argument_breakdown,omitempty"`
 }
 
 // ArgCount represents the breakdown of functions by argument count.
 type ArgCount struct {
-	Arguments int `yaml:"arguments"`
-	Functions int `yaml:"functions"`
-	Valid     int `yaml:"valid"`
+	Arguments int `json:"arguments" yaml:"arguments"`
+	Functions int `json:"functions" yaml:"functions"`
+	Valid     int `json:"valid" yaml:"valid"`
 }
@@ -0,0 +1,164 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/titpetric/exp/cmd/go-fsck/lint/rules"
+)
+
+// SARIF 2.1.0 output, as consumed by code scanning dashboards.
+// Only the subset of the format used by go-fsck is modeled.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+	}
+
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		RuleIndex int             `json:"ruleIndex"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations,omitempty"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           *sarifRegion          `json:"region,omitempty"`
+	}
+
+	sarifArtifactLocation struct {
+		URI       string `json:"uri"`
+		URIBaseID string `json:"uriBaseId"`
+	}
+
+	sarifRegion struct {
+		StartLine int `json:"startLine"`
+	}
+)
+
+func renderSARIF(w io.Writer, results []*result) error {
+	driver := sarifDriver{
+		Name:           "go-fsck",
+		InformationURI: "https://github.com/titpetric/exp/tree/main/cmd/go-fsck",
+		Rules:          []sarifRule{},
+	}
+
+	// Rule descriptions, keyed by rule ID.
+	descriptions := map[string]string{}
+	for _, r := range results {
+		if documented, ok := r.rule.(rules.Documented); ok {
+			for issueType, description := range documented.IssueTypes() {
+				descriptions[r.rule.Name()+"/"+issueType] = description
+			}
+		}
+		for _, issue := range r.issues {
+			if _, ok := descriptions[issue.RuleID()]; !ok {
+				descriptions[issue.RuleID()] = issue.RuleID()
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(descriptions))
+	for id := range descriptions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	index := map[string]int{}
+	for i, id := range ids {
+		index[id] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:               id,
+			ShortDescription: sarifMessage{Text: descriptions[id]},
+		})
+	}
+
+	run := sarifRun{
+		Tool:    sarifTool{Driver: driver},
+		Results: []sarifResult{},
+	}
+
+	for _, r := range results {
+		for _, issue := range r.issues {
+			res := sarifResult{
+				RuleID:    issue.RuleID(),
+				RuleIndex: index[issue.RuleID()],
+				Level:     sarifLevel(issue.Severity),
+				Message:   sarifMessage{Text: issueMessage(issue)},
+			}
+
+			if filename := issue.Path(); filename != "" {
+				location := sarifLocation{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{
+							URI:       filename,
+							URIBaseID: "%SRCROOT%",
+						},
+					},
+				}
+				if issue.Line > 0 {
+					location.PhysicalLocation.Region = &sarifRegion{StartLine: issue.Line}
+				}
+				res.Locations = append(res.Locations, location)
+			}
+
+			run.Results = append(run.Results, res)
+		}
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+
+	b, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, string(b))
+	return nil
+}
+
+func sarifLevel(severity rules.Severity) string {
+	switch severity {
+	case rules.SeverityWarning:
+		return "warning"
+	case rules.SeverityInfo:
+		return "note"
+	}
+	return "error"
+}
@@ -10,16 +10,23 @@ import (
 
 	"golang.org/x/exp/maps"
 
+	"github.com/titpetric/exp/cmd/go-fsck/apicompat"
+	"github.com/titpetric/exp/cmd/go-fsck/arch"
 	"github.com/titpetric/exp/cmd/go-fsck/coverage"
+	"github.com/titpetric/exp/cmd/go-fsck/diff"
 	"github.com/titpetric/exp/cmd/go-fsck/docs"
 	"github.com/titpetric/exp/cmd/go-fsck/edges"
 	"github.com/titpetric/exp/cmd/go-fsck/extract"
+	"github.com/titpetric/exp/cmd/go-fsck/implements"
 	"github.com/titpetric/exp/cmd/go-fsck/jsonschema"
 	"github.com/titpetric/exp/cmd/go-fsck/lint"
+	"github.com/titpetric/exp/cmd/go-fsck/move"
 	"github.com/titpetric/exp/cmd/go-fsck/query"
 	"github.com/titpetric/exp/cmd/go-fsck/report"
 	"github.com/titpetric/exp/cmd/go-fsck/restore"
+	"github.com/titpetric/exp/cmd/go-fsck/risk"
 	"github.com/titpetric/exp/cmd/go-fsck/search"
+	"github.com/titpetric/exp/cmd/go-fsck/serve"
 	"github.com/titpetric/exp/cmd/go-fsck/sqlite"
 	"github.com/titpetric/exp/cmd/go-fsck/stats"
 	"github.com/titpetric/exp/cmd/go-fsck/test"
@@ -47,6 +54,13 @@ func start() (err error) {
 		"sqlite":     sqlite.Run,
 		"test":       test.Run,
 		"edges":      edges.Run,
+		"implements": implements.Run,
+		"diff":       diff.Run,
+		"apicompat":  apicompat.Run,
+		"move":       move.Run,
+		"serve":      serve.Run,
+		"risk":       risk.Run,
+		"arch":       arch.Run,
 	}
 	commandList := maps.Keys(commands)
 	sort.Strings(commandList)
@@ -4,7 +4,8 @@ import (
 	"strings"
 )
 
-// To type returns a sanitized type name.
+// To type returns a sanitized type name. The type arguments of
+// instantiated generic types are trimmed, `Set[string]` returns `Set`.
 func ToType(s string) (string, bool) {
 	// variadic ...
 	s = strings.TrimLeft(s, ".")
@@ -12,25 +13,8 @@ func ToType(s string) (string, bool) {
 	if strings.HasPrefix(s, "func") {
 		return "<func>", false
 	}
-	// channels
-	// implementation gap: anonymous structs
-	if strings.Contains(s, " ") {
-		ss := strings.SplitN(s, " ", 2)
-		if strings.Contains(ss[0], "chan") {
-			s = ss[1]
-		}
-	}
-	// maps, slices
-	for strings.Contains(s, "]") {
-		// Handle:
-		// - `[]` V
-		// - `map[...]` V
-		// - `.*]` V
-		ss := strings.SplitN(s, "]", 2)
-		s = ss[len(ss)-1]
-	}
-	// *T to T
-	s = strings.TrimLeft(s, "*")
+	// pointers, maps, slices, channels, type arguments
+	s = TypeRef(s)
 	if yes, _ := BuiltInTypes[s]; yes {
 		return s, false
 	}
@@ -12,11 +12,20 @@ type Declaration struct {
 
 	File string
 	Line int `json:",omitempty"`
+	// EndLine is the last line of the declaration. Like Line, it
+	// doesn't include the doc comment.
+	EndLine int `json:",omitempty"`
+
+	// Constraint is the build constraint of the declaring file,
+	// e.g. `linux && amd64` or `integration`.
+	Constraint string `json:",omitempty"`
 
 	SelfContained bool
 
 	// This is not encoded to json, it's computed on load.
 	Imports []string `json:"-"`
+	// ImportNames are the package names of the imports, computed on load.
+	ImportNames map[string]string `json:"-"`
 
 	References StringSet `json:",omitempty"`
 	Globals    StringSet `json:",omitempty"`
@@ -27,6 +36,9 @@ type Declaration struct {
 	Names    []string `json:",omitempty"`
 	Receiver string   `json:",omitempty"`
 
+	// TypeParams hold the type parameters of generic types and funcs.
+	TypeParams TypeParamList `json:",omitempty"`
+
 	Fields FieldList `json:",omitempty"`
 
 	Arguments []string `json:",omitempty"`
@@ -35,6 +47,14 @@ type Declaration struct {
 	Signature string `json:",omitempty"`
 	Source    string `json:",omitempty"`
 
+	// TypeInfo, ReceiverType, ArgumentTypes and ReturnTypes hold the
+	// go/types resolved counterparts of Type, Receiver, Arguments and
+	// Returns. They are only filled when extracting with `--typed`.
+	TypeInfo      *TypeInfo   `json:",omitempty"`
+	ReceiverType  *TypeInfo   `json:",omitempty"`
+	ArgumentTypes []*TypeInfo `json:",omitempty"`
+	ReturnTypes   []*TypeInfo `json:",omitempty"`
+
 	Complexity *Complexity `json:",omitempty"`
 }
 
@@ -74,6 +94,28 @@ func (f *Declaration) ReceiverTypeRef() string {
 	return TypeRef(f.Receiver)
 }
 
+// LastLine returns the last line of the declaration. For models without
+// EndLine, it's computed from the source without the doc comment.
+func (d *Declaration) LastLine() int {
+	if d.EndLine > 0 {
+		return d.EndLine
+	}
+
+	docLines := 0
+	if d.Doc != "" {
+		docLines = strings.Count(d.Doc, "\n") + 1
+	}
+
+	if d.Source != "" {
+		lines := strings.Count(strings.TrimRight(d.Source, "\n"), "\n") + 1
+		return d.Line + max(lines-docLines, 1) - 1
+	}
+	if d.Complexity != nil && d.Complexity.Lines > 0 {
+		return d.Line + max(d.Complexity.Lines-docLines, 1) - 1
+	}
+	return d.Line
+}
+
 func (d *Declaration) GetNames() []string {
 	if len(d.Names) > 0 {
 		return d.Names
@@ -112,15 +154,16 @@ func (d *Declaration) Equal(in *Declaration) bool {
 
 func (d *Declaration) Keys() []string {
 	trimPath := "*."
+	receiver := d.ReceiverTypeRef()
 	if d.Name != "" {
 		return []string{
-			strings.Trim(d.Receiver+"."+d.Name, trimPath),
+			strings.Trim(receiver+"."+d.Name, trimPath),
 		}
 	}
 	if len(d.Names) != 0 {
 		result := make([]string, len(d.Names))
 		for k, v := range d.Names {
-			result[k] = strings.Trim(d.Receiver+"."+v, trimPath)
+			result[k] = strings.Trim(receiver+"."+v, trimPath)
 		}
 		return result
 	}
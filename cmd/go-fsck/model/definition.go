@@ -11,6 +11,11 @@ type Definition struct {
 	Imports   StringSet `json:",omitempty"`
 	InitCount int       `json:",omitempty"`
 
+	// ImportNames maps import paths to the imported package names, for
+	// packages outside the standard library. The package name may not
+	// match the import path, e.g. `gopkg.in/yaml.v3` is `yaml`.
+	ImportNames map[string]string `json:",omitempty"`
+
 	Types  DeclarationList `json:",omitempty"`
 	Consts DeclarationList `json:",omitempty"`
 	Vars   DeclarationList `json:",omitempty"`
@@ -80,6 +85,7 @@ func (d *Definition) ClearNonTestFiles() {
 func (d *Definition) Fill() {
 	for _, decl := range d.Order() {
 		decl.Imports = d.getImports(decl)
+		decl.ImportNames = d.ImportNames
 	}
 }
 
@@ -89,6 +95,12 @@ func (d *Definition) Merge(in *Definition) {
 	for k, v := range in.Imports {
 		d.Imports.Add(k, v...)
 	}
+	for k, v := range in.ImportNames {
+		if d.ImportNames == nil {
+			d.ImportNames = map[string]string{}
+		}
+		d.ImportNames[k] = v
+	}
 
 	d.Types.AppendUnique(in.Types...)
 	d.Funcs.AppendUnique(in.Funcs...)
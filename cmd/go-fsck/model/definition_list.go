@@ -26,3 +26,21 @@ func (p DefinitionList) Filter(matchfn func(d *Definition) bool) (result []*Defi
 	}
 	return
 }
+
+// Merge merges definitions with the same key. The first definition
+// for a key is kept and the following ones are merged into it, so the
+// result keeps the order in which the keys were first seen.
+func (p DefinitionList) Merge(keyfn func(d *Definition) string) DefinitionList {
+	result := make(DefinitionList, 0, len(p))
+	seen := make(map[string]*Definition, len(p))
+	for _, def := range p {
+		key := keyfn(def)
+		if existing, ok := seen[key]; ok {
+			existing.Merge(def)
+			continue
+		}
+		seen[key] = def
+		result = append(result, def)
+	}
+	return result
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefinitionList_Merge(t *testing.T) {
+	defs := DefinitionList{
+		{Package: Package{ImportPath: "b"}, Types: DeclarationList{{Name: "B1", File: "b.go"}}},
+		{Package: Package{ImportPath: "a"}, Types: DeclarationList{{Name: "A1", File: "a.go"}}},
+		{Package: Package{ImportPath: "b", TestPackage: true}, Types: DeclarationList{{Name: "B2", File: "b.go"}}},
+	}
+
+	result := defs.Merge(func(d *Definition) string {
+		return d.ImportPath
+	})
+
+	require.Len(t, result, 2)
+	assert.Equal(t, "b", result[0].ImportPath)
+	assert.True(t, result[0].TestPackage)
+	assert.Len(t, result[0].Types, 2)
+	assert.Equal(t, "a", result[1].ImportPath)
+}
@@ -24,8 +24,15 @@ type Field struct {
 	// It's cleared if it's set to `-` (unexported).
 	JSONName string
 
+	// Embed is the embedded type reference for embedded interfaces,
+	// e.g. `io.Reader` or `~int | ~float64` for type set elements.
+	Embed string `json:",omitempty"`
+
 	// MapKey is the map key type, if this field is a map.
 	MapKey string `json:",omitempty"`
+
+	// TypeInfo is the go/types resolved type, filled with `--typed`.
+	TypeInfo *TypeInfo `json:",omitempty"`
 }
 
 func (f *Field) TypeRef() string {
@@ -0,0 +1,218 @@
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"go/build"
+	"go/build/constraint"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// BuildContext selects source files by their build constraints.
+type BuildContext struct {
+	GOOS   string
+	GOARCH string
+	Tags   []string
+
+	// CgoEnabled satisfies the `cgo` tag.
+	CgoEnabled bool
+
+	// All includes the files for all platforms and tags.
+	All bool
+}
+
+// DefaultBuildContext returns the build context of the current platform.
+func DefaultBuildContext() *BuildContext {
+	return &BuildContext{
+		GOOS:       build.Default.GOOS,
+		GOARCH:     build.Default.GOARCH,
+		Tags:       build.Default.BuildTags,
+		CgoEnabled: build.Default.CgoEnabled,
+	}
+}
+
+// NewBuildContext returns a build context for the platform. Empty
+// values default to the current platform.
+func NewBuildContext(goos, goarch string, tags []string) *BuildContext {
+	result := DefaultBuildContext()
+	if goos != "" {
+		result.GOOS = goos
+	}
+	if goarch != "" {
+		result.GOARCH = goarch
+	}
+	if goos != "" || goarch != "" {
+		result.CgoEnabled = false
+	}
+	result.Tags = append(append([]string{}, result.Tags...), tags...)
+	return result
+}
+
+// String returns the build context in the form of `goos/goarch,tag,...`.
+func (b *BuildContext) String() string {
+	if b.All {
+		return "all"
+	}
+	result := append([]string{b.GOOS + "/" + b.GOARCH}, b.Tags...)
+	if b.CgoEnabled {
+		result = append(result, "cgo")
+	}
+	return strings.Join(result, ",")
+}
+
+// Match returns true if the constraint is satisfied. An empty constraint
+// is always satisfied.
+func (b *BuildContext) Match(expr string) bool {
+	if b.All || expr == "" {
+		return true
+	}
+	x, err := constraint.Parse("//go:build " + expr)
+	if err != nil {
+		return false
+	}
+	return x.Eval(b.matchTag)
+}
+
+func (b *BuildContext) matchTag(tag string) bool {
+	switch {
+	case tag == b.GOOS || tag == b.GOARCH || tag == "gc":
+		return true
+	case tag == "cgo":
+		return b.CgoEnabled
+	case tag == "unix":
+		return unixOS[b.GOOS]
+	case tag == "linux":
+		return b.GOOS == "android"
+	case tag == "darwin":
+		return b.GOOS == "ios"
+	case tag == "solaris":
+		return b.GOOS == "illumos"
+	case strings.HasPrefix(tag, "go1."):
+		// release tags are satisfied by the current toolchain
+		return slices.Contains(build.Default.ReleaseTags, tag)
+	}
+	return slices.Contains(b.Tags, tag)
+}
+
+// FileConstraint returns the build constraint of a source file, combining
+// the `//go:build` (or `// +build`) lines with the GOOS and GOARCH file
+// name suffixes, e.g. `_linux.go`. An empty string means no constraint.
+func FileConstraint(filename string, src []byte) string {
+	var exprs []constraint.Expr
+
+	if x := headerConstraint(src); x != nil {
+		exprs = append(exprs, x)
+	}
+	for _, tag := range fileNameTags(filepath.Base(filename)) {
+		exprs = append(exprs, &constraint.TagExpr{Tag: tag})
+	}
+
+	if len(exprs) == 0 {
+		return ""
+	}
+
+	result := exprs[0]
+	for _, x := range exprs[1:] {
+		result = &constraint.AndExpr{X: result, Y: x}
+	}
+	return result.String()
+}
+
+// headerConstraint parses the build constraint lines before the package
+// clause. The `//go:build` line takes precedence over `// +build` lines.
+func headerConstraint(src []byte) constraint.Expr {
+	var (
+		goBuild    constraint.Expr
+		plusBuilds []constraint.Expr
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+		if !constraint.IsGoBuild(line) && !constraint.IsPlusBuild(line) {
+			continue
+		}
+
+		x, err := constraint.Parse(line)
+		if err != nil {
+			continue
+		}
+		if constraint.IsGoBuild(line) {
+			goBuild = x
+			continue
+		}
+		plusBuilds = append(plusBuilds, x)
+	}
+
+	if goBuild != nil || len(plusBuilds) == 0 {
+		return goBuild
+	}
+
+	result := plusBuilds[0]
+	for _, x := range plusBuilds[1:] {
+		result = &constraint.AndExpr{X: result, Y: x}
+	}
+	return result
+}
+
+// fileNameTags returns the GOOS and GOARCH tags implied by a file
+// name, following the `name_GOOS_GOARCH.go` convention.
+func fileNameTags(name string) []string {
+	name = strings.TrimSuffix(name, ".go")
+	name = strings.TrimSuffix(name, "_test")
+
+	parts := strings.Split(name, "_")
+	if len(parts) < 2 {
+		return nil
+	}
+
+	n := len(parts)
+	if n >= 3 && knownOS[parts[n-2]] && knownArch[parts[n-1]] {
+		return []string{parts[n-2], parts[n-1]}
+	}
+	if knownOS[parts[n-1]] || knownArch[parts[n-1]] {
+		return []string{parts[n-1]}
+	}
+	return nil
+}
+
+// FilterDefinitions removes declarations with build constraints not
+// satisfied by the build context, e.g. to filter a model extracted
+// with `--all-platforms`.
+func FilterDefinitions(defs []*model.Definition, build *BuildContext) {
+	match := func(d *model.Declaration) bool {
+		return build.Match(d.Constraint)
+	}
+	for _, def := range defs {
+		def.Types = def.Types.Filter(match)
+		def.Consts = def.Consts.Filter(match)
+		def.Vars = def.Vars.Filter(match)
+		def.Funcs = def.Funcs.Filter(match)
+	}
+}
+
+var unixOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true, "hurd": true,
+	"illumos": true, "ios": true, "linux": true, "netbsd": true, "openbsd": true, "solaris": true,
+}
+
+var knownOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true, "hurd": true,
+	"illumos": true, "ios": true, "js": true, "linux": true, "nacl": true, "netbsd": true,
+	"openbsd": true, "plan9": true, "solaris": true, "wasip1": true, "windows": true, "zos": true,
+}
+
+var knownArch = map[string]bool{
+	"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true,
+	"arm64be": true, "loong64": true, "mips": true, "mipsle": true, "mips64": true, "mips64le": true,
+	"mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true, "ppc64le": true, "riscv": true,
+	"riscv64": true, "s390": true, "s390x": true, "sparc": true, "sparc64": true, "wasm": true,
+}
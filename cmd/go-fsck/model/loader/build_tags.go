@@ -5,6 +5,10 @@ import (
 	"strings"
 )
 
+// BuildTags returns the tags of legacy `// +build` lines.
+//
+// Deprecated: use FileConstraint, which also handles `//go:build`
+// lines and file name suffixes.
 func BuildTags(src []byte) []string {
 	// Regular expression to match build tags in comments.
 	re := regexp.MustCompile(`(?m)^\s*//\s*\+build\s+(.*)$`)
@@ -0,0 +1,176 @@
+package loader
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func TestFileConstraint(t *testing.T) {
+	tests := []struct {
+		filename string
+		src      string
+		want     string
+	}{
+		{"store.go", "package store\n", ""},
+		{"store_linux.go", "package store\n", "linux"},
+		{"store_linux_amd64.go", "package store\n", "linux && amd64"},
+		{"store_arm64_test.go", "package store\n", "arm64"},
+		{"store_unknown.go", "package store\n", ""},
+		{"store.go", "//go:build integration\n\npackage store\n", "integration"},
+		{"store_windows.go", "//go:build !cgo || race\n\npackage store\n", "(!cgo || race) && windows"},
+		{"store.go", "// +build debug\n// +build linux darwin\n\npackage store\n", "debug && (linux || darwin)"},
+		{"store.go", "//go:build cgo\n// +build cgo\n\npackage store\n", "cgo"},
+		{"store.go", "package store\n\n//go:build ignored\n", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.filename+" "+tc.want, func(t *testing.T) {
+			assert.Equal(t, tc.want, FileConstraint(tc.filename, []byte(tc.src)))
+		})
+	}
+}
+
+func TestBuildContext_Match(t *testing.T) {
+	linux := &BuildContext{GOOS: "linux", GOARCH: "amd64", Tags: []string{"integration"}}
+
+	assert.True(t, linux.Match(""))
+	assert.True(t, linux.Match("linux && amd64"))
+	assert.True(t, linux.Match("unix"))
+	assert.True(t, linux.Match("integration"))
+	assert.True(t, linux.Match("go1.18"))
+	assert.False(t, linux.Match("windows"))
+	assert.False(t, linux.Match("cgo"))
+	assert.False(t, linux.Match("!integration"))
+
+	android := &BuildContext{GOOS: "android", GOARCH: "arm64"}
+	assert.True(t, android.Match("linux"))
+	assert.False(t, android.Match("integration"))
+
+	all := &BuildContext{All: true}
+	assert.True(t, all.Match("windows && !unix"))
+
+	assert.Equal(t, "linux/amd64,integration", linux.String())
+	assert.Equal(t, "all", all.String())
+}
+
+func TestLoadFiles(t *testing.T) {
+	dir := t.TempDir()
+	sources := map[string]string{
+		"store.go":             "package store\n\nfunc Open() {}\n",
+		"store_linux.go":       "package store\n\nfunc open() {}\n",
+		"store_windows.go":     "package store\n\nfunc open() {}\n",
+		"store_integration.go": "//go:build integration\n\npackage store\n\nfunc Seed() {}\n",
+		"gen.go":               "//go:build ignore\n\npackage main\n",
+	}
+	for name, src := range sources {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644))
+	}
+
+	// go list only parses files matching the host platform
+	fset := token.NewFileSet()
+	syntax := []*ast.File{}
+	for _, name := range []string{"store.go", "store_linux.go"} {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		require.NoError(t, err)
+		syntax = append(syntax, file)
+	}
+
+	in := &model.Package{
+		ID:         "github.com/x/store",
+		Package:    "store",
+		ImportPath: "github.com/x/store",
+		Path:       dir,
+		Pkg: &packages.Package{
+			Name:   "store",
+			Fset:   fset,
+			Syntax: syntax,
+			IgnoredFiles: []string{
+				filepath.Join(dir, "store_windows.go"),
+				filepath.Join(dir, "store_integration.go"),
+				filepath.Join(dir, "gen.go"),
+			},
+		},
+	}
+
+	funcs := func(build *BuildContext) map[string]string {
+		defs, err := LoadWithOptions(in, &Options{Build: build})
+		require.NoError(t, err)
+
+		result := map[string]string{}
+		for _, def := range defs {
+			for _, fn := range def.Funcs {
+				result[fn.File] = fn.Name + " " + fn.Constraint
+			}
+		}
+		return result
+	}
+
+	assert.Equal(t, map[string]string{
+		"store.go":       "Open ",
+		"store_linux.go": "open linux",
+	}, funcs(&BuildContext{GOOS: "linux", GOARCH: "amd64"}))
+
+	assert.Equal(t, map[string]string{
+		"store.go":             "Open ",
+		"store_windows.go":     "open windows",
+		"store_integration.go": "Seed integration",
+	}, funcs(&BuildContext{GOOS: "windows", GOARCH: "amd64", Tags: []string{"integration"}}))
+
+	assert.Equal(t, map[string]string{
+		"store.go":             "Open ",
+		"store_linux.go":       "open linux",
+		"store_windows.go":     "open windows",
+		"store_integration.go": "Seed integration",
+	}, funcs(&BuildContext{All: true}))
+}
+
+func TestFilterDefinitions(t *testing.T) {
+	defs := []*model.Definition{
+		{
+			Funcs: model.DeclarationList{
+				{Kind: model.FuncKind, Name: "Open", File: "store.go"},
+				{Kind: model.FuncKind, Name: "open", File: "store_linux.go", Constraint: "linux"},
+				{Kind: model.FuncKind, Name: "open", File: "store_windows.go", Constraint: "windows"},
+			},
+		},
+	}
+
+	FilterDefinitions(defs, &BuildContext{GOOS: "windows", GOARCH: "amd64"})
+
+	require.Len(t, defs[0].Funcs, 2)
+	assert.Equal(t, "store.go", defs[0].Funcs[0].File)
+	assert.Equal(t, "store_windows.go", defs[0].Funcs[1].File)
+}
+
+func TestImportNames(t *testing.T) {
+	pkg := &packages.Package{
+		Imports: map[string]*packages.Package{
+			"fmt":                       {ID: "fmt"},
+			"example.com/lib/parser-go": {ID: "example.com/lib/parser-go", Name: "grammar"},
+			"example.com/lib/unknown":   {ID: "example.com/lib/unknown"},
+		},
+		Types: types.NewPackage("example.com/app", "app"),
+	}
+	pkg.Types.SetImports([]*types.Package{
+		types.NewPackage("gopkg.in/yaml.v3", "yaml"),
+		types.NewPackage("strings", "strings"),
+	})
+
+	assert.Equal(t, map[string]string{
+		"example.com/lib/parser-go": "grammar",
+		"gopkg.in/yaml.v3":          "yaml",
+	}, importNames(pkg))
+
+	assert.Nil(t, importNames(&packages.Package{}))
+}
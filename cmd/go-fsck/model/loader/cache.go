@@ -0,0 +1,135 @@
+package loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// cacheVersion is part of every cache key. Bump it when the collected
+// model changes, so stale cache entries are not reused.
+const cacheVersion = "go-fsck.v5"
+
+// Cache stores loaded definitions on disk. Entries are keyed by the
+// package ID, import path, load options and the content hashes of the
+// package source files, so unchanged packages don't need to be loaded.
+type Cache struct {
+	dir string
+}
+
+// DefaultCacheDir returns the default cache location in the user cache dir.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-fsck"), nil
+}
+
+// NewCache creates a cache in dir, creating the folder if needed.
+func NewCache(dir string) (*Cache, error) {
+	// package loading changes the working directory
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Cache{
+		dir: dir,
+	}, nil
+}
+
+// Key returns the cache key for a package. The package must hold the
+// file list (in.Pkg.GoFiles), the syntax doesn't need to be loaded.
+func (c *Cache) Key(in *model.Package, opts *Options) (string, error) {
+	if in.Pkg == nil {
+		return "", fmt.Errorf("No pkg present in package: %s", in)
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n%s\ntests=%v typed=%v build=%s\n", cacheVersion, in.ID, in.ImportPath, opts.IncludeTests, opts.Typed, opts.build())
+
+	files := append(append([]string{}, in.Pkg.GoFiles...), in.Pkg.IgnoredFiles...)
+	sort.Strings(files)
+
+	for _, filename := range files {
+		if err := hashFile(hash, filename); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func hashFile(w io.Writer, filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fileHash := sha256.New()
+	if _, err := io.Copy(fileHash, f); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s %x\n", filepath.Base(filename), fileHash.Sum(nil))
+	return err
+}
+
+func (c *Cache) filename(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// Get returns the cached definitions for a key.
+func (c *Cache) Get(key string) ([]*model.Definition, bool) {
+	data, err := os.ReadFile(c.filename(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var result []*model.Definition
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, false
+	}
+
+	for _, def := range result {
+		def.Fill()
+	}
+
+	return result, true
+}
+
+// Put stores the definitions for a key.
+func (c *Cache) Put(key string, defs []*model.Definition) error {
+	data, err := json.Marshal(defs)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so readers never see partial entries
+	f, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), c.filename(key))
+}
@@ -0,0 +1,62 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func TestCache(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "model.go")
+	require.NoError(t, os.WriteFile(filename, []byte("package model\n"), 0o644))
+
+	cache, err := NewCache(filepath.Join(dir, "cache"))
+	require.NoError(t, err)
+
+	pkg := &model.Package{
+		ID:         "github.com/x/model",
+		ImportPath: "github.com/x/model",
+		Pkg:        &packages.Package{GoFiles: []string{filename}},
+	}
+	opts := &Options{}
+
+	key, err := cache.Key(pkg, opts)
+	require.NoError(t, err)
+
+	_, ok := cache.Get(key)
+	assert.False(t, ok)
+
+	defs := []*model.Definition{
+		{
+			Package: model.Package{Package: "model", ImportPath: "github.com/x/model"},
+			Types: model.DeclarationList{
+				{Kind: model.TypeKind, Name: "Model", File: "model.go"},
+			},
+		},
+	}
+	require.NoError(t, cache.Put(key, defs))
+
+	cached, ok := cache.Get(key)
+	require.True(t, ok)
+	require.Len(t, cached, 1)
+	assert.Equal(t, "Model", cached[0].Types[0].Name)
+
+	// options are part of the key
+	typedKey, err := cache.Key(pkg, &Options{Typed: true})
+	require.NoError(t, err)
+	assert.NotEqual(t, key, typedKey)
+
+	// changing the file contents invalidates the key
+	require.NoError(t, os.WriteFile(filename, []byte("package model\n\ntype Model struct{}\n"), 0o644))
+
+	changedKey, err := cache.Key(pkg, opts)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, changedKey)
+}
@@ -0,0 +1,72 @@
+package loader
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// LoadAll loads definitions from packages with a bounded pool of
+// opts.Jobs workers. The result holds the definitions for each package
+// in the order of pkgs, regardless of the order the loading completes.
+// Loading stops on the first error.
+func LoadAll(parent context.Context, pkgs []*model.Package, opts *Options) ([][]*model.Definition, error) {
+	jobs := opts.Jobs
+	if jobs <= 0 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+	jobs = min(jobs, len(pkgs))
+
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	var (
+		results = make([][]*model.Definition, len(pkgs))
+		errs    = make([]error, len(pkgs))
+		queue   = make(chan int)
+		wg      sync.WaitGroup
+	)
+
+	for range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				if ctx.Err() != nil {
+					continue
+				}
+				results[i], errs[i] = LoadWithOptions(pkgs[i], opts)
+				if errs[i] != nil {
+					cancel()
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := range pkgs {
+		select {
+		case queue <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	// report the first error in package order
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// packages may have been skipped if the parent context is done
+	if err := parent.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,67 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func newTestPackage(t *testing.T, fset *token.FileSet, name string) *model.Package {
+	t.Helper()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, name+".go")
+	src := fmt.Sprintf("package %s\n\n// %s is a test type.\ntype %s struct{}\n", name, name, "T"+name)
+	require.NoError(t, os.WriteFile(filename, []byte(src), 0o644))
+
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	require.NoError(t, err)
+
+	return &model.Package{
+		ID:         "github.com/x/" + name,
+		Package:    name,
+		ImportPath: "github.com/x/" + name,
+		Path:       dir,
+		Pkg: &packages.Package{
+			Name:   name,
+			Fset:   fset,
+			Syntax: []*ast.File{file},
+		},
+	}
+}
+
+func TestLoadAll(t *testing.T) {
+	fset := token.NewFileSet()
+
+	pkgs := []*model.Package{}
+	for i := 0; i < 10; i++ {
+		pkgs = append(pkgs, newTestPackage(t, fset, fmt.Sprintf("pkg%d", i)))
+	}
+
+	results, err := LoadAll(context.Background(), pkgs, &Options{Jobs: 4})
+	require.NoError(t, err)
+	require.Len(t, results, len(pkgs))
+
+	for i, defs := range results {
+		require.Len(t, defs, 1)
+		assert.Equal(t, pkgs[i].ImportPath, defs[0].ImportPath)
+		assert.Equal(t, fmt.Sprintf("Tpkg%d", i), defs[0].Types[0].Name)
+	}
+
+	pkgs[3].Pkg = nil
+
+	_, err = LoadAll(context.Background(), pkgs, &Options{Jobs: 4})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "github.com/x/pkg3")
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"go/ast"
+	"go/parser"
 	"log"
 	"os"
 	"path"
@@ -16,8 +17,44 @@ import (
 	"github.com/titpetric/exp/cmd/go-fsck/model"
 )
 
+// Options control the behaviour of LoadWithOptions.
+type Options struct {
+	// IncludeTests loads the test scope of the package.
+	IncludeTests bool
+	// Verbose logs loading progress.
+	Verbose bool
+	// Typed fills go/types resolved type information (`--typed`).
+	Typed bool
+	// Jobs is the number of packages LoadAll loads in parallel,
+	// defaults to GOMAXPROCS.
+	Jobs int
+	// Build selects the files by their build constraints,
+	// defaults to the current platform.
+	Build *BuildContext
+}
+
+func (o *Options) build() *BuildContext {
+	if o.Build == nil {
+		return DefaultBuildContext()
+	}
+	return o.Build
+}
+
 // Load definitions from package located in sourcePath.
 func Load(in *model.Package, includeTests bool, verbose bool) ([]*model.Definition, error) {
+	return LoadWithOptions(in, &Options{
+		IncludeTests: includeTests,
+		Verbose:      verbose,
+	})
+}
+
+// LoadWithOptions loads definitions from a package with the provided options.
+func LoadWithOptions(in *model.Package, opts *Options) ([]*model.Definition, error) {
+	var (
+		includeTests = opts.IncludeTests
+		verbose      = opts.Verbose
+	)
+
 	if in.Pkg == nil {
 		return nil, fmt.Errorf("No pkg present in package: %s", in)
 	}
@@ -45,46 +82,130 @@ func Load(in *model.Package, includeTests bool, verbose bool) ([]*model.Definiti
 	//		return nil, err
 	//	}
 
-	files := []*ast.File{}
-	//	for _, pkg := range pkgs {
-	for _, file := range pkg.Syntax {
-		filename := path.Base(fset.Position(file.Pos()).Filename)
-		if !strings.HasSuffix(filename, ".go") {
-			// skip test packages that don't end in .go
-			continue
-		}
-
-		src, err := os.ReadFile(path.Join(sourcePath, filename))
-		if err != nil {
-			return nil, fmt.Errorf("Error reading in source file: %s", filename)
-		}
-
-		if tags := BuildTags(src); len(tags) > 0 {
-			// skipped files with build tags
-			continue
-		}
-
-		files = append(files, file)
-		//		}
+	files, constraints, err := loadFiles(pkg, opts.build())
+	if err != nil {
+		return nil, err
 	}
 
 	sink := collector.NewCollector(fset)
+	if opts.Typed {
+		if pkg.TypesInfo == nil {
+			return nil, fmt.Errorf("No type information present in package: %s", in)
+		}
+		sink.WithTypesInfo(pkg.TypesInfo)
+	}
 
 	insp := inspector.New(files)
 	insp.WithStack(nil, sink.Visit)
 
 	results := sink.Clean(verbose)
 
+	for _, def := range results {
+		for _, decl := range def.DeclarationList() {
+			decl.Constraint = constraints[decl.File]
+		}
+	}
+
 	// Attach the package information to all returned definitions
+	importNames := importNames(pkg)
 	for _, def := range results {
 		def.Pkg = pkg
 		def.ImportPath = in.ImportPath
 		def.ID = in.ID
+		def.ImportNames = importNames
 	}
 
 	return results, nil
 }
 
+// importNames returns the package names of the imported packages, as
+// far as they are known from the loaded package. Standard library
+// packages are skipped, their names match the import path.
+func importNames(pkg *packages.Package) map[string]string {
+	result := map[string]string{}
+	add := func(importPath, name string) {
+		if name != "" && strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			result[importPath] = name
+		}
+	}
+
+	for importPath, imported := range pkg.Imports {
+		add(importPath, imported.Name)
+	}
+	if pkg.Types != nil {
+		for _, imported := range pkg.Types.Imports() {
+			add(imported.Path(), imported.Name())
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// loadFiles returns the package files satisfying the build context, and
+// the build constraints keyed by file name. Files excluded by the go list
+// build context are parsed, if they satisfy the build context.
+func loadFiles(pkg *packages.Package, build *BuildContext) ([]*ast.File, map[string]string, error) {
+	var (
+		files       []*ast.File
+		constraints = map[string]string{}
+	)
+
+	add := func(file *ast.File, filename string) error {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("Error reading in source file: %s", filename)
+		}
+
+		expr := FileConstraint(filename, src)
+		if !build.Match(expr) {
+			return nil
+		}
+
+		if file == nil {
+			file, err = parser.ParseFile(pkg.Fset, filename, src, parser.ParseComments)
+			if err != nil {
+				return err
+			}
+			// files for other platforms may declare another package
+			if file.Name.Name != pkg.Name {
+				return nil
+			}
+		}
+
+		if expr != "" {
+			constraints[path.Base(filename)] = expr
+		}
+		files = append(files, file)
+		return nil
+	}
+
+	for _, file := range pkg.Syntax {
+		filename := pkg.Fset.Position(file.Pos()).Filename
+		if !strings.HasSuffix(filename, ".go") {
+			// skip test packages that don't end in .go
+			continue
+		}
+		if err := add(file, filename); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	testPackage := strings.HasSuffix(pkg.Name, "_test") || strings.HasSuffix(pkg.ID, ".test]")
+	for _, filename := range pkg.IgnoredFiles {
+		if !strings.HasSuffix(filename, ".go") || (strings.HasSuffix(filename, "_test.go") && !testPackage) {
+			continue
+		}
+		if err := add(nil, filename); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return files, constraints, nil
+}
+
 // ReadFile loads the definitions from a json file
 func ReadFile(inputPath string) ([]*model.Definition, error) {
 	data, err := os.ReadFile(inputPath)
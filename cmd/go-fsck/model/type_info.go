@@ -0,0 +1,93 @@
+package model
+
+import (
+	"strings"
+)
+
+// TypeInfo holds a type identity as resolved by go/types.
+//
+// It's filled when extracting with `--typed`, and sits next to the
+// string values in Declaration and Field. Consumers can use it in
+// place of guessing with TypeRef/ToType.
+type TypeInfo struct {
+	// Type is the fully qualified type string, e.g. `[]*github.com/x/y.Z`.
+	Type string
+
+	// ImportPath is the package import path of a named type.
+	// It's empty for builtin and unnamed types.
+	ImportPath string `json:",omitempty"`
+
+	// Name is the type name for named, basic and type parameter types.
+	Name string `json:",omitempty"`
+
+	// Kind is the underlying kind of the type (struct, interface, map, slice,
+	// array, pointer, chan, func, basic, typeparam).
+	Kind string `json:",omitempty"`
+
+	// Alias is set when the type is an alias declaration.
+	Alias bool `json:",omitempty"`
+
+	// TypeParams hold the declared type parameters as `T constraint`.
+	TypeParams []string `json:",omitempty"`
+
+	// TypeArgs hold the type arguments of an instantiated generic type.
+	TypeArgs []*TypeInfo `json:",omitempty"`
+
+	// Key is the map key type.
+	Key *TypeInfo `json:",omitempty"`
+
+	// Elem is the element type for pointers, slices, arrays, maps and channels.
+	Elem *TypeInfo `json:",omitempty"`
+}
+
+// IsNamed returns true if the type is a declared type in a package.
+func (t *TypeInfo) IsNamed() bool {
+	return t != nil && t.ImportPath != "" && t.Name != ""
+}
+
+// QualifiedName returns `importpath.Name` for named types,
+// or the type string for other types.
+func (t *TypeInfo) QualifiedName() string {
+	if t == nil {
+		return ""
+	}
+	if t.IsNamed() {
+		return t.ImportPath + "." + t.Name
+	}
+	return t.Type
+}
+
+// Ref returns the named type referenced by the type info, following
+// pointers, slices, arrays, channels and map values. This is the typed
+// counterpart to TypeRef. It returns nil if no named type is referenced.
+func (t *TypeInfo) Ref() *TypeInfo {
+	for t != nil {
+		if t.IsNamed() {
+			return t
+		}
+		switch t.Kind {
+		case "pointer", "slice", "array", "chan", "map":
+			t = t.Elem
+			continue
+		}
+		return nil
+	}
+	return nil
+}
+
+// String returns the fully qualified type string.
+func (t *TypeInfo) String() string {
+	if t == nil {
+		return ""
+	}
+	return t.Type
+}
+
+// PackageName returns the last segment of the import path.
+func (t *TypeInfo) PackageName() string {
+	if t == nil || t.ImportPath == "" {
+		return ""
+	}
+	idx := strings.LastIndex(t.ImportPath, "/")
+	return t.ImportPath[idx+1:]
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"strings"
+)
+
+// TypeParam is a type parameter of a generic type or func.
+type TypeParam struct {
+	Name string
+
+	// Constraint is the type constraint as written in source,
+	// e.g. `any`, `comparable`, `fmt.Stringer` or `~int | ~string`.
+	Constraint string
+}
+
+// TypeParamList is a list of type parameters.
+type TypeParamList []*TypeParam
+
+// Names returns the type parameter names.
+func (t TypeParamList) Names() []string {
+	result := make([]string, 0, len(t))
+	for _, param := range t {
+		result = append(result, param.Name)
+	}
+	return result
+}
+
+// String returns the type parameters as declared, e.g. `[K comparable, V any]`.
+// An empty list returns an empty string.
+func (t TypeParamList) String() string {
+	if len(t) == 0 {
+		return ""
+	}
+	params := make([]string, 0, len(t))
+	for _, param := range t {
+		params = append(params, param.Name+" "+param.Constraint)
+	}
+	return "[" + strings.Join(params, ", ") + "]"
+}
+
+// Constraints returns the type references used in the constraints.
+// Union terms are split and the `~` approximation is trimmed, so
+// `~int | ~string` returns `int` and `string`. Inline interfaces
+// are not returned.
+func (t TypeParamList) Constraints() []string {
+	var result []string
+	for _, param := range t {
+		for _, term := range splitTopLevel(param.Constraint, '|') {
+			term = strings.TrimPrefix(strings.TrimSpace(term), "~")
+			if term == "" || strings.HasPrefix(term, "interface{") {
+				continue
+			}
+			result = appendUnique(result, term)
+		}
+	}
+	return result
+}
+
+// TypeArgs returns the type arguments of an instantiated generic type,
+// e.g. `*Set[string]` returns `string` and `Map[K, []V]` returns `K`
+// and `[]V`. Pointers, slices, maps and channels are followed the same
+// way as with TypeRef.
+func TypeArgs(name string) []string {
+	name = trimTypeModifiers(name)
+	if strings.HasPrefix(name, "func") || strings.HasPrefix(name, "struct{") || strings.HasPrefix(name, "interface{") {
+		return nil
+	}
+
+	start := strings.Index(name, "[")
+	if start == -1 || !strings.HasSuffix(name, "]") {
+		return nil
+	}
+
+	args := splitTopLevel(name[start+1:len(name)-1], ',')
+	for i, arg := range args {
+		args[i] = strings.TrimSpace(arg)
+	}
+	return args
+}
+
+// splitTopLevel splits s by sep, ignoring separators nested in brackets,
+// parentheses or braces.
+func splitTopLevel(s string, sep byte) []string {
+	var (
+		result []string
+		depth  int
+		last   int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case sep:
+			if depth == 0 {
+				result = append(result, s[last:i])
+				last = i + 1
+			}
+		}
+	}
+	return append(result, s[last:])
+}
+
+// closingBracket returns the index of the bracket closing the one at
+// s[0], or -1 if the brackets are not balanced.
+func closingBracket(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeRef(t *testing.T) {
+	tests := map[string]string{
+		"string":                  "string",
+		"*Store":                  "Store",
+		"...model.Item":           "model.Item",
+		"[]*model.Item":           "model.Item",
+		"[4]int":                  "int",
+		"map[string]*Store":       "Store",
+		"map[Key[int]]Value":      "Value",
+		"chan<- Event":            "Event",
+		"List[T]":                 "List",
+		"*Allocator[T]":           "Allocator",
+		"Map[K, V]":               "Map",
+		"[]Set[string]":           "Set",
+		"map[string]Pair[K, []V]": "Pair",
+		"cache.Map[string, int]":  "cache.Map",
+		"func() T":                "func() T",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, TypeRef(in), in)
+	}
+}
+
+func TestToType(t *testing.T) {
+	name, ok := ToType("*Set[string]")
+	assert.Equal(t, "Set", name)
+	assert.True(t, ok)
+
+	name, ok = ToType("[]map[string]int")
+	assert.Equal(t, "int", name)
+	assert.False(t, ok)
+
+	name, ok = ToType("chan *Event")
+	assert.Equal(t, "Event", name)
+	assert.True(t, ok)
+}
+
+func TestTypeArgs(t *testing.T) {
+	assert.Nil(t, TypeArgs("Store"))
+	assert.Nil(t, TypeArgs("[]int"))
+	assert.Equal(t, []string{"string"}, TypeArgs("*Set[string]"))
+	assert.Equal(t, []string{"K", "[]V"}, TypeArgs("map[string]Map[K, []V]"))
+	assert.Equal(t, []string{"Pair[K, V]", "int"}, TypeArgs("cache.Map[Pair[K, V], int]"))
+}
+
+func TestTypeParamList(t *testing.T) {
+	params := TypeParamList{
+		{Name: "K", Constraint: "comparable"},
+		{Name: "V", Constraint: "~int | ~string | fmt.Stringer"},
+		{Name: "R", Constraint: "interface{ Reset() }"},
+	}
+
+	assert.Equal(t, "[K comparable, V ~int | ~string | fmt.Stringer, R interface{ Reset() }]", params.String())
+	assert.Equal(t, []string{"K", "V", "R"}, params.Names())
+	assert.Equal(t, []string{"comparable", "int", "string", "fmt.Stringer"}, params.Constraints())
+	assert.Equal(t, "", TypeParamList(nil).String())
+}
+
+func TestDeclaration_Keys(t *testing.T) {
+	decl := &Declaration{Kind: FuncKind, Name: "Get", Receiver: "*Allocator[T]"}
+	assert.Equal(t, []string{"Allocator.Get"}, decl.Keys())
+	assert.Equal(t, "Allocator", decl.ReceiverTypeRef())
+}
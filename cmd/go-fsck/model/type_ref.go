@@ -4,19 +4,43 @@ import (
 	"strings"
 )
 
-// TypeRef aims to trim a type name to a reference type.
+// TypeRef aims to trim a type name to a reference type. Pointers,
+// variadics, slices, arrays, map values, channels and the type arguments
+// of instantiated generic types are trimmed, e.g. `map[string]*Set[int]`
+// returns `Set`.
 func TypeRef(name string) string {
-	// trim variadic arg
-	if strings.HasPrefix(name, "...") {
-		name = name[3:]
+	name = trimTypeModifiers(name)
+	if strings.HasPrefix(name, "func") || strings.HasPrefix(name, "struct{") || strings.HasPrefix(name, "interface{") {
+		return name
 	}
 
-	// slice, array and map value
-	// in terms of nesting, this is a hack
-	if strings.HasPrefix(name, "[") || strings.HasPrefix(name, "map[") {
-		name = strings.SplitN(name, "]", 2)[1]
+	// instantiated generic type
+	if idx := strings.Index(name, "["); idx > 0 {
+		name = name[:idx]
 	}
+	return name
+}
 
-	// deref pointers
-	return strings.TrimPrefix(name, "*")
+// trimTypeModifiers trims the pointer, variadic, slice, array, map and
+// channel prefixes from a type name. Nested brackets are skipped, so
+// `map[Key[int]]V` returns `V`.
+func trimTypeModifiers(name string) string {
+	name = strings.TrimPrefix(name, "...")
+	for {
+		switch {
+		case strings.HasPrefix(name, "*"):
+			name = name[1:]
+		case strings.HasPrefix(name, "["), strings.HasPrefix(name, "map["):
+			start := strings.Index(name, "[")
+			end := closingBracket(name[start:])
+			if end == -1 {
+				return name
+			}
+			name = name[start+end+1:]
+		case strings.HasPrefix(name, "chan "), strings.HasPrefix(name, "<-chan "), strings.HasPrefix(name, "chan<- "):
+			name = strings.TrimSpace(name[strings.Index(name, " ")+1:])
+		default:
+			return name
+		}
+	}
 }
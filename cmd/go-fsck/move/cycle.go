@@ -0,0 +1,102 @@
+package move
+
+import (
+	"strings"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// importGraph maps import paths to the imported paths.
+type importGraph map[string]map[string]bool
+
+// newImportGraph builds the import graph of the model. Imports from
+// test files are skipped, as external test packages can't form cycles.
+func newImportGraph(defs []*model.Definition) importGraph {
+	result := importGraph{}
+	for _, def := range defs {
+		if def.TestPackage {
+			continue
+		}
+		for filename, imports := range def.Imports {
+			if strings.HasSuffix(filename, "_test.go") {
+				continue
+			}
+			for _, literal := range imports {
+				fields := strings.Fields(literal)
+				result.add(def.ImportPath, strings.Trim(fields[len(fields)-1], `"`))
+			}
+		}
+	}
+	return result
+}
+
+func (g importGraph) add(from, to string) {
+	if from == to {
+		return
+	}
+	if g[from] == nil {
+		g[from] = map[string]bool{}
+	}
+	g[from][to] = true
+}
+
+// cycle returns an import path cycle starting and ending at start,
+// or nil if there is none.
+func (g importGraph) cycle(start string) []string {
+	visited := map[string]bool{}
+
+	var visit func(node string, path []string) []string
+	visit = func(node string, path []string) []string {
+		for _, next := range internal.SortedKeys(g[node]) {
+			if next == start {
+				return append(path, next)
+			}
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			if result := visit(next, append(path, next)); result != nil {
+				return result
+			}
+		}
+		return nil
+	}
+
+	return visit(start, []string{start})
+}
+
+// modelImporters returns the packages in the model referencing any of
+// the names in the package importPath, based on the function references.
+func modelImporters(defs []*model.Definition, importPath string, names map[string]bool) []string {
+	var result []string
+	for _, def := range defs {
+		if def.ImportPath == importPath || def.TestPackage {
+			continue
+		}
+		if referencesAny(def, importPath, names) {
+			result = append(result, def.ImportPath)
+		}
+	}
+	return result
+}
+
+func referencesAny(def *model.Definition, importPath string, names map[string]bool) bool {
+	for _, decl := range def.Funcs {
+		if decl.IsTestScope() {
+			continue
+		}
+		imports, _ := def.Imports.Map(def.Imports.Get(decl.File))
+		for pkgName, symbols := range decl.References {
+			if imports[pkgName] != importPath {
+				continue
+			}
+			for _, symbol := range symbols {
+				if names[symbol] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package move
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"maps"
+	"strconv"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/imports"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// importSpec is an import path with an optional name.
+type importSpec struct {
+	Name string
+	Path string
+}
+
+// fileImportSpecs returns the imports of a file.
+func fileImportSpecs(file *ast.File) []importSpec {
+	result := make([]importSpec, 0, len(file.Imports))
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		var name string
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		result = append(result, importSpec{Name: name, Path: importPath})
+	}
+	return result
+}
+
+// importName returns the name a file refers to an import by.
+func importName(spec importSpec, known map[string]string) string {
+	if spec.Name != "" {
+		return spec.Name
+	}
+	if name, ok := known[spec.Path]; ok {
+		return name
+	}
+	return internal.PackageName(spec.Path)
+}
+
+// resolveImportNames collects the package names of the imports used by
+// the rewritten files. The names come from the model, imports outside
+// the standard library which the model doesn't know are loaded, as the
+// package name may not match the import path, e.g. `k8s.io/api/core/v1`
+// is `v1`.
+func (p *plan) resolveImportNames(defs []*model.Definition) error {
+	for _, def := range defs {
+		maps.Copy(p.importNames, def.ImportNames)
+	}
+
+	files := append(append([]*goFile{}, p.src.files...), p.dst.files...)
+	for _, imp := range p.importers {
+		files = append(files, imp.goFile)
+	}
+
+	var unknown []string
+	for _, f := range files {
+		for _, spec := range fileImportSpecs(f.file) {
+			if _, ok := p.importNames[spec.Path]; ok || spec.Name != "" || internal.IsStdlib(spec.Path) {
+				continue
+			}
+			p.importNames[spec.Path] = internal.PackageName(spec.Path)
+			unknown = append(unknown, spec.Path)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName,
+		Dir:  p.moduleRoot,
+	}, unknown...)
+	if err != nil {
+		return err
+	}
+	for _, pkg := range pkgs {
+		if pkg.Name != "" {
+			p.importNames[pkg.PkgPath] = pkg.Name
+		}
+	}
+	return nil
+}
+
+// fixImports adds imports to the source, removes unused imports and
+// formats the result with grouped imports. Known maps import paths to
+// package names, for imports where the package name can't be guessed
+// from the path. It returns false if the file has no declarations
+// besides imports.
+func fixImports(filename string, src []byte, add []importSpec, known map[string]string) ([]byte, bool, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, spec := range add {
+		astutil.AddNamedImport(fset, file, spec.Name, spec.Path)
+	}
+
+	used := map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil {
+				used[x.Name] = true
+			}
+		}
+		return true
+	})
+
+	for _, spec := range fileImportSpecs(file) {
+		name := importName(spec, known)
+		if name == "_" || name == "." || name == "C" || used[name] {
+			continue
+		}
+		astutil.DeleteNamedImport(fset, file, spec.Name, spec.Path)
+	}
+
+	hasDecls := false
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			continue
+		}
+		hasDecls = true
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, false, err
+	}
+
+	// Sort and group the imports, standard library first.
+	result, err := imports.Process(filename, buf.Bytes(), &imports.Options{
+		Comments:   true,
+		TabIndent:  true,
+		TabWidth:   8,
+		FormatOnly: true,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return result, hasDecls, nil
+}
@@ -0,0 +1,184 @@
+package move
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+)
+
+// member is a field or method declared on a package level type.
+type member struct {
+	owner string
+	ident *ast.Ident
+}
+
+// declMembers returns the fields and methods declared by decl. Struct
+// fields, interface methods and method declarations are included,
+// embedded fields are not.
+func declMembers(decl ast.Decl) []member {
+	var result []member
+	switch decl := decl.(type) {
+	case *ast.GenDecl:
+		if decl.Tok != token.TYPE {
+			return nil
+		}
+		for _, spec := range decl.Specs {
+			spec := spec.(*ast.TypeSpec)
+
+			var fields *ast.FieldList
+			switch t := spec.Type.(type) {
+			case *ast.StructType:
+				fields = t.Fields
+			case *ast.InterfaceType:
+				fields = t.Methods
+			default:
+				continue
+			}
+			for _, field := range fields.List {
+				for _, name := range field.Names {
+					result = append(result, member{spec.Name.Name, name})
+				}
+			}
+		}
+	case *ast.FuncDecl:
+		if owner := receiverName(decl); owner != "" {
+			result = append(result, member{owner, decl.Name})
+		}
+	}
+	return result
+}
+
+// memberRefs returns the selectors and keyed struct literal fields in
+// node, for which the name is in names. Without type information, a
+// name refers to any member declared with that name.
+func memberRefs(node ast.Node, names map[string]string) []*ast.Ident {
+	var result []*ast.Ident
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if _, ok := names[n.Sel.Name]; ok {
+				result = append(result, n.Sel)
+			}
+		case *ast.CompositeLit:
+			if _, ok := n.Type.(*ast.MapType); ok {
+				return true
+			}
+			for _, elt := range n.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok {
+						if _, ok := names[key.Name]; ok {
+							result = append(result, key)
+						}
+					}
+				}
+			}
+		}
+		return true
+	})
+	return result
+}
+
+// analyzeMembers collects the unexported fields and methods used
+// across the package boundary. A moved type with an unexported member
+// used by the source package, or a remaining type with an unexported
+// member used by the moved code, needs the member exported.
+func (p *plan) analyzeMembers() error {
+	// owners maps member names to the declaring types.
+	owners := map[string]map[string]bool{}
+	p.eachDecl(func(_ *goFile, decl ast.Decl) {
+		for _, m := range declMembers(decl) {
+			if owners[m.ident.Name] == nil {
+				owners[m.ident.Name] = map[string]bool{}
+			}
+			owners[m.ident.Name][m.owner] = true
+		}
+	})
+
+	unexported := map[string]string{}
+	for name := range owners {
+		if !ast.IsExported(name) {
+			unexported[name] = name
+		}
+	}
+
+	p.eachDecl(func(_ *goFile, decl ast.Decl) {
+		isMoved := p.movedDecls[decl]
+		for _, ident := range memberRefs(decl, unexported) {
+			for owner := range owners[ident.Name] {
+				if p.moved[owner] != isMoved {
+					p.memberUses[ident.Name] = true
+				}
+			}
+		}
+	})
+
+	var ambiguous []string
+	for _, name := range internal.SortedKeys(p.memberUses) {
+		var moved, remaining []string
+		for _, owner := range internal.SortedKeys(owners[name]) {
+			if p.moved[owner] {
+				moved = append(moved, owner+"."+name)
+			} else {
+				remaining = append(remaining, owner+"."+name)
+			}
+		}
+		if len(moved) > 0 && len(remaining) > 0 {
+			ambiguous = append(ambiguous, fmt.Sprintf("  %s is moved, %s is not", strings.Join(moved, ", "), strings.Join(remaining, ", ")))
+		}
+	}
+	if len(ambiguous) > 0 {
+		report := []string{"refusing to move, can't export members declared on moved and remaining types, rename them first:"}
+		return errors.New(strings.Join(append(report, ambiguous...), "\n"))
+	}
+
+	p.memberOwners = owners
+	return nil
+}
+
+// resolveMemberExports exports the members used across the packages
+// and checks for name conflicts on the declaring types.
+func (p *plan) resolveMemberExports() error {
+	var conflicts []string
+	for _, name := range internal.SortedKeys(p.memberUses) {
+		exported := strings.ToUpper(name[:1]) + name[1:]
+		if !ast.IsExported(exported) {
+			return fmt.Errorf("can't export %s", name)
+		}
+		for owner := range p.memberOwners[name] {
+			if p.memberOwners[exported][owner] {
+				conflicts = append(conflicts, fmt.Sprintf("exporting %s.%s conflicts with %s.%s", owner, name, owner, exported))
+			}
+		}
+		p.memberExports[name] = exported
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return errors.New(strings.Join(conflicts, "\n"))
+	}
+	return nil
+}
+
+// memberEdits renames the exported members in decl.
+func (p *plan) memberEdits(decl ast.Decl) []edit {
+	if len(p.memberExports) == 0 {
+		return nil
+	}
+
+	idents := memberRefs(decl, p.memberExports)
+	for _, m := range declMembers(decl) {
+		if _, ok := p.memberExports[m.ident.Name]; ok {
+			idents = append(idents, m.ident)
+		}
+	}
+
+	result := make([]edit, 0, len(idents))
+	for _, ident := range idents {
+		result = append(result, edit{p.offset(ident.Pos()), p.offset(ident.End()), p.memberExports[ident.Name]})
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+package move
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
+)
+
+func getDefinitions(cfg *options) ([]*model.Definition, error) {
+	// Read the exported go-fsck.json data.
+	defs, err := loader.ReadFile(cfg.inputFile)
+	if err == nil {
+		return defs, nil
+	}
+
+	// list current local packages
+	packages, err := internal.ListPackages(".", "./...")
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := loader.LoadAll(context.Background(), packages, &loader.Options{
+		Verbose: cfg.verbose,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defs = []*model.Definition{}
+	for _, d := range results {
+		defs = append(defs, d...)
+	}
+
+	return defs, nil
+}
+
+func move(cfg *options) error {
+	if len(cfg.symbols) == 0 {
+		return errors.New("no symbols to move, use --symbols")
+	}
+	if cfg.to == "" {
+		return errors.New("no destination package, use --to")
+	}
+
+	defs, err := getDefinitions(cfg)
+	if err != nil {
+		return err
+	}
+
+	p, err := newPlan(cfg)
+	if err != nil {
+		return err
+	}
+	if err := p.selectDecls(cfg.symbols); err != nil {
+		return err
+	}
+	if err := p.analyze(); err != nil {
+		return err
+	}
+	if err := p.scanModule(); err != nil {
+		return err
+	}
+	if err := p.resolveImportNames(defs); err != nil {
+		return err
+	}
+	if err := p.checkCycles(defs); err != nil {
+		return err
+	}
+	if err := p.resolveExports(); err != nil {
+		return err
+	}
+	if err := p.rewrite(); err != nil {
+		return err
+	}
+
+	if cfg.dryRun || cfg.verbose {
+		p.print()
+	}
+	if cfg.dryRun {
+		return nil
+	}
+
+	return p.write()
+}
+
+// write writes the changed files and removes the emptied ones.
+func (p *plan) write() error {
+	if err := os.MkdirAll(p.dst.dir, 0o755); err != nil {
+		return err
+	}
+	for _, filename := range internal.SortedKeys(p.output) {
+		body := p.output[filename]
+		if body == nil {
+			if err := os.Remove(filename); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.WriteFile(filename, body, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// print prints the moved symbols, the exported names and changed files.
+func (p *plan) print() {
+	moved := internal.SortedKeys(p.moved)
+	for i, name := range moved {
+		moved[i] = p.exportName(name)
+	}
+	sort.Strings(moved)
+
+	fmt.Printf("Moving %s -> %s: %s\n", p.srcPath, p.dstPath, strings.Join(moved, ", "))
+
+	for _, name := range internal.SortedKeys(p.exports) {
+		fmt.Printf("Exporting %s as %s\n", name, p.exports[name])
+	}
+	for _, name := range internal.SortedKeys(p.memberExports) {
+		fmt.Printf("Exporting %s as %s on %s\n", name, p.memberExports[name], strings.Join(internal.SortedKeys(p.memberOwners[name]), ", "))
+	}
+
+	cwd, _ := os.Getwd()
+	for _, filename := range internal.SortedKeys(p.output) {
+		status := "M"
+		if p.output[filename] == nil {
+			status = "D"
+		} else if _, err := os.Stat(filename); err != nil {
+			status = "A"
+		}
+		if rel, err := filepath.Rel(cwd, filename); err == nil {
+			filename = rel
+		}
+		fmt.Println(status, filename)
+	}
+}
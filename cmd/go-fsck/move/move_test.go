@@ -0,0 +1,270 @@
+package move
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func writeModule(t *testing.T, files map[string]string) {
+	t.Helper()
+
+	t.Chdir(t.TempDir())
+	files["go.mod"] = "module example.com/app\n\ngo 1.21\n"
+	for filename, contents := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o755))
+		require.NoError(t, os.WriteFile(filename, []byte(contents), 0o644))
+	}
+
+	data, err := json.Marshal([]*model.Definition{})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile("go-fsck.json", data, 0o644))
+}
+
+func readFile(t *testing.T, filename string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestMove(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	writeModule(t, map[string]string{
+		"store/store.go": `package store
+
+import "strings"
+
+// Foo returns a greeting.
+func Foo() string {
+	return strings.ToUpper(helper()) + suffix
+}
+
+func helper() string { return "hello" }
+
+const suffix = "!"
+
+func Keep() string { return helper() }
+`,
+		"store/store_test.go": `package store
+
+import "testing"
+
+func TestFoo(t *testing.T) {
+	if Foo() == "" {
+		t.Fail()
+	}
+}
+
+func TestKeep(t *testing.T) {
+	if Keep() == "" {
+		t.Fail()
+	}
+}
+`,
+		"api/api.go": `package api
+
+import "example.com/app/store"
+
+func Hello() string {
+	return store.Foo() + store.Keep()
+}
+`,
+	})
+
+	cfg := &options{
+		inputFile: "go-fsck.json",
+		symbols:   []string{"Foo"},
+		from:      "./store",
+		to:        "./internal/greet",
+	}
+	require.NoError(t, move(cfg))
+
+	greet := readFile(t, "internal/greet/store.go")
+	assert.Contains(t, greet, "package greet")
+	assert.Contains(t, greet, `"example.com/app/store"`)
+	assert.Contains(t, greet, "// Foo returns a greeting.\nfunc Foo() string {")
+	assert.Contains(t, greet, "strings.ToUpper(store.Helper()) + store.Suffix")
+
+	store := readFile(t, "store/store.go")
+	assert.NotContains(t, store, "func Foo")
+	assert.NotContains(t, store, `"strings"`)
+	assert.Contains(t, store, "func Helper() string")
+	assert.Contains(t, store, "const Suffix")
+	assert.Contains(t, store, "return Helper()")
+
+	assert.Contains(t, readFile(t, "internal/greet/store_test.go"), "func TestFoo(t *testing.T)")
+	assert.NotContains(t, readFile(t, "store/store_test.go"), "TestFoo")
+
+	api := readFile(t, "api/api.go")
+	assert.Contains(t, api, `"example.com/app/internal/greet"`)
+	assert.Contains(t, api, "greet.Foo() + store.Keep()")
+
+	out, err := exec.Command("go", "vet", "./...").CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestMove_importNames(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	writeModule(t, map[string]string{
+		"api/core/v1/types.go": `package v1
+
+type Pod struct{}
+`,
+		"store/store.go": `package store
+
+import "example.com/app/api/core/v1"
+
+func Foo() v1.Pod { return v1.Pod{} }
+
+func Keep() v1.Pod { return Foo() }
+`,
+	})
+
+	cfg := &options{
+		inputFile: "go-fsck.json",
+		symbols:   []string{"Foo"},
+		from:      "./store",
+		to:        "./internal/greet",
+	}
+	require.NoError(t, move(cfg))
+
+	assert.Contains(t, readFile(t, "internal/greet/store.go"), `"example.com/app/api/core/v1"`)
+	assert.Contains(t, readFile(t, "store/store.go"), `"example.com/app/api/core/v1"`)
+
+	out, err := exec.Command("go", "vet", "./...").CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestMove_members(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	writeModule(t, map[string]string{
+		"counter/counter.go": `package counter
+
+type foo struct {
+	n int
+}
+
+func (f *foo) inc() { f.n++ }
+
+func Use() int {
+	f := &foo{n: 1}
+	f.inc()
+	return f.n
+}
+`,
+	})
+
+	cfg := &options{
+		inputFile: "go-fsck.json",
+		symbols:   []string{"foo"},
+		from:      "./counter",
+		to:        "./internal/count",
+	}
+	require.NoError(t, move(cfg))
+
+	count := readFile(t, "internal/count/counter.go")
+	assert.Contains(t, count, "type Foo struct {\n\tN int\n}")
+	assert.Contains(t, count, "func (f *Foo) Inc() { f.N++ }")
+
+	counter := readFile(t, "counter/counter.go")
+	assert.Contains(t, counter, "f := &count.Foo{N: 1}")
+	assert.Contains(t, counter, "f.Inc()")
+	assert.Contains(t, counter, "return f.N")
+
+	out, err := exec.Command("go", "vet", "./...").CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestMove_membersAmbiguous(t *testing.T) {
+	writeModule(t, map[string]string{
+		"counter/counter.go": `package counter
+
+type foo struct {
+	n int
+}
+
+type bar struct {
+	n int
+}
+
+func Use() int {
+	f := &foo{n: 1}
+	return f.n + bar{}.n
+}
+`,
+	})
+
+	cfg := &options{
+		inputFile: "go-fsck.json",
+		symbols:   []string{"foo"},
+		from:      "./counter",
+		to:        "./internal/count",
+	}
+
+	err := move(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refusing to move, can't export members declared on moved and remaining types")
+	assert.Contains(t, err.Error(), "foo.n is moved, bar.n is not")
+	assert.NoDirExists(t, "internal/count")
+}
+
+func TestMove_cycle(t *testing.T) {
+	writeModule(t, map[string]string{
+		"store/store.go": `package store
+
+func Foo() string { return helper() }
+
+func helper() string { return "hello" }
+
+func Keep() string { return Foo() }
+`,
+	})
+
+	cfg := &options{
+		inputFile: "go-fsck.json",
+		symbols:   []string{"Foo"},
+		from:      "./store",
+		to:        "./internal/greet",
+	}
+
+	err := move(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refusing to move, import cycle: example.com/app/internal/greet -> example.com/app/store -> example.com/app/internal/greet")
+	assert.Contains(t, err.Error(), "example.com/app/internal/greet uses example.com/app/store: helper")
+	assert.Contains(t, err.Error(), "example.com/app/store uses example.com/app/internal/greet: Foo")
+
+	assert.NoDirExists(t, "internal/greet")
+	assert.Contains(t, readFile(t, "store/store.go"), "func Foo()")
+}
+
+func TestMove_notFound(t *testing.T) {
+	writeModule(t, map[string]string{
+		"store/store.go": "package store\n\nfunc Foo() {}\n",
+	})
+
+	cfg := &options{
+		inputFile: "go-fsck.json",
+		symbols:   []string{"Bar"},
+		from:      "./store",
+		to:        "./internal/greet",
+	}
+	assert.EqualError(t, move(cfg), "symbol Bar not found in example.com/app/store")
+}
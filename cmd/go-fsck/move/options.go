@@ -0,0 +1,47 @@
+package move
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+)
+
+type options struct {
+	inputFile string
+	symbols   []string
+	from      string
+	to        string
+
+	dryRun  bool
+	verbose bool
+
+	fs *internal.FlagSet
+}
+
+// NewOptions parses command-line flags and returns the move options.
+func NewOptions() *options {
+	cfg := &options{
+		inputFile: "go-fsck.json",
+		from:      ".",
+	}
+
+	cfg.fs = internal.NewFlagSet("move")
+	cfg.fs.StringVarP(&cfg.inputFile, "input-file", "i", cfg.inputFile, "input file (go-fsck extract ./...)")
+	cfg.fs.StringSliceVar(&cfg.symbols, "symbols", cfg.symbols, "symbols to move (comma separated)")
+	cfg.fs.StringVar(&cfg.from, "from", cfg.from, "source package folder")
+	cfg.fs.StringVar(&cfg.to, "to", cfg.to, "destination package folder, e.g. ./internal/foo")
+	cfg.fs.BoolVar(&cfg.dryRun, "dry-run", cfg.dryRun, "print the changes without writing files")
+	cfg.fs.BoolVarP(&cfg.verbose, "verbose", "v", cfg.verbose, "verbose output")
+
+	_ = internal.ParseArgs(cfg.fs)
+
+	return cfg
+}
+
+// PrintHelp displays usage information for the move command.
+func (o *options) PrintHelp() {
+	fmt.Printf("Usage: %s move <options>:\n\n", path.Base(os.Args[0]))
+	o.fs.PrintDefaults()
+}
@@ -0,0 +1,219 @@
+package move
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// goFile is a parsed source file.
+type goFile struct {
+	filename string
+	src      []byte
+	file     *ast.File
+}
+
+// isTest returns true for _test.go files.
+func (f *goFile) isTest() bool {
+	return strings.HasSuffix(f.filename, "_test.go")
+}
+
+// goPackage holds the parsed files of a package folder.
+type goPackage struct {
+	dir  string
+	name string
+
+	// files are the package files, including internal tests.
+	files []*goFile
+
+	// external are the files of the external test package.
+	external []*goFile
+
+	// names maps the package level names to the declaring file.
+	names map[string]string
+}
+
+// parsePackage parses the go files in dir. A missing folder
+// returns an empty package.
+func parsePackage(fset *token.FileSet, dir string) (*goPackage, error) {
+	result := &goPackage{
+		dir:   dir,
+		names: map[string]string{},
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, err
+	}
+
+	var parsed []*goFile
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		f, err := parseFile(fset, filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, f)
+	}
+
+	// The package name is taken from non-test files when possible.
+	for _, f := range parsed {
+		if !f.isTest() {
+			result.name = f.file.Name.Name
+			break
+		}
+	}
+	for _, f := range parsed {
+		if result.name == "" {
+			result.name = strings.TrimSuffix(f.file.Name.Name, "_test")
+		}
+		if f.file.Name.Name != result.name {
+			result.external = append(result.external, f)
+			continue
+		}
+		result.files = append(result.files, f)
+
+		for _, decl := range f.file.Decls {
+			for _, name := range declNames(decl) {
+				result.names[name] = f.filename
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func parseFile(fset *token.FileSet, filename string) (*goFile, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	return &goFile{
+		filename: filename,
+		src:      src,
+		file:     file,
+	}, nil
+}
+
+// declNames returns the package level names declared by decl.
+// Methods and init funcs don't declare package level names.
+func declNames(decl ast.Decl) []string {
+	var result []string
+	switch decl := decl.(type) {
+	case *ast.GenDecl:
+		for _, spec := range decl.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				result = append(result, spec.Name.Name)
+			case *ast.ValueSpec:
+				for _, name := range spec.Names {
+					if name.Name != "_" {
+						result = append(result, name.Name)
+					}
+				}
+			}
+		}
+	case *ast.FuncDecl:
+		if decl.Recv == nil && decl.Name.Name != "init" {
+			result = append(result, decl.Name.Name)
+		}
+	}
+	return result
+}
+
+// receiverName returns the receiver type name of a method.
+func receiverName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+	expr := decl.Recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// packageRefs returns the identifiers in node referring to package level
+// names. Identifiers declared in the file resolve to the file scope, and
+// identifiers declared in other files of the package are unresolved.
+// Selectors, methods names and struct literal keys are skipped.
+func packageRefs(file *ast.File, node ast.Node, names map[string]string) []*ast.Ident {
+	var (
+		skip   = map[*ast.Ident]bool{}
+		result []*ast.Ident
+	)
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			skip[n.Sel] = true
+		case *ast.FuncDecl:
+			if n.Recv != nil {
+				skip[n.Name] = true
+			}
+		case *ast.CompositeLit:
+			if _, ok := n.Type.(*ast.MapType); ok {
+				return true
+			}
+			for _, elt := range n.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok {
+						skip[key] = true
+					}
+				}
+			}
+		case *ast.Ident:
+			if skip[n] {
+				return true
+			}
+			if _, ok := names[n.Name]; !ok {
+				return true
+			}
+			if n.Obj == nil || file.Scope.Lookup(n.Name) == n.Obj {
+				result = append(result, n)
+			}
+		}
+		return true
+	})
+
+	return result
+}
+
+// selectorRefs returns the selectors qualified with the package name,
+// for which the selected name is in names.
+func selectorRefs(node ast.Node, pkgName string, names map[string]bool) []*ast.SelectorExpr {
+	var result []*ast.SelectorExpr
+	ast.Inspect(node, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil && x.Name == pkgName && names[sel.Sel.Name] {
+			result = append(result, sel)
+		}
+		return true
+	})
+	return result
+}
@@ -0,0 +1,648 @@
+package move
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"go/token"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+
+	"github.com/titpetric/exp/cmd/go-fsck/edges"
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// plan holds the changes of a move before they are written.
+type plan struct {
+	fset *token.FileSet
+
+	moduleRoot string
+	modulePath string
+
+	src, dst         *goPackage
+	srcPath, dstPath string
+	dstName          string
+
+	// moved holds the moved package level names.
+	moved map[string]bool
+	// movedDecls holds the moved declarations, including methods and tests.
+	movedDecls map[ast.Decl]bool
+	// exports maps unexported names used across the packages to exported names.
+	exports map[string]string
+
+	// srcUses holds the moved names used by the remaining source package.
+	srcUses map[string]bool
+	// dstUses holds the remaining names used by the moved declarations.
+	dstUses map[string]bool
+
+	// memberOwners maps field and method names to the declaring types.
+	memberOwners map[string]map[string]bool
+	// memberUses holds the unexported members used across the packages.
+	memberUses map[string]bool
+	// memberExports maps the member names used across the packages to exported names.
+	memberExports map[string]string
+
+	// importers are the files outside the source package using moved names.
+	importers []*importer
+
+	// importNames maps import paths to package names, for imports
+	// where the package name can't be guessed from the path.
+	importNames map[string]string
+
+	// output holds the new file contents, nil contents remove the file.
+	output map[string][]byte
+}
+
+// importer is a file using moved names through the source package import.
+type importer struct {
+	*goFile
+
+	dir  string
+	name string
+	refs []*ast.SelectorExpr
+}
+
+// edit replaces the source between start and end offsets.
+type edit struct {
+	start, end int
+	text       string
+}
+
+func newPlan(cfg *options) (*plan, error) {
+	from, err := filepath.Abs(cfg.from)
+	if err != nil {
+		return nil, err
+	}
+	to, err := filepath.Abs(cfg.to)
+	if err != nil {
+		return nil, err
+	}
+	if from == to {
+		return nil, errors.New("source and destination package are the same")
+	}
+
+	p := &plan{
+		fset:       token.NewFileSet(),
+		moved:      map[string]bool{},
+		movedDecls: map[ast.Decl]bool{},
+		exports:    map[string]string{},
+		srcUses:    map[string]bool{},
+		dstUses:    map[string]bool{},
+		output:     map[string][]byte{},
+
+		importNames: map[string]string{},
+
+		memberUses:    map[string]bool{},
+		memberExports: map[string]string{},
+	}
+
+	if p.moduleRoot, p.modulePath, err = findModule(from); err != nil {
+		return nil, err
+	}
+	if p.srcPath, err = p.importPath(from); err != nil {
+		return nil, err
+	}
+	if p.dstPath, err = p.importPath(to); err != nil {
+		return nil, err
+	}
+
+	if p.src, err = parsePackage(p.fset, from); err != nil {
+		return nil, err
+	}
+	if p.src.name == "" {
+		return nil, fmt.Errorf("no go files in %s", cfg.from)
+	}
+	if p.dst, err = parsePackage(p.fset, to); err != nil {
+		return nil, err
+	}
+
+	p.dstName = p.dst.name
+	if p.dstName == "" {
+		p.dstName = internal.PackageName(p.dstPath)
+	}
+
+	return p, nil
+}
+
+// findModule returns the module root folder and module path for dir.
+func findModule(dir string) (string, string, error) {
+	for current := dir; ; current = filepath.Dir(current) {
+		data, err := os.ReadFile(filepath.Join(current, "go.mod"))
+		if err == nil {
+			modulePath := modfile.ModulePath(data)
+			if modulePath == "" {
+				return "", "", fmt.Errorf("no module path in %s", filepath.Join(current, "go.mod"))
+			}
+			return current, modulePath, nil
+		}
+		if filepath.Dir(current) == current {
+			return "", "", fmt.Errorf("no go.mod found for %s", dir)
+		}
+	}
+}
+
+func (p *plan) importPath(dir string) (string, error) {
+	rel, err := filepath.Rel(p.moduleRoot, dir)
+	if err != nil {
+		return "", err
+	}
+	if rel == "." {
+		return p.modulePath, nil
+	}
+	if strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside of module %s", dir, p.modulePath)
+	}
+	return p.modulePath + "/" + filepath.ToSlash(rel), nil
+}
+
+// selectDecls selects the declarations to move. Grouped declarations
+// move together, methods follow their receiver type, and tests follow
+// the symbol they cover.
+func (p *plan) selectDecls(symbols []string) error {
+	for _, name := range symbols {
+		if _, ok := p.src.names[name]; !ok {
+			return fmt.Errorf("symbol %s not found in %s", name, p.srcPath)
+		}
+		p.moved[name] = true
+	}
+
+	p.eachDecl(func(_ *goFile, decl ast.Decl) {
+		names := declNames(decl)
+		for _, name := range names {
+			if p.moved[name] {
+				p.movedDecls[decl] = true
+			}
+		}
+		if p.movedDecls[decl] {
+			for _, name := range names {
+				p.moved[name] = true
+			}
+		}
+	})
+
+	p.eachDecl(func(f *goFile, decl ast.Decl) {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			return
+		}
+		if p.moved[receiverName(fn)] {
+			p.movedDecls[decl] = true
+		}
+		if f.isTest() && fn.Recv == nil && p.moved[edges.InferTestTarget(fn.Name.Name)] {
+			p.movedDecls[decl] = true
+			p.moved[fn.Name.Name] = true
+		}
+	})
+
+	return nil
+}
+
+func (p *plan) eachDecl(fn func(*goFile, ast.Decl)) {
+	for _, f := range p.src.files {
+		for _, decl := range f.file.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+				continue
+			}
+			fn(f, decl)
+		}
+	}
+}
+
+// analyze collects the names used across the package boundary.
+func (p *plan) analyze() error {
+	helpers := map[string]bool{}
+
+	p.eachDecl(func(f *goFile, decl ast.Decl) {
+		isMoved := p.movedDecls[decl]
+		for _, ident := range packageRefs(f.file, decl, p.src.names) {
+			name := ident.Name
+			switch {
+			case p.moved[name] && !isMoved:
+				p.srcUses[name] = true
+			case !p.moved[name] && isMoved:
+				p.dstUses[name] = true
+				if strings.HasSuffix(p.src.names[name], "_test.go") {
+					helpers[name] = true
+				}
+			}
+		}
+	})
+
+	if len(helpers) > 0 {
+		return fmt.Errorf("moved tests use test helpers of %s, move them too: %s", p.srcPath, strings.Join(internal.SortedKeys(helpers), ", "))
+	}
+	return p.analyzeMembers()
+}
+
+// scanModule finds the files in the module using the moved names.
+func (p *plan) scanModule() error {
+	own := map[string]bool{}
+	for _, f := range p.src.files {
+		own[f.filename] = true
+	}
+	quoted := []byte(strconv.Quote(p.srcPath))
+
+	return filepath.WalkDir(p.moduleRoot, func(filename string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if filename != p.moduleRoot && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(filename, "go.mod")); err == nil && filename != p.moduleRoot {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(filename, ".go") || own[filename] {
+			return nil
+		}
+
+		src, err := os.ReadFile(filename)
+		if err != nil || !bytes.Contains(src, quoted) {
+			return err
+		}
+		f, err := parseFile(p.fset, filename)
+		if err != nil {
+			return err
+		}
+
+		for _, spec := range fileImportSpecs(f.file) {
+			if spec.Path != p.srcPath {
+				continue
+			}
+			name := importName(spec, map[string]string{p.srcPath: p.src.name})
+			if refs := selectorRefs(f.file, name, p.moved); len(refs) > 0 {
+				p.importers = append(p.importers, &importer{
+					goFile: f,
+					dir:    filepath.Dir(filename),
+					name:   name,
+					refs:   refs,
+				})
+			}
+		}
+		return nil
+	})
+}
+
+// checkCycles returns an error if the move results in an import cycle.
+// The import graph of the model is extended with the imports the move
+// adds: the destination imports the packages used by the moved code,
+// and the packages using the moved names import the destination.
+func (p *plan) checkCycles(defs []*model.Definition) error {
+	graph := newImportGraph(defs)
+
+	// The destination may only import the source for the moved names.
+	delete(graph[p.dstPath], p.srcPath)
+	if len(p.dstUses) > 0 {
+		graph.add(p.dstPath, p.srcPath)
+	}
+	for _, imp := range p.importers {
+		if imp.dir == p.dst.dir && imp.usesOther(p) {
+			graph.add(p.dstPath, p.srcPath)
+		}
+	}
+
+	known := maps.Clone(p.importNames)
+	known[p.srcPath] = p.src.name
+	p.eachDecl(func(f *goFile, decl ast.Decl) {
+		if !p.movedDecls[decl] || f.isTest() {
+			return
+		}
+		used := map[string]bool{}
+		ast.Inspect(decl, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil {
+					used[x.Name] = true
+				}
+			}
+			return true
+		})
+		for _, spec := range fileImportSpecs(f.file) {
+			if used[importName(spec, known)] {
+				graph.add(p.dstPath, spec.Path)
+			}
+		}
+	})
+
+	if len(p.srcUses) > 0 {
+		graph.add(p.srcPath, p.dstPath)
+	}
+	for _, imp := range p.importers {
+		if !imp.isTest() {
+			importPath, _ := p.importPath(imp.dir)
+			graph.add(importPath, p.dstPath)
+		}
+	}
+	for _, importPath := range modelImporters(defs, p.srcPath, p.moved) {
+		graph.add(importPath, p.dstPath)
+	}
+
+	cycle := graph.cycle(p.dstPath)
+	if cycle == nil {
+		return nil
+	}
+
+	report := []string{"refusing to move, import cycle: " + strings.Join(cycle, " -> ")}
+	if len(p.dstUses) > 0 {
+		report = append(report, fmt.Sprintf("  %s uses %s: %s", p.dstPath, p.srcPath, strings.Join(internal.SortedKeys(p.dstUses), ", ")))
+	}
+	if len(p.srcUses) > 0 {
+		report = append(report, fmt.Sprintf("  %s uses %s: %s", p.srcPath, p.dstPath, strings.Join(internal.SortedKeys(p.srcUses), ", ")))
+	}
+	return errors.New(strings.Join(report, "\n"))
+}
+
+// usesOther returns true if a destination package file uses source
+// package names that are not moved.
+func (i *importer) usesOther(p *plan) bool {
+	found := false
+	ast.Inspect(i.file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil && x.Name == i.name && !p.moved[sel.Sel.Name] {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// resolveExports exports the unexported names and members used across
+// the packages and checks for name conflicts.
+func (p *plan) resolveExports() error {
+	for _, name := range internal.SortedKeys(p.srcUses) {
+		p.export(name)
+	}
+	for _, name := range internal.SortedKeys(p.dstUses) {
+		p.export(name)
+	}
+
+	var conflicts []string
+	for name, exported := range p.exports {
+		if !ast.IsExported(exported) {
+			return fmt.Errorf("can't export %s", name)
+		}
+		if _, ok := p.src.names[exported]; ok {
+			conflicts = append(conflicts, fmt.Sprintf("exporting %s conflicts with %s in %s", name, exported, p.srcPath))
+		}
+	}
+	for name := range p.moved {
+		if _, ok := p.dst.names[p.exportName(name)]; ok {
+			conflicts = append(conflicts, fmt.Sprintf("%s is already declared in %s", p.exportName(name), p.dstPath))
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return errors.New(strings.Join(conflicts, "\n"))
+	}
+	return p.resolveMemberExports()
+}
+
+func (p *plan) export(name string) {
+	if !ast.IsExported(name) {
+		p.exports[name] = strings.ToUpper(name[:1]) + name[1:]
+	}
+}
+
+func (p *plan) exportName(name string) string {
+	if exported, ok := p.exports[name]; ok {
+		return exported
+	}
+	return name
+}
+
+// rewrite produces the file contents of the move.
+func (p *plan) rewrite() error {
+	known := maps.Clone(p.importNames)
+	known[p.srcPath] = p.src.name
+	known[p.dstPath] = p.dstName
+	srcImport := importSpec{Path: p.srcPath}
+	if p.src.name != internal.PackageName(p.srcPath) {
+		srcImport.Name = p.src.name
+	}
+	dstImport := importSpec{Path: p.dstPath}
+	if p.dstName != internal.PackageName(p.dstPath) {
+		dstImport.Name = p.dstName
+	}
+
+	var (
+		targets       []string
+		targetTexts   = map[string][]string{}
+		targetImports = map[string][]importSpec{}
+		targetHeaders = map[string]string{}
+	)
+
+	// Source package files
+	for _, f := range p.src.files {
+		var edits, cuts []edit
+		var usesDst, usesSrc bool
+
+		for _, decl := range f.file.Decls {
+			isMoved := p.movedDecls[decl]
+			if isMoved {
+				cuts = append(cuts, p.declRange(f, decl))
+			}
+			for _, ident := range packageRefs(f.file, decl, p.src.names) {
+				name, newName := ident.Name, p.exportName(ident.Name)
+
+				var text string
+				switch {
+				case p.moved[name] && !isMoved:
+					text, usesDst = p.dstName+"."+newName, true
+				case !p.moved[name] && isMoved:
+					text, usesSrc = p.src.name+"."+newName, true
+				case newName != name:
+					text = newName
+				default:
+					continue
+				}
+				edits = append(edits, edit{p.offset(ident.Pos()), p.offset(ident.End()), text})
+			}
+			edits = append(edits, p.memberEdits(decl)...)
+		}
+
+		if len(edits) == 0 && len(cuts) == 0 {
+			continue
+		}
+
+		var add []importSpec
+		if usesDst {
+			add = append(add, dstImport)
+		}
+		body, hasDecls, err := fixImports(f.filename, applyEdits(f.src, 0, len(f.src), append(outside(edits, cuts), cuts...)), add, known)
+		if err != nil {
+			return fmt.Errorf("error rewriting %s: %w", f.filename, err)
+		}
+		p.output[f.filename] = body
+		if !hasDecls {
+			p.output[f.filename] = nil
+		}
+
+		if len(cuts) == 0 {
+			continue
+		}
+
+		target := filepath.Join(p.dst.dir, filepath.Base(f.filename))
+		if _, ok := targetTexts[target]; !ok {
+			targets = append(targets, target)
+			targetHeaders[target] = buildConstraint(f.file)
+		}
+		for _, cut := range cuts {
+			targetTexts[target] = append(targetTexts[target], string(applyEdits(f.src, cut.start, cut.end, edits)))
+		}
+		targetImports[target] = append(targetImports[target], fileImportSpecs(f.file)...)
+		if usesSrc {
+			targetImports[target] = append(targetImports[target], srcImport)
+		}
+	}
+
+	// Files importing the moved names from the source package
+	for _, imp := range p.importers {
+		edits := make([]edit, 0, len(imp.refs))
+		for _, sel := range imp.refs {
+			if imp.dir == p.dst.dir {
+				edits = append(edits, edit{p.offset(sel.Pos()), p.offset(sel.End()), sel.Sel.Name})
+				continue
+			}
+			edits = append(edits, edit{p.offset(sel.X.Pos()), p.offset(sel.X.End()), p.dstName})
+		}
+
+		src := imp.src
+		if body, ok := p.output[imp.filename]; ok {
+			src = body
+		}
+
+		var add []importSpec
+		if imp.dir != p.dst.dir {
+			add = append(add, dstImport)
+		}
+		body, _, err := fixImports(imp.filename, applyEdits(src, 0, len(src), edits), add, known)
+		if err != nil {
+			return fmt.Errorf("error rewriting %s: %w", imp.filename, err)
+		}
+		p.output[imp.filename] = body
+	}
+
+	// Destination package files
+	for _, target := range targets {
+		base, ok := p.output[target]
+		if !ok {
+			if existing, err := os.ReadFile(target); err == nil {
+				base = existing
+			} else {
+				base = []byte(targetHeaders[target] + "package " + p.dstName + "\n")
+			}
+		}
+
+		src := string(base) + "\n" + strings.Join(targetTexts[target], "\n\n") + "\n"
+		body, _, err := fixImports(target, []byte(src), targetImports[target], known)
+		if err != nil {
+			return fmt.Errorf("error writing %s: %w", target, err)
+		}
+		p.output[target] = body
+	}
+
+	return nil
+}
+
+func (p *plan) offset(pos token.Pos) int {
+	return p.fset.Position(pos).Offset
+}
+
+// declRange returns the source range of a declaration, including the
+// doc comment and a trailing line comment.
+func (p *plan) declRange(f *goFile, decl ast.Decl) edit {
+	start := decl.Pos()
+	switch decl := decl.(type) {
+	case *ast.GenDecl:
+		if decl.Doc != nil {
+			start = decl.Doc.Pos()
+		}
+	case *ast.FuncDecl:
+		if decl.Doc != nil {
+			start = decl.Doc.Pos()
+		}
+	}
+
+	result := edit{start: p.offset(start), end: p.offset(decl.End())}
+	rest := f.src[result.end:]
+	if nl := bytes.IndexByte(rest, '\n'); nl != -1 {
+		line := strings.TrimSpace(string(rest[:nl]))
+		if line == "" || strings.HasPrefix(line, "//") {
+			result.end += nl + 1
+		}
+	} else {
+		result.end = len(f.src)
+	}
+	return result
+}
+
+// buildConstraint returns the `//go:build` line of a file, if any.
+func buildConstraint(file *ast.File) string {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if constraint.IsGoBuild(c.Text) {
+				return c.Text + "\n\n"
+			}
+		}
+	}
+	return ""
+}
+
+// outside returns the edits not contained in any of the ranges.
+func outside(edits, ranges []edit) []edit {
+	var result []edit
+	for _, e := range edits {
+		contained := false
+		for _, r := range ranges {
+			if e.start >= r.start && e.end <= r.end {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// applyEdits returns the source between start and end with the edits
+// in that range applied.
+func applyEdits(src []byte, start, end int, edits []edit) []byte {
+	sorted := make([]edit, 0, len(edits))
+	for _, e := range edits {
+		if e.start >= start && e.end <= end {
+			sorted = append(sorted, e)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].start < sorted[j].start
+	})
+
+	var buf bytes.Buffer
+	last := start
+	for _, e := range sorted {
+		buf.Write(src[last:e.start])
+		buf.WriteString(e.text)
+		last = e.end
+	}
+	buf.Write(src[last:end])
+	return buf.Bytes()
+}
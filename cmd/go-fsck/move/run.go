@@ -0,0 +1,19 @@
+package move
+
+import (
+	"os"
+
+	"golang.org/x/exp/slices"
+)
+
+// Run is the entrypoint for `go-fsck move`.
+func Run() (err error) {
+	cfg := NewOptions()
+
+	if slices.Contains(os.Args, "help") {
+		cfg.PrintHelp()
+		return nil
+	}
+
+	return move(cfg)
+}
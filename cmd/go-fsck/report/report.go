@@ -1,6 +1,7 @@
 package report
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/titpetric/exp/cmd/go-fsck/internal"
@@ -22,32 +23,23 @@ func getDefinitions(cfg *options) ([]*model.Definition, error) {
 		return nil, err
 	}
 
-	defs = []*model.Definition{}
-	getDef := func(in *model.Definition) *model.Definition {
-		for _, def := range defs {
-			if def.Package.Equal(in.Package) {
-				return def
-			}
-		}
-		return nil
+	results, err := loader.LoadAll(context.Background(), packages, &loader.Options{
+		IncludeTests: true,
+		Verbose:      cfg.verbose,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	for _, pkg := range packages {
-		d, err := loader.Load(pkg, true, cfg.verbose)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, in := range d {
-			def := getDef(in)
-			if def != nil {
-				def.Merge(in)
-				continue
-			}
-			defs = append(defs, in)
-		}
+	list := model.DefinitionList{}
+	for _, d := range results {
+		list = append(list, d...)
 	}
 
+	defs = list.Merge(func(d *model.Definition) string {
+		return d.ImportPath
+	})
+
 	return defs, nil
 }
 
@@ -0,0 +1,144 @@
+package restore
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// importSpec is a parsed import literal, e.g. `alias "path"`.
+type importSpec struct {
+	Name string
+	Path string
+
+	// Alias is set when the import literal has an explicit name.
+	Alias bool
+}
+
+func (i importSpec) String() string {
+	if i.Alias {
+		return i.Name + " " + fmt.Sprintf("%q", i.Path)
+	}
+	return fmt.Sprintf("%q", i.Path)
+}
+
+// isStdlib returns true for standard library imports, which
+// don't have a domain in the first path element.
+func (i importSpec) isStdlib() bool {
+	return internal.IsStdlib(i.Path)
+}
+
+func parseImport(literal string) importSpec {
+	name, importPath, ok := strings.Cut(strings.TrimSpace(literal), " ")
+	if !ok {
+		importPath = name
+		name = ""
+	}
+	result := importSpec{
+		Name:  name,
+		Path:  strings.Trim(importPath, `"`),
+		Alias: ok,
+	}
+	if !result.Alias {
+		result.Name = internal.PackageName(result.Path)
+	}
+	return result
+}
+
+// usedPackages returns the package names referenced by a declaration.
+// The References collected from function bodies are combined with the
+// qualified identifiers found in the declaration source, so package
+// names used in signatures, fields and values are found as well.
+func usedPackages(decl *model.Declaration) map[string]bool {
+	result := map[string]bool{}
+	for name := range decl.References {
+		result[name] = true
+	}
+
+	if decl.Source == "" {
+		return result
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package restore\n\n"+decl.Source, 0)
+	if err != nil {
+		return result
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		sel, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		// Unresolved identifiers are package names or package scope symbols.
+		if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil {
+			result[ident.Name] = true
+		}
+		return true
+	})
+
+	return result
+}
+
+// fileImports returns the import literals needed by the declarations in
+// a restored file. Each declaration resolves the packages it uses against
+// the imports of the file it was declared in, keeping aliases. Unused
+// imports are dropped, blank and dot imports are kept. Imports with an
+// unknown package name are kept, as the name may not match the import
+// path. The result is sorted, with the standard library imports first.
+func fileImports(decls model.DeclarationList) []string {
+	imports := map[string]importSpec{}
+
+	add := func(spec importSpec, known bool) {
+		key := spec.Name
+		if spec.Name == "_" || spec.Name == "." || !known {
+			key = spec.Name + " " + spec.Path
+		}
+		if existing, ok := imports[key]; ok && existing.Path != spec.Path {
+			log.Printf("WARN: import conflict for %s: %s != %s\n", spec.Name, existing.Path, spec.Path)
+			return
+		}
+		imports[key] = spec
+	}
+
+	for _, decl := range decls {
+		used := usedPackages(decl)
+		for _, literal := range decl.Imports {
+			spec := parseImport(literal)
+			known := spec.Alias || spec.isStdlib()
+			if name, ok := decl.ImportNames[spec.Path]; ok && !spec.Alias {
+				spec.Name, known = name, true
+			}
+			if !known || spec.Name == "_" || spec.Name == "." || used[spec.Name] {
+				add(spec, known)
+			}
+		}
+	}
+
+	specs := make([]importSpec, 0, len(imports))
+	for _, spec := range imports {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		if a, b := specs[i].isStdlib(), specs[j].isStdlib(); a != b {
+			return a
+		}
+		return specs[i].Path < specs[j].Path
+	})
+
+	result := make([]string, 0, len(specs)+1)
+	for i, spec := range specs {
+		// separate stdlib imports from the rest
+		if i > 0 && specs[i-1].isStdlib() && !spec.isStdlib() {
+			result = append(result, "")
+		}
+		result = append(result, spec.String())
+	}
+	return result
+}
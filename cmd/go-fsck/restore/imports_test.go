@@ -0,0 +1,103 @@
+package restore
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func TestParseImport(t *testing.T) {
+	assert.Equal(t, importSpec{Name: "strcase", Path: "github.com/stoewer/go-strcase"}, parseImport(`"github.com/stoewer/go-strcase"`))
+	assert.Equal(t, importSpec{Name: "yaml", Path: "gopkg.in/yaml.v3"}, parseImport(`"gopkg.in/yaml.v3"`))
+	assert.Equal(t, importSpec{Name: "chi", Path: "github.com/go-chi/chi/v5"}, parseImport(`"github.com/go-chi/chi/v5"`))
+	assert.Equal(t, importSpec{Name: "flag", Path: "github.com/spf13/pflag", Alias: true}, parseImport(`flag "github.com/spf13/pflag"`))
+	assert.Equal(t, `flag "github.com/spf13/pflag"`, parseImport(`flag "github.com/spf13/pflag"`).String())
+}
+
+func TestFileImports(t *testing.T) {
+	imports := []string{`"fmt"`, `"os"`, `"strings"`, `flag "github.com/spf13/pflag"`, `_ "embed"`}
+
+	decls := model.DeclarationList{
+		{
+			Kind: model.FuncKind, Name: "Run",
+			Imports: imports,
+			Source:  "func Run(args []string) error {\n\tfs := flag.NewFlagSet(\"run\", flag.ContinueOnError)\n\treturn fs.Parse(args)\n}",
+		},
+		{
+			Kind: model.TypeKind, Name: "Writer",
+			Imports: imports,
+			Source:  "type Writer struct {\n\tout *os.File\n}",
+		},
+		{
+			Kind: model.FuncKind, Name: "print",
+			Imports:    []string{`"fmt"`, `"log"`},
+			References: model.StringSet{"log": {"Println"}},
+		},
+	}
+
+	assert.Equal(t, []string{
+		`_ "embed"`,
+		`"log"`,
+		`"os"`,
+		"",
+		`flag "github.com/spf13/pflag"`,
+	}, fileImports(decls))
+}
+
+func TestFileImports_names(t *testing.T) {
+	imports := []string{`"fmt"`, `"gopkg.in/yaml.v3"`, `"example.com/lib/parser-go"`, `"example.com/lib/unused"`, `"example.com/lib/other"`}
+	names := map[string]string{
+		"gopkg.in/yaml.v3":          "yaml",
+		"example.com/lib/parser-go": "grammar",
+		"example.com/lib/unused":    "unused",
+	}
+
+	decls := model.DeclarationList{
+		{
+			Kind: model.FuncKind, Name: "Parse",
+			Imports:     imports,
+			ImportNames: names,
+			Source:      "func Parse(b []byte) (any, error) {\n\tvar v any\n\tif err := yaml.Unmarshal(b, &v); err != nil {\n\t\treturn nil, err\n\t}\n\treturn grammar.Parse(v)\n}",
+		},
+	}
+
+	// fmt and unused are dropped, other has an unknown name and is kept.
+	assert.Equal(t, []string{
+		`"example.com/lib/other"`,
+		`"example.com/lib/parser-go"`,
+		`"gopkg.in/yaml.v3"`,
+	}, fileImports(decls))
+}
+
+func TestSaveLayout(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	imports := []string{`"errors"`, `"fmt"`, `"strings"`}
+	files := map[string]model.DeclarationList{
+		"item.go": {
+			{Kind: model.TypeKind, Name: "Item", File: "model.go", Imports: imports, Source: "type Item struct{ Name string }"},
+			{Kind: model.FuncKind, Name: "Title", Receiver: "*Item", File: "model.go", Imports: imports, Source: "func (i *Item) Title() string { return strings.ToUpper(i.Name) }"},
+		},
+		"errors.go": {
+			{Kind: model.VarKind, Name: "ErrEmpty", File: "errors.go", Imports: imports, Source: "var ErrEmpty = errors.New(\"empty\")"},
+		},
+	}
+
+	require.NoError(t, os.WriteFile("go.mod", []byte("module example.com/restored\n\ngo 1.21\n"), 0644))
+	require.NoError(t, saveLayout(&options{packageName: "restored"}, files, []string{"errors.go", "item.go"}))
+
+	item, err := os.ReadFile("item.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package restored\n\nimport (\n\t\"strings\"\n)\n\ntype Item struct{ Name string }\n\nfunc (i *Item) Title() string { return strings.ToUpper(i.Name) }\n", string(item))
+
+	// the restored package compiles without goimports
+	if _, err := exec.LookPath("go"); err == nil {
+		out, err := exec.Command("go", "vet", ".").CombinedOutput()
+		assert.NoError(t, err, string(out))
+	}
+}
@@ -28,6 +28,9 @@ type options struct {
 	statsFiles  bool
 	verbose     bool
 
+	verify       bool
+	verifyOutput string
+
 	V2 bool
 }
 
@@ -57,6 +60,9 @@ func NewOptions() *options {
 	flag.StringVarP(&cfg.packageName, "package-name", "p", cfg.packageName, "package name for --save")
 	flag.BoolVarP(&cfg.verbose, "verbose", "v", cfg.verbose, "verbose output")
 
+	flag.BoolVar(&cfg.verify, "verify", cfg.verify, "compile each file group in isolation and report couplings")
+	flag.StringVar(&cfg.verifyOutput, "verify-output", cfg.verifyOutput, "write the model with verified SelfContained values to file")
+
 	flag.Parse()
 
 	return cfg
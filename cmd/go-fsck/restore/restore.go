@@ -338,6 +338,19 @@ func restoreV1(cfg *options) error {
 		return strings.Compare(c1, c2) < 0
 	})
 
+	if cfg.verify {
+		results, err := verifyLayout(cfg, files, filenames)
+		if err != nil {
+			return err
+		}
+		if cfg.verifyOutput != "" {
+			if err := writeModel(cfg.verifyOutput, defs); err != nil {
+				return err
+			}
+		}
+		return printVerify(cfg, results)
+	}
+
 	if cfg.save {
 		return saveLayout(cfg, files, filenames)
 	}
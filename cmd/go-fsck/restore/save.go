@@ -2,11 +2,10 @@ package restore
 
 import (
 	"fmt"
+	"go/format"
 	"os"
 	"strings"
 
-	"golang.org/x/exp/maps"
-
 	"github.com/titpetric/exp/cmd/go-fsck/model"
 )
 
@@ -25,42 +24,42 @@ func saveLayout(cfg *options, files map[string]model.DeclarationList, filenames
 			continue
 		}
 
-		decls := files[filename]
-		decls.Sort()
-
-		// Collect sources
-		lines := []string{"package " + cfg.packageName}
-
-		imports := map[string]bool{}
-		for _, decl := range decls {
-			for _, v := range decl.Imports {
-				imports[v] = true
-			}
-		}
-		if cfg.addDotImport != "" {
-			imports[fmt.Sprintf(`%s "%s"`, ".", cfg.addDotImport)] = true
+		body, err := renderFile(cfg, files[filename])
+		if err != nil {
+			return fmt.Errorf("error rendering %s: %w", filename, err)
 		}
-		if len(imports) > 0 {
-			lines = append(lines, "", "import (")
-			lines = append(lines, maps.Keys(imports)...)
-			lines = append(lines, "", ")")
+		if err := os.WriteFile(filename, body, 0644); err != nil {
+			return fmt.Errorf("error saving: %w", err)
 		}
+	}
 
-		for _, decl := range decls {
-			if decl.Source != "" {
-				lines = append(lines, "", decl.Source)
-				continue
-			}
-			return fmt.Errorf("Missing source for object, %#v", decl)
-		}
+	return nil
+}
 
-		if len(lines) > 0 {
-			body := []byte(strings.Join(lines, "\n"))
-			if err := os.WriteFile(filename, body, 0644); err != nil {
-				return fmt.Errorf("error saving: %w", err)
-			}
+// renderFile returns the formatted source for a restored file.
+func renderFile(cfg *options, decls model.DeclarationList) ([]byte, error) {
+	decls.Sort()
+
+	// Collect sources
+	lines := []string{"package " + cfg.packageName}
+
+	imports := fileImports(decls)
+	if cfg.addDotImport != "" {
+		imports = append(imports, fmt.Sprintf(`%s "%s"`, ".", cfg.addDotImport))
+	}
+	if len(imports) > 0 {
+		lines = append(lines, "", "import (")
+		lines = append(lines, imports...)
+		lines = append(lines, ")")
+	}
+
+	for _, decl := range decls {
+		if decl.Source != "" {
+			lines = append(lines, "", decl.Source)
+			continue
 		}
+		return nil, fmt.Errorf("Missing source for object, %#v", decl)
 	}
 
-	return nil
+	return format.Source([]byte(strings.Join(lines, "\n") + "\n"))
 }
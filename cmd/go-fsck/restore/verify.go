@@ -0,0 +1,254 @@
+package restore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fbiville/markdown-table-formatter/pkg/markdown"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// Shared files of the restored layout. They hold the package scope
+// that file groups may be coupled to.
+const (
+	constFile      = "const.go"
+	varsFile       = "vars.go"
+	funcsFile      = "funcs.go"
+	interfacesFile = "interfaces.go"
+)
+
+// verifyLevel is a combination of shared files compiled with a file group.
+type verifyLevel struct {
+	Name  string
+	Files []string
+}
+
+// verifyLevels are ordered from the least to the most coupled.
+var verifyLevels = []verifyLevel{
+	{"strict", []string{constFile}},
+	{"with-interfaces", []string{constFile, interfacesFile}},
+	{"with-vars", []string{constFile, varsFile}},
+	{"with-vars-interfaces", []string{constFile, varsFile, interfacesFile}},
+	{"with-funcs", []string{constFile, funcsFile}},
+	{"with-funcs-interfaces", []string{constFile, funcsFile, interfacesFile}},
+	{"with-funcs-vars", []string{constFile, varsFile, funcsFile}},
+	{"with-funcs-vars-interfaces", []string{constFile, varsFile, funcsFile, interfacesFile}},
+}
+
+// VerifyResult holds the compiler results for a file group.
+type VerifyResult struct {
+	// Group is the file group name, e.g. `item` for item.go and item_test.go.
+	Group string
+	Files []string
+
+	// Level is the least coupled combination that compiles. It's
+	// empty if the group doesn't compile in any combination.
+	Level string `json:",omitempty"`
+
+	// Couplings are the package scope symbols undefined in the strict
+	// combination, preventing extraction of the group.
+	Couplings []string `json:",omitempty"`
+}
+
+// SelfContained returns true if the group compiles on its own.
+func (v *VerifyResult) SelfContained() bool {
+	return v.Level == verifyLevels[0].Name
+}
+
+var undefinedSymbol = regexp.MustCompile(`undefined: ([A-Za-z_][A-Za-z0-9_.]*)`)
+
+// verifyLayout writes the layout into a temporary folder and compiles
+// each file group in isolation, in the combinations of verifyLevels.
+// The SelfContained field of the grouped declarations is set from the
+// results. Interface types are moved into interfaces.go.
+func verifyLayout(cfg *options, files map[string]model.DeclarationList, filenames []string) ([]*VerifyResult, error) {
+	files, filenames = splitInterfaces(files, filenames)
+
+	dir, err := os.MkdirTemp("", "go-fsck-verify-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	written := map[string]bool{}
+	for _, filename := range filenames {
+		if cfg.removeTests && strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+		body, err := renderFile(cfg, files[filename])
+		if err != nil {
+			return nil, fmt.Errorf("error rendering %s: %w", filename, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, filename), body, 0644); err != nil {
+			return nil, err
+		}
+		written[filename] = true
+	}
+
+	var results []*VerifyResult
+	for _, group := range fileGroups(written) {
+		result := &VerifyResult{
+			Group: strings.TrimSuffix(group[0], ".go"),
+			Files: group,
+		}
+
+		for i, level := range verifyLevels {
+			out, err := compileFiles(cfg, dir, append(append([]string{}, group...), existing(written, level.Files)...))
+			if i == 0 && err != nil {
+				result.Couplings = couplings(out)
+			}
+			if err == nil {
+				result.Level = level.Name
+				break
+			}
+		}
+
+		for _, filename := range group {
+			for _, decl := range files[filename] {
+				decl.SelfContained = result.SelfContained()
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// splitInterfaces moves interface types into interfaces.go.
+func splitInterfaces(files map[string]model.DeclarationList, filenames []string) (map[string]model.DeclarationList, []string) {
+	result := make(map[string]model.DeclarationList, len(files))
+	for _, filename := range filenames {
+		for _, decl := range files[filename] {
+			dest := filename
+			if decl.Kind == model.TypeKind && decl.Type == "interface" && !strings.HasSuffix(filename, "_test.go") {
+				dest = interfacesFile
+			}
+			result[dest] = append(result[dest], decl)
+		}
+	}
+
+	names := make([]string, 0, len(result))
+	for filename := range result {
+		names = append(names, filename)
+	}
+	sort.Strings(names)
+	return result, names
+}
+
+// fileGroups groups `name.go` and `name_test.go`, skipping shared files.
+func fileGroups(written map[string]bool) [][]string {
+	shared := map[string]bool{constFile: true, varsFile: true, funcsFile: true, interfacesFile: true}
+
+	var result [][]string
+	for filename := range written {
+		if strings.HasSuffix(filename, "_test.go") || shared[filename] {
+			continue
+		}
+		group := []string{filename}
+		if test := strings.TrimSuffix(filename, ".go") + "_test.go"; written[test] {
+			group = append(group, test)
+		}
+		result = append(result, group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i][0] < result[j][0]
+	})
+	return result
+}
+
+func existing(written map[string]bool, filenames []string) []string {
+	var result []string
+	for _, filename := range filenames {
+		if written[filename] {
+			result = append(result, filename)
+		}
+	}
+	return result
+}
+
+// compileFiles compiles the files with the go toolchain. Imports are
+// resolved from the module in the current working directory. Groups
+// with tests are compiled with `go test -c`.
+func compileFiles(cfg *options, dir string, filenames []string) (string, error) {
+	args := []string{"build", "-gcflags=-e", "-o", os.DevNull}
+	for _, filename := range filenames {
+		if strings.HasSuffix(filename, "_test.go") {
+			args = []string{"test", "-c", "-gcflags=-e", "-o", filepath.Join(dir, "verify.test")}
+			break
+		}
+	}
+	for _, filename := range filenames {
+		args = append(args, filepath.Join(dir, filename))
+	}
+
+	if cfg.verbose {
+		fmt.Fprintln(os.Stderr, "go", strings.Join(args, " "))
+	}
+
+	out, err := exec.Command("go", args...).CombinedOutput()
+	return string(out), err
+}
+
+// couplings returns the undefined symbols reported by the compiler.
+func couplings(out string) []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, match := range undefinedSymbol.FindAllStringSubmatch(out, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			result = append(result, match[1])
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func printVerify(cfg *options, results []*VerifyResult) error {
+	if cfg.statsFiles {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, result := range results {
+			if err := encoder.Encode(result); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	table := make([][]string, 0, len(results))
+	selfContained := 0
+	for _, result := range results {
+		level := result.Level
+		if level == "" {
+			level = "none"
+		}
+		if result.SelfContained() {
+			selfContained++
+		}
+		table = append(table, []string{result.Group, level, strings.Join(result.Couplings, ", ")})
+	}
+
+	t, err := markdown.NewTableFormatterBuilder().WithPrettyPrint().Build("Group", "Level", "Couplings").Format(table)
+	if err != nil {
+		return err
+	}
+	fmt.Println(t)
+	fmt.Printf("Self-contained groups: %d/%d\n", selfContained, len(results))
+	return nil
+}
+
+// writeModel writes the definitions with verified SelfContained values.
+func writeModel(filename string, defs []*model.Definition) error {
+	b, err := json.MarshalIndent(defs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0644)
+}
@@ -0,0 +1,51 @@
+package restore
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func TestVerifyLayout(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("go.mod", []byte("module example.com/restored\n\ngo 1.21\n"), 0644))
+
+	imports := []string{`"strings"`, `"testing"`}
+	item := &model.Declaration{Kind: model.TypeKind, Name: "Item", File: "item.go", Source: "type Item struct{ Name string }"}
+	store := &model.Declaration{Kind: model.TypeKind, Name: "Store", File: "store.go", Source: "type Store struct{ items []Item }"}
+	getter := &model.Declaration{Kind: model.TypeKind, Name: "Getter", Type: "interface", File: "store.go", Source: "type Getter interface{ Get() string }"}
+	cached := &model.Declaration{Kind: model.TypeKind, Name: "Cached", File: "cached.go", Source: "type Cached struct{ Getter }"}
+	global := &model.Declaration{Kind: model.FuncKind, Name: "Normalize", Receiver: "*Store", File: "store.go", Imports: imports, Source: "func (s *Store) Normalize() {\n\tfor i := range s.items {\n\t\ts.items[i].Name = strings.ToLower(s.items[i].Name) + suffix\n\t}\n}"}
+
+	files := map[string]model.DeclarationList{
+		"item.go":      {item},
+		"item_test.go": {{Kind: model.FuncKind, Name: "TestItem", File: "item_test.go", Imports: imports, Source: "func TestItem(t *testing.T) {\n\t_ = Item{}\n}"}},
+		"store.go":     {store, getter, global},
+		"cached.go":    {cached},
+		"vars.go":      {{Kind: model.VarKind, Name: "suffix", File: "vars.go", Source: `var suffix = "!"`}},
+	}
+
+	cfg := &options{packageName: "restored"}
+	results, err := verifyLayout(cfg, files, []string{"cached.go", "item.go", "item_test.go", "store.go", "vars.go"})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, &VerifyResult{Group: "cached", Files: []string{"cached.go"}, Level: "with-interfaces", Couplings: []string{"Getter"}}, results[0])
+	assert.Equal(t, &VerifyResult{Group: "item", Files: []string{"item.go", "item_test.go"}, Level: "strict"}, results[1])
+	assert.Equal(t, "store", results[2].Group)
+	assert.Equal(t, "", results[2].Level)
+	assert.Equal(t, []string{"Item", "suffix"}, results[2].Couplings)
+
+	assert.True(t, item.SelfContained)
+	assert.False(t, store.SelfContained)
+	assert.False(t, cached.SelfContained)
+}
@@ -29,70 +29,53 @@ func RestoreDefinition(definition *Definition, cfg *options) error {
 	var modelTestDeclarations []*Declaration
 	var richModelTestDeclarations []*Declaration
 
-	modelImports := make(StringSet)
-	richModelImports := make(StringSet)
-	modelTestImports := make(StringSet)
-	richModelTestImports := make(StringSet)
-
-	// Collect declarations and imports
+	// Collect declarations
 	for _, decl := range definition.Types {
 		if removeUnexported && !ast.IsExported(decl.Name) {
 			continue
 		}
 
-		imports := definition.Imports[decl.File]
-
 		if strings.HasSuffix(decl.File, "_test.go") {
 			if decl.SelfContained {
 				modelTestDeclarations = append(modelTestDeclarations, decl)
-				mergeImports(&modelTestImports, imports)
 			} else {
 				richModelTestDeclarations = append(richModelTestDeclarations, decl)
-				mergeImports(&richModelTestImports, imports)
 			}
 		} else {
 			if decl.SelfContained {
 				modelDeclarations = append(modelDeclarations, decl)
-				mergeImports(&modelImports, imports)
 			} else {
 				richModelDeclarations = append(richModelDeclarations, decl)
-				mergeImports(&richModelImports, imports)
 			}
 		}
 	}
 
 	// Write self-contained declarations to model.go
-	if err := writeFile(definition.Package, modelDeclarations, modelImports, filepath.Join(basePath, "model.go")); err != nil {
+	if err := writeFile(definition.Package, modelDeclarations, filepath.Join(basePath, "model.go")); err != nil {
 		return err
 	}
 
 	// Write non-self-contained declarations to model_rich.go
-	if err := writeFile(definition.Package, richModelDeclarations, richModelImports, filepath.Join(basePath, "model_rich.go")); err != nil {
+	if err := writeFile(definition.Package, richModelDeclarations, filepath.Join(basePath, "model_rich.go")); err != nil {
 		return err
 	}
 
 	// Write self-contained test declarations to model_test.go
-	if err := writeFile(definition.Package, modelTestDeclarations, modelTestImports, filepath.Join(basePath, "model_test.go")); err != nil {
+	if err := writeFile(definition.Package, modelTestDeclarations, filepath.Join(basePath, "model_test.go")); err != nil {
 		return err
 	}
 
 	// Write non-self-contained test declarations to model_rich_test.go
-	if err := writeFile(definition.Package, richModelTestDeclarations, richModelTestImports, filepath.Join(basePath, "model_rich_test.go")); err != nil {
+	if err := writeFile(definition.Package, richModelTestDeclarations, filepath.Join(basePath, "model_rich_test.go")); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// mergeImports merges a list of imports into the given import set
-func mergeImports(importsSet *StringSet, imports []string) {
-	for _, imp := range imports {
-		importsSet.Add(imp, imp)
-	}
-}
-
-// writeFile writes the declarations and imports to a specified file using the files package
-func writeFile(pkg model.Package, declarations []*Declaration, imports StringSet, filePath string) error {
+// writeFile writes the declarations to a specified file using the files package.
+// The imports are resolved from the declarations.
+func writeFile(pkg model.Package, declarations []*Declaration, filePath string) error {
 	// Collect types
 	var types []string
 	for _, decl := range declarations {
@@ -103,7 +86,7 @@ func writeFile(pkg model.Package, declarations []*Declaration, imports StringSet
 	file := &files.File{
 		Filename: filePath,
 		Package:  pkg.Name(),
-		Imports:  imports.All(),
+		Imports:  fileImports(declarations),
 		Types:    types,
 	}
 
@@ -0,0 +1,135 @@
+package risk
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// lineRange is an inclusive range of lines.
+type lineRange struct {
+	start, end int
+}
+
+func (r lineRange) overlaps(start, end int) bool {
+	return r.start <= end && start <= r.end
+}
+
+// gitChurn returns a churn func counting the commits since the window
+// which touched the line range of a function. `git log -L` follows the
+// range back through history, so lines moving around in the file are
+// still attributed to the function.
+func gitChurn(since string) func(*Score) (int, error) {
+	return func(score *Score) (int, error) {
+		args := []string{"log", "-s", "--format=%H", fmt.Sprintf("-L%d,%d:%s", score.Line, score.EndLine, score.File)}
+		if since != "" {
+			args = append(args, "--since="+since)
+		}
+
+		out, err := git(args...)
+		if err != nil {
+			// Untracked files have no history.
+			if strings.Contains(err.Error(), "no path") {
+				return 0, nil
+			}
+			return 0, err
+		}
+
+		var count int
+		for _, line := range strings.Split(out, "\n") {
+			if strings.TrimSpace(line) != "" {
+				count++
+			}
+		}
+		return count, nil
+	}
+}
+
+// changedLines returns the lines added or changed since ref, by file
+// path relative to the working directory. Lines are in the coordinates
+// of the working tree, so they match the model. A removal is recorded
+// as a change to the line following it.
+func changedLines(ref string) (map[string][]lineRange, error) {
+	out, err := git("diff", "--relative", "--no-color", "--no-ext-diff", "-U0", ref, "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	return parseDiff(out)
+}
+
+func parseDiff(diff string) (map[string][]lineRange, error) {
+	result := map[string][]lineRange{}
+
+	var file string
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			if file == "/dev/null" {
+				file = ""
+			}
+		case strings.HasPrefix(line, "@@ ") && file != "":
+			// @@ -old,count +new,count @@
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("invalid hunk header: %q", line)
+			}
+			start, count, err := parseHunk(strings.TrimPrefix(fields[2], "+"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid hunk header: %q: %w", line, err)
+			}
+			end := start + count - 1
+			if count == 0 {
+				start, end = start+1, start+1
+			}
+			result[file] = append(result[file], lineRange{start, end})
+		}
+	}
+	return result, scanner.Err()
+}
+
+// parseHunk parses `start,count` or `start` of a hunk header.
+func parseHunk(s string) (start, count int, err error) {
+	count = 1
+	startText, countText, ok := strings.Cut(s, ",")
+	if start, err = strconv.Atoi(startText); err != nil {
+		return 0, 0, err
+	}
+	if ok {
+		if count, err = strconv.Atoi(countText); err != nil {
+			return 0, 0, err
+		}
+	}
+	return start, count, nil
+}
+
+// setChanged marks the scores overlapping the changed lines.
+func setChanged(scores []*Score, changed map[string][]lineRange) {
+	for _, score := range scores {
+		for _, r := range changed[score.File] {
+			if r.overlaps(score.Line, score.EndLine) {
+				score.Changed = true
+				break
+			}
+		}
+	}
+}
+
+func git(args ...string) (string, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,53 @@
+package risk
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+)
+
+type options struct {
+	inputFile string
+
+	since     string
+	noChurn   bool
+	base      string
+	threshold float64
+	top       int
+
+	json    bool
+	verbose bool
+
+	fs *internal.FlagSet
+}
+
+// NewOptions parses command-line flags and returns the risk options.
+func NewOptions() *options {
+	cfg := &options{
+		inputFile: "go-fsck.json",
+		since:     "90 days ago",
+		top:       10,
+	}
+
+	cfg.fs = internal.NewFlagSet("risk")
+	cfg.fs.StringVarP(&cfg.inputFile, "input-file", "i", cfg.inputFile, "input file (go-fsck coverage -p coverage.out -o go-fsck.json)")
+	cfg.fs.StringVar(&cfg.since, "since", cfg.since, "churn window, commits since (git log --since)")
+	cfg.fs.BoolVar(&cfg.noChurn, "no-churn", cfg.noChurn, "don't read churn from git history")
+	cfg.fs.StringVar(&cfg.base, "base", cfg.base, "git ref, check only functions added or changed since ref")
+	cfg.fs.Float64Var(&cfg.threshold, "threshold", cfg.threshold, "fail if a checked function has a CRAP score above threshold (0 disables)")
+	cfg.fs.IntVar(&cfg.top, "top", cfg.top, "hotspots to list per package (0 lists all)")
+	cfg.fs.BoolVar(&cfg.json, "json", cfg.json, "print results as json")
+	cfg.fs.BoolVarP(&cfg.verbose, "verbose", "v", cfg.verbose, "verbose output")
+
+	_ = internal.ParseArgs(cfg.fs)
+
+	return cfg
+}
+
+// PrintHelp displays usage information for the risk command.
+func (o *options) PrintHelp() {
+	fmt.Printf("Usage: %s risk <options>:\n\n", path.Base(os.Args[0]))
+	o.fs.PrintDefaults()
+}
@@ -0,0 +1,157 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fbiville/markdown-table-formatter/pkg/markdown"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
+)
+
+func getDefinitions(cfg *options) ([]*model.Definition, error) {
+	// Read the exported go-fsck.json data.
+	defs, err := loader.ReadFile(cfg.inputFile)
+	if err == nil {
+		return defs, nil
+	}
+
+	// list current local packages
+	packages, err := internal.ListPackages(".", "./...")
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := loader.LoadAll(context.Background(), packages, &loader.Options{
+		Verbose: cfg.verbose,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defs = []*model.Definition{}
+	for _, d := range results {
+		defs = append(defs, d...)
+	}
+
+	return defs, nil
+}
+
+// Result holds the risk report.
+type Result struct {
+	Packages   []*Package
+	Violations []*Score `json:",omitempty"`
+}
+
+func risk(cfg *options) error {
+	defs, err := getDefinitions(cfg)
+	if err != nil {
+		return err
+	}
+
+	scores := Scores(defs)
+
+	var churn func(*Score) (int, error)
+	if !cfg.noChurn {
+		if cfg.verbose {
+			fmt.Fprintf(os.Stderr, "Reading churn of %d functions since %q\n", len(scores), cfg.since)
+		}
+		churn = gitChurn(cfg.since)
+	}
+	if err := SetChurn(scores, churn); err != nil {
+		return err
+	}
+
+	if cfg.base != "" {
+		changed, err := changedLines(cfg.base)
+		if err != nil {
+			return err
+		}
+		setChanged(scores, changed)
+	}
+
+	result := &Result{
+		Packages: Hotspots(scores),
+	}
+	if cfg.threshold > 0 {
+		result.Violations = Violations(scores, cfg.threshold, cfg.base != "")
+	}
+
+	if cfg.json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	} else if err := printReport(cfg, result); err != nil {
+		return err
+	}
+
+	if len(result.Violations) > 0 {
+		return fmt.Errorf("found %d functions with a CRAP score above %v", len(result.Violations), cfg.threshold)
+	}
+	return nil
+}
+
+func printReport(cfg *options, result *Result) error {
+	if len(result.Violations) > 0 {
+		table, err := formatScores(result.Violations, true)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("## CRAP score above %v\n\n%s\n\n", cfg.threshold, table)
+	}
+
+	for _, pkg := range result.Packages {
+		functions := pkg.Functions
+		if cfg.top > 0 && len(functions) > cfg.top {
+			functions = functions[:cfg.top]
+		}
+
+		table, err := formatScores(functions, false)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("## %s\n\nRisk: %.2f, functions: %d\n\n%s\n\n", pkg.ImportPath, pkg.Risk, len(pkg.Functions), table)
+	}
+	return nil
+}
+
+func formatScores(scores []*Score, withPackage bool) (string, error) {
+	header := []string{"Function", "Location", "Cyclomatic", "Coverage", "CRAP", "Churn", "Risk"}
+	if withPackage {
+		header = append([]string{"Package"}, header...)
+	}
+
+	rows := [][]string{}
+	for _, s := range scores {
+		name := "`" + s.Function + "`"
+		if s.Changed {
+			name += " (changed)"
+		}
+		row := []string{
+			name,
+			fmt.Sprintf("%s:%d", s.File, s.Line),
+			fmt.Sprint(s.Cyclomatic),
+			fmt.Sprintf("%.1f%%", s.Coverage),
+			fmt.Sprintf("%.2f", s.CRAP),
+			fmt.Sprint(s.Churn),
+			fmt.Sprintf("%.2f", s.Risk),
+		}
+		if withPackage {
+			row = append([]string{s.Package}, row...)
+		}
+		rows = append(rows, row)
+	}
+
+	table, err := markdown.NewTableFormatterBuilder().WithPrettyPrint().Build(header...).Format(rows)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(table), nil
+}
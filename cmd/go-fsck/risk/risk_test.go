@@ -0,0 +1,189 @@
+package risk
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func TestCRAP(t *testing.T) {
+	assert.Equal(t, 2.0, CRAP(1, 0))
+	assert.Equal(t, 110.0, CRAP(10, 0))
+	assert.Equal(t, 10.0, CRAP(10, 100))
+	assert.Equal(t, 22.5, CRAP(10, 50))
+}
+
+func testDefinitions() []*model.Definition {
+	funcs := model.DeclarationList{
+		{Kind: model.FuncKind, Name: "Get", Receiver: "*Store", File: "store.go", Line: 10, Source: "func (s *Store) Get() {\n}", Complexity: &model.Complexity{Cyclomatic: 10, Coverage: 50}},
+		{Kind: model.FuncKind, Name: "New", File: "store.go", Line: 20, Complexity: &model.Complexity{Cyclomatic: 2, Lines: 5}},
+		{Kind: model.FuncKind, Name: "TestGet", File: "store_test.go", Line: 5, Complexity: &model.Complexity{Cyclomatic: 1}},
+		{Kind: model.FuncKind, Name: "helper", File: "store.go", Line: 30},
+	}
+	return []*model.Definition{
+		{
+			Package: model.Package{ImportPath: "example.com/app/store", Path: "./store"},
+			Funcs:   funcs,
+		},
+		{
+			Package: model.Package{ImportPath: "example.com/app/store", Path: "./store", TestPackage: true},
+			Funcs:   funcs,
+		},
+		{
+			Package: model.Package{ImportPath: "example.com/app/api", Path: "./api"},
+			Funcs: model.DeclarationList{
+				{Kind: model.FuncKind, Name: "Handle", File: "api.go", Line: 3, Complexity: &model.Complexity{Cyclomatic: 4, Coverage: 100}},
+			},
+		},
+	}
+}
+
+func TestScores(t *testing.T) {
+	scores := Scores(testDefinitions())
+	require.Len(t, scores, 3)
+
+	get := scores[0]
+	assert.Equal(t, "Store.Get", get.Function)
+	assert.Equal(t, "store/store.go", get.File)
+	assert.Equal(t, 10, get.Line)
+	assert.Equal(t, 11, get.EndLine)
+	assert.Equal(t, 22.5, get.CRAP)
+	assert.Equal(t, 24, scores[1].EndLine)
+
+	churn := map[string]int{"Store.Get": 3, "Handle": 1}
+	require.NoError(t, SetChurn(scores, func(s *Score) (int, error) {
+		return churn[s.Function], nil
+	}))
+	assert.Equal(t, 90.0, get.Risk)
+
+	hotspots := Hotspots(scores)
+	require.Len(t, hotspots, 2)
+	assert.Equal(t, "example.com/app/store", hotspots[0].ImportPath)
+	assert.Equal(t, 96.0, hotspots[0].Risk)
+	assert.Equal(t, []*Score{get, scores[1]}, hotspots[0].Functions)
+	assert.Equal(t, 8.0, hotspots[1].Risk)
+}
+
+func TestViolations(t *testing.T) {
+	scores := Scores(testDefinitions())
+	require.NoError(t, SetChurn(scores, nil))
+
+	assert.Len(t, Violations(scores, 5, false), 2)
+	assert.Len(t, Violations(scores, 10, false), 1)
+	assert.Len(t, Violations(scores, 10, true), 0)
+
+	changed, err := parseDiff(`diff --git a/store/store.go b/store/store.go
+--- a/store/store.go
++++ b/store/store.go
+@@ -12,0 +11,2 @@ func (s *Store) Get() {
++	return
++}
+@@ -40 +40,0 @@
+-// removed
+diff --git a/api/api.go b/api/api.go
+deleted file mode 100644
+--- a/api/api.go
++++ /dev/null
+@@ -1,3 +0,0 @@
+`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]lineRange{"store/store.go": {{11, 12}, {41, 41}}}, changed)
+
+	setChanged(scores, changed)
+	assert.True(t, scores[0].Changed)
+	assert.False(t, scores[1].Changed)
+
+	violations := Violations(scores, 10, true)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "Store.Get", violations[0].Function)
+}
+
+func TestGitChurn(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	t.Chdir(t.TempDir())
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(contents string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile("main.go", []byte(contents), 0o644))
+	}
+
+	run("init", "-q")
+	write("package main\n\nfunc a() {\n}\n\nfunc b() {\n}\n")
+	run("add", "main.go")
+	run("commit", "-q", "-m", "add a, b")
+	write("package main\n\nfunc a() {\n\tprintln()\n}\n\nfunc b() {\n}\n")
+	run("commit", "-q", "-am", "change a")
+
+	churn := gitChurn("")
+	n, err := churn(&Score{File: "main.go", Line: 3, EndLine: 5})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	n, err = churn(&Score{File: "main.go", Line: 7, EndLine: 8})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	n, err = churn(&Score{File: "untracked.go", Line: 1, EndLine: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestGitChurn_docComment(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	t.Chdir(t.TempDir())
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(contents string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile("main.go", []byte(contents), 0o644))
+	}
+
+	run("init", "-q")
+	write("package main\n\n// a is documented.\n// It spans two doc lines.\nfunc a() {\n}\n\nfunc b() {\n}\n")
+	run("add", "main.go")
+	run("commit", "-q", "-m", "add a, b")
+	write("package main\n\n// a is documented.\n// It spans two doc lines.\nfunc a() {\n}\n\nfunc b(n int) {\n}\n")
+	run("commit", "-q", "-am", "change b")
+
+	scores := Scores([]*model.Definition{
+		{
+			Package: model.Package{ImportPath: "example.com/app", Path: "."},
+			Funcs: model.DeclarationList{
+				{
+					Kind:       model.FuncKind,
+					Name:       "a",
+					File:       "main.go",
+					Line:       5,
+					Doc:        "// a is documented.\n// It spans two doc lines.",
+					Source:     "// a is documented.\n// It spans two doc lines.\nfunc a() {\n}",
+					Complexity: &model.Complexity{Cyclomatic: 1},
+				},
+			},
+		},
+	})
+	require.Len(t, scores, 1)
+	assert.Equal(t, 6, scores[0].EndLine)
+
+	require.NoError(t, SetChurn(scores, gitChurn("")))
+	assert.Equal(t, 1, scores[0].Churn)
+}
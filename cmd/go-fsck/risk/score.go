@@ -0,0 +1,172 @@
+// Package risk scores functions by complexity, coverage and churn.
+package risk
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// Score holds the risk scores of a function.
+type Score struct {
+	Package  string
+	Function string
+	File     string
+	Line     int
+	EndLine  int
+
+	Cognitive  int
+	Cyclomatic int
+	Coverage   float64
+
+	// CRAP is the change risk anti-patterns score,
+	// `comp^2 * (1 - cov)^3 + comp` for cyclomatic complexity.
+	CRAP float64
+	// Churn is the number of commits touching the function lines.
+	Churn int
+	// Risk is the CRAP score multiplied by churn, `CRAP * (1 + Churn)`.
+	Risk float64
+
+	// Changed is set for functions added or changed since --base.
+	Changed bool `json:",omitempty"`
+}
+
+// Name returns the function name with the receiver type.
+func (s *Score) Name() string {
+	return s.Package + "." + s.Function
+}
+
+// Package holds the ranked hotspots of a package.
+type Package struct {
+	ImportPath string
+	Risk       float64
+	Functions  []*Score
+}
+
+// CRAP returns the change risk anti-patterns score for the cyclomatic
+// complexity and coverage percentage of a function.
+func CRAP(cyclomatic int, coverage float64) float64 {
+	comp := float64(cyclomatic)
+	uncovered := 1 - coverage/100
+	return round(comp*comp*math.Pow(uncovered, 3) + comp)
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// Scores returns the scores of functions in the definitions. Functions
+// in test files and functions without complexity are skipped. A model
+// may hold the same function in the package and in the package compiled
+// with tests, each function is scored once.
+func Scores(defs []*model.Definition) []*Score {
+	var (
+		result []*Score
+		seen   = map[string]bool{}
+	)
+	for _, def := range defs {
+		for _, fn := range def.Funcs {
+			if fn.IsTestScope() || fn.Complexity == nil {
+				continue
+			}
+
+			file := filepath.ToSlash(filepath.Join(def.Package.Path, fn.File))
+			key := def.Package.ImportPath + "/" + file + ":" + fmt.Sprint(fn.Line)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			result = append(result, &Score{
+				Package:    def.Package.ImportPath,
+				Function:   internal.FuncName(fn.Receiver, fn.Name),
+				File:       file,
+				Line:       fn.Line,
+				EndLine:    fn.LastLine(),
+				Cognitive:  fn.Complexity.Cognitive,
+				Cyclomatic: fn.Complexity.Cyclomatic,
+				Coverage:   fn.Complexity.Coverage,
+				CRAP:       CRAP(fn.Complexity.Cyclomatic, fn.Complexity.Coverage),
+			})
+		}
+	}
+	return result
+}
+
+// SetChurn sets the churn of each score and computes the risk.
+func SetChurn(scores []*Score, churn func(*Score) (int, error)) error {
+	for _, score := range scores {
+		if churn != nil {
+			n, err := churn(score)
+			if err != nil {
+				return err
+			}
+			score.Churn = n
+		}
+		score.Risk = round(score.CRAP * float64(1+score.Churn))
+	}
+	return nil
+}
+
+// Hotspots groups the scores by package and ranks the functions by
+// risk. Packages are ranked by the sum of their function risk.
+func Hotspots(scores []*Score) []*Package {
+	index := map[string]*Package{}
+
+	var result []*Package
+	for _, score := range scores {
+		pkg, ok := index[score.Package]
+		if !ok {
+			pkg = &Package{ImportPath: score.Package}
+			index[score.Package] = pkg
+			result = append(result, pkg)
+		}
+		pkg.Functions = append(pkg.Functions, score)
+		pkg.Risk += score.Risk
+	}
+
+	for _, pkg := range result {
+		pkg.Risk = round(pkg.Risk)
+		sortScores(pkg.Functions)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Risk != result[j].Risk {
+			return result[i].Risk > result[j].Risk
+		}
+		return result[i].ImportPath < result[j].ImportPath
+	})
+	return result
+}
+
+func sortScores(scores []*Score) {
+	sort.SliceStable(scores, func(i, j int) bool {
+		a, b := scores[i], scores[j]
+		if a.Risk != b.Risk {
+			return a.Risk > b.Risk
+		}
+		if a.CRAP != b.CRAP {
+			return a.CRAP > b.CRAP
+		}
+		return a.Name() < b.Name()
+	})
+}
+
+// Violations returns the scores with a CRAP score above threshold. With
+// onlyChanged, only functions added or changed since the base are checked.
+func Violations(scores []*Score, threshold float64, onlyChanged bool) []*Score {
+	var result []*Score
+	for _, score := range scores {
+		if onlyChanged && !score.Changed {
+			continue
+		}
+		if score.CRAP > threshold {
+			result = append(result, score)
+		}
+	}
+	sortScores(result)
+	return result
+}
@@ -1,6 +1,7 @@
 package search
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -25,14 +26,16 @@ func getDefinitions(cfg *options) ([]*model.Definition, error) {
 		return nil, err
 	}
 
-	defs = []*model.Definition{}
-
-	for _, pkg := range packages {
-		d, err := loader.Load(pkg, true, cfg.verbose)
-		if err != nil {
-			return nil, err
-		}
+	results, err := loader.LoadAll(context.Background(), packages, &loader.Options{
+		IncludeTests: true,
+		Verbose:      cfg.verbose,
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	defs = []*model.Definition{}
+	for _, d := range results {
 		defs = append(defs, d...)
 	}
 
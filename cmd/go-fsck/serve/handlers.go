@@ -0,0 +1,155 @@
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/titpetric/exp/cmd/go-fsck/docs"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// routes returns the http handler for the web UI.
+func (s *server) routes() http.Handler {
+	static, _ := fs.Sub(htmlFS, "html/static")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /pkg/{path...}", s.handlePackage)
+	mux.HandleFunc("GET /symbol", s.handleSymbol)
+	mux.HandleFunc("GET /graph", s.handleGraph)
+	mux.HandleFunc("GET /stats", s.handleStats)
+	mux.HandleFunc("GET /docs/{file...}", s.handleDocs)
+	mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServerFS(static)))
+
+	mux.HandleFunc("GET /api/model", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, s.defs)
+	})
+	mux.HandleFunc("GET /api/graph", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, s.graph())
+	})
+	mux.HandleFunc("GET /api/stats", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, s.stats())
+	})
+	return mux
+}
+
+// logRequests logs the method, URL and duration of each request.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		log.Printf("%s %s %s\n", r.Method, r.URL.RequestURI(), time.Since(start))
+	})
+}
+
+func (s *server) render(w http.ResponseWriter, name string, data any) {
+	var buf bytes.Buffer
+	if err := s.tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(data)
+}
+
+func (s *server) handleIndex(w http.ResponseWriter, _ *http.Request) {
+	s.render(w, "index.tmpl", s)
+}
+
+func (s *server) handlePackage(w http.ResponseWriter, r *http.Request) {
+	pkg, ok := s.packages[r.PathValue("path")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	s.render(w, "package.tmpl", pkg)
+}
+
+// symbolPage holds the data of a symbol page.
+type symbolPage struct {
+	*symbol
+
+	DocsURL    string
+	References []*reference
+	Outgoing   []*relation
+	Incoming   []*relation
+}
+
+func (s *server) handleSymbol(w http.ResponseWriter, r *http.Request) {
+	sym, ok := s.symbols[r.URL.Query().Get("id")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.render(w, "symbol.tmpl", &symbolPage{
+		symbol:     sym,
+		DocsURL:    s.docsURL(sym),
+		References: s.references(sym),
+		Outgoing:   s.relations(s.outgoing[sym.ID], false),
+		Incoming:   s.relations(s.incoming[sym.ID], true),
+	})
+}
+
+// docsURL links exported symbols to the docs site. Types have a page,
+// funcs and methods link to an anchor on the package or type page.
+func (s *server) docsURL(sym *symbol) string {
+	if sym.Package.DocsURL == "" || !sym.Decl.IsExported() {
+		return ""
+	}
+
+	page := docs.HTMLPage(sym.Package.ImportPath, "", s.cfg.strip)
+	switch {
+	case sym.Kind == model.TypeKind:
+		page = docs.HTMLPage(sym.Package.ImportPath, sym.Decl.Name, s.cfg.strip)
+	case sym.Decl.Receiver != "":
+		page = docs.HTMLPage(sym.Package.ImportPath, sym.Decl.ReceiverTypeRef(), s.cfg.strip) + "#" + sym.Decl.Name
+	case sym.Kind == model.FuncKind:
+		page += "#" + sym.Decl.Name
+	}
+
+	if _, ok := s.docs[strings.SplitN(page, "#", 2)[0]]; !ok {
+		return ""
+	}
+	return "/docs/" + page
+}
+
+func (s *server) handleGraph(w http.ResponseWriter, _ *http.Request) {
+	s.render(w, "graph.tmpl", s)
+}
+
+func (s *server) handleStats(w http.ResponseWriter, _ *http.Request) {
+	s.render(w, "stats.tmpl", s.stats())
+}
+
+func (s *server) handleDocs(w http.ResponseWriter, r *http.Request) {
+	filename := r.PathValue("file")
+	if filename == "" {
+		filename = "index.html"
+	}
+
+	data, ok := s.docs[filename]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if contentType := mime.TypeByExtension(path.Ext(filename)); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	_, _ = w.Write(data)
+}
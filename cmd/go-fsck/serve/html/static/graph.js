@@ -0,0 +1,150 @@
+(function () {
+  var svg = document.getElementById("graph");
+  if (!svg) {
+    return;
+  }
+  var ns = "http://www.w3.org/2000/svg";
+
+  fetch(svg.dataset.src)
+    .then(function (res) {
+      return res.json();
+    })
+    .then(render);
+
+  function el(name, attrs, parent) {
+    var node = document.createElementNS(ns, name);
+    Object.keys(attrs).forEach(function (key) {
+      node.setAttribute(key, attrs[key]);
+    });
+    parent.appendChild(node);
+    return node;
+  }
+
+  function render(data) {
+    var width = svg.clientWidth, height = svg.clientHeight;
+    var byID = {};
+
+    // Start on a circle, so the layout is stable between page loads.
+    data.nodes.forEach(function (node, i) {
+      var angle = (2 * Math.PI * i) / data.nodes.length;
+      node.x = width / 2 + (Math.cos(angle) * Math.min(width, height)) / 3;
+      node.y = height / 2 + (Math.sin(angle) * Math.min(width, height)) / 3;
+      node.vx = node.vy = 0;
+      byID[node.id] = node;
+    });
+    var links = data.links.filter(function (link) {
+      link.source = byID[link.source];
+      link.target = byID[link.target];
+      return link.source && link.target;
+    });
+
+    var lines = links.map(function (link) {
+      return el("line", {}, svg);
+    });
+    var groups = data.nodes.map(function (node) {
+      var g = el("g", {}, svg);
+      var radius = 4 + Math.sqrt(node.symbols);
+      var circle = el("circle", { r: radius, class: node.test ? "test" : "" }, g);
+      el("text", { x: radius + 2, y: 4 }, g).textContent = node.name;
+      el("title", {}, circle).textContent = node.id;
+
+      circle.addEventListener("mouseenter", function () {
+        highlight(node);
+      });
+      circle.addEventListener("mouseleave", function () {
+        highlight(null);
+      });
+      circle.addEventListener("mousedown", function (e) {
+        drag(node, e);
+      });
+      return g;
+    });
+
+    function highlight(node) {
+      var related = {};
+      links.forEach(function (link, i) {
+        var active = node && (link.source === node || link.target === node);
+        lines[i].setAttribute("class", active ? "active" : "");
+        if (active) {
+          related[link.source.id] = related[link.target.id] = true;
+        }
+      });
+      data.nodes.forEach(function (other, i) {
+        groups[i].setAttribute("class", node && !related[other.id] && other !== node ? "dim" : "");
+      });
+    }
+
+    function drag(node, start) {
+      var moved = false;
+      function move(e) {
+        var rect = svg.getBoundingClientRect();
+        node.x = e.clientX - rect.left;
+        node.y = e.clientY - rect.top;
+        node.fixed = moved = true;
+        draw();
+      }
+      function up() {
+        window.removeEventListener("mousemove", move);
+        window.removeEventListener("mouseup", up);
+        if (!moved) {
+          window.location = node.url;
+        }
+      }
+      start.preventDefault();
+      window.addEventListener("mousemove", move);
+      window.addEventListener("mouseup", up);
+    }
+
+    function step() {
+      data.nodes.forEach(function (a) {
+        data.nodes.forEach(function (b) {
+          if (a === b) {
+            return;
+          }
+          var dx = a.x - b.x, dy = a.y - b.y;
+          var dist2 = Math.max(dx * dx + dy * dy, 100);
+          a.vx += (dx / dist2) * 200;
+          a.vy += (dy / dist2) * 200;
+        });
+        a.vx += (width / 2 - a.x) * 0.002;
+        a.vy += (height / 2 - a.y) * 0.002;
+      });
+      links.forEach(function (link) {
+        var dx = link.target.x - link.source.x, dy = link.target.y - link.source.y;
+        link.source.vx += dx * 0.005;
+        link.source.vy += dy * 0.005;
+        link.target.vx -= dx * 0.005;
+        link.target.vy -= dy * 0.005;
+      });
+      data.nodes.forEach(function (node) {
+        if (!node.fixed) {
+          node.x = Math.min(width - 10, Math.max(10, node.x + node.vx));
+          node.y = Math.min(height - 10, Math.max(10, node.y + node.vy));
+        }
+        node.vx *= 0.6;
+        node.vy *= 0.6;
+      });
+    }
+
+    function draw() {
+      links.forEach(function (link, i) {
+        lines[i].setAttribute("x1", link.source.x);
+        lines[i].setAttribute("y1", link.source.y);
+        lines[i].setAttribute("x2", link.target.x);
+        lines[i].setAttribute("y2", link.target.y);
+      });
+      data.nodes.forEach(function (node, i) {
+        groups[i].setAttribute("transform", "translate(" + node.x + "," + node.y + ")");
+      });
+    }
+
+    var ticks = 0;
+    (function tick() {
+      step();
+      draw();
+      if (++ticks < 300) {
+        window.requestAnimationFrame(tick);
+      }
+    })();
+  }
+})();
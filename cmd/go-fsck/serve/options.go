@@ -0,0 +1,46 @@
+package serve
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/titpetric/exp/cmd/go-fsck/internal"
+)
+
+type options struct {
+	inputFile string
+	addr      string
+
+	strip     string
+	sourceURL string
+
+	verbose bool
+
+	fs *internal.FlagSet
+}
+
+// NewOptions parses command-line flags and returns the serve options.
+func NewOptions() *options {
+	cfg := &options{
+		inputFile: "go-fsck.json",
+		addr:      "127.0.0.1:8080",
+	}
+
+	cfg.fs = internal.NewFlagSet("serve")
+	cfg.fs.StringVarP(&cfg.inputFile, "input-file", "i", cfg.inputFile, "input file (go-fsck extract ./...)")
+	cfg.fs.StringVar(&cfg.addr, "addr", cfg.addr, "listen address")
+	cfg.fs.StringVar(&cfg.strip, "strip", cfg.strip, "prefix to strip from import path for docs filenames")
+	cfg.fs.StringVar(&cfg.sourceURL, "source-url", cfg.sourceURL, "source link template with {path} and {line}")
+	cfg.fs.BoolVarP(&cfg.verbose, "verbose", "v", cfg.verbose, "log requests")
+
+	_ = internal.ParseArgs(cfg.fs)
+
+	return cfg
+}
+
+// PrintHelp displays usage information for the serve command.
+func (o *options) PrintHelp() {
+	fmt.Printf("Usage: %s serve <options>:\n\n", path.Base(os.Args[0]))
+	o.fs.PrintDefaults()
+}
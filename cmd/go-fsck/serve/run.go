@@ -0,0 +1,43 @@
+package serve
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
+)
+
+// Run is the entrypoint for `go-fsck serve`.
+func Run() (err error) {
+	cfg := NewOptions()
+
+	if slices.Contains(os.Args, "help") {
+		cfg.PrintHelp()
+		return nil
+	}
+
+	return serve(cfg)
+}
+
+func serve(cfg *options) error {
+	defs, err := loader.ReadFile(cfg.inputFile)
+	if err != nil {
+		return err
+	}
+
+	srv, err := newServer(cfg, defs)
+	if err != nil {
+		return err
+	}
+
+	handler := srv.routes()
+	if cfg.verbose {
+		handler = logRequests(handler)
+	}
+
+	log.Printf("Serving %s on http://%s\n", cfg.inputFile, cfg.addr)
+	return http.ListenAndServe(cfg.addr, handler)
+}
@@ -0,0 +1,417 @@
+package serve
+
+import (
+	"embed"
+	"fmt"
+	"go/doc"
+	"html/template"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/titpetric/exp/cmd/go-fsck/docs"
+	"github.com/titpetric/exp/cmd/go-fsck/edges"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+	"github.com/titpetric/exp/cmd/go-fsck/stats/modules"
+)
+
+//go:embed html
+var htmlFS embed.FS
+
+// server holds the model and the indexes the pages are rendered from.
+type server struct {
+	cfg  *options
+	defs []*model.Definition
+	tmpl *template.Template
+
+	// Packages are sorted by import path.
+	Packages []*pkgInfo
+	Tree     []*treeNode
+
+	packages map[string]*pkgInfo
+	symbols  map[string]*symbol
+
+	// outgoing and incoming relationships by symbol ID.
+	outgoing map[string][]*edges.Relationship
+	incoming map[string][]*edges.Relationship
+
+	// docs holds the pages of the static docs site.
+	docs map[string][]byte
+}
+
+// pkgInfo is a package in the model.
+type pkgInfo struct {
+	Name        string
+	ImportPath  string
+	Synopsis    string
+	Doc         string
+	TestPackage bool
+	DocsURL     string
+
+	// Imports and ImportedBy list the model packages.
+	Imports    []*pkgInfo
+	ImportedBy []*pkgInfo
+
+	Symbols []*symbol
+
+	def *model.Definition
+}
+
+// URL returns the package page link.
+func (p *pkgInfo) URL() string {
+	return "/pkg/" + p.ImportPath
+}
+
+// symbol is a declaration in the model, identified by the edges symbol ID.
+type symbol struct {
+	ID      string
+	Name    string
+	Kind    model.DeclarationKind
+	Package *pkgInfo
+	Decl    *model.Declaration
+}
+
+// URL returns the symbol page link.
+func (s *symbol) URL() string {
+	return "/symbol?id=" + url.QueryEscape(s.ID)
+}
+
+// Complexity returns the complexity of funcs, or an empty value.
+func (s *symbol) Complexity() model.Complexity {
+	if s.Decl.Complexity == nil {
+		return model.Complexity{}
+	}
+	return *s.Decl.Complexity
+}
+
+// treeNode is a node in the package tree. Path segments without a
+// package and a single child are collapsed into the child.
+type treeNode struct {
+	Name     string
+	Package  *pkgInfo
+	Children []*treeNode
+}
+
+func newServer(cfg *options, defs []*model.Definition) (*server, error) {
+	tmpl, err := template.New("").Funcs(template.FuncMap{
+		"percent": func(v float64) string {
+			return fmt.Sprintf("%.1f%%", v)
+		},
+	}).ParseFS(htmlFS, "html/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &server{
+		cfg:      cfg,
+		defs:     defs,
+		tmpl:     tmpl,
+		packages: map[string]*pkgInfo{},
+		symbols:  map[string]*symbol{},
+		outgoing: map[string][]*edges.Relationship{},
+		incoming: map[string][]*edges.Relationship{},
+		docs:     map[string][]byte{},
+	}
+
+	// The docs site is rendered first, as it merges the definitions
+	// of a package split over several build contexts.
+	err = docs.RenderHTML(defs, cfg.strip, cfg.sourceURL, func(filename string, data []byte) error {
+		s.docs[filename] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.addPackages()
+	s.addImports()
+	s.Tree = newTree(s.Packages)
+
+	_, relationships, err := edges.ExtractAll(defs)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range relationships {
+		s.outgoing[rel.From.SymbolID()] = append(s.outgoing[rel.From.SymbolID()], rel)
+		s.incoming[rel.To.SymbolID()] = append(s.incoming[rel.To.SymbolID()], rel)
+	}
+
+	return s, nil
+}
+
+func definitionImportPath(def *model.Definition) string {
+	if def.ImportPath != "" {
+		return def.ImportPath
+	}
+	return def.Package.Name()
+}
+
+func (s *server) addPackages() {
+	for _, def := range s.defs {
+		importPath := definitionImportPath(def)
+
+		pkg, ok := s.packages[importPath]
+		if !ok {
+			pkg = &pkgInfo{
+				Name:        def.Package.Package,
+				ImportPath:  importPath,
+				Doc:         def.Doc,
+				TestPackage: def.TestPackage,
+				def:         def,
+			}
+			if _, ok := s.docs[docs.HTMLPage(importPath, "", s.cfg.strip)]; ok && !def.TestPackage {
+				pkg.DocsURL = "/docs/" + docs.HTMLPage(importPath, "", s.cfg.strip)
+			}
+			s.packages[importPath] = pkg
+			s.Packages = append(s.Packages, pkg)
+		}
+		if pkg.Doc == "" {
+			pkg.Doc = def.Doc
+		}
+
+		add := func(decls model.DeclarationList) {
+			for _, decl := range decls {
+				for _, name := range decl.GetNames() {
+					if name == "" {
+						continue
+					}
+					edge := &edges.Edge{
+						ImportPath: importPath,
+						SymbolName: name,
+						Receiver:   decl.Receiver,
+					}
+					sym := &symbol{
+						ID:      edge.SymbolID(),
+						Name:    edge.FullName(),
+						Kind:    decl.Kind,
+						Package: pkg,
+						Decl:    decl,
+					}
+					if _, ok := s.symbols[sym.ID]; ok {
+						continue
+					}
+					s.symbols[sym.ID] = sym
+					pkg.Symbols = append(pkg.Symbols, sym)
+				}
+			}
+		}
+		add(def.Types)
+		add(def.Consts)
+		add(def.Vars)
+		add(def.Funcs)
+	}
+
+	kinds := map[model.DeclarationKind]int{
+		model.TypeKind:  0,
+		model.ConstKind: 1,
+		model.VarKind:   2,
+		model.FuncKind:  3,
+	}
+	for _, pkg := range s.Packages {
+		pkg.Synopsis = synopsis(pkg.Doc)
+		sort.SliceStable(pkg.Symbols, func(i, j int) bool {
+			a, b := pkg.Symbols[i], pkg.Symbols[j]
+			if a.Kind != b.Kind {
+				return kinds[a.Kind] < kinds[b.Kind]
+			}
+			return a.Name < b.Name
+		})
+	}
+	sort.Slice(s.Packages, func(i, j int) bool {
+		return s.Packages[i].ImportPath < s.Packages[j].ImportPath
+	})
+}
+
+// addImports links the packages importing each other in the model.
+func (s *server) addImports() {
+	for _, pkg := range s.Packages {
+		seen := map[string]bool{}
+		for _, def := range s.defs {
+			if definitionImportPath(def) != pkg.ImportPath {
+				continue
+			}
+			importMap, _ := def.Imports.Map(def.Imports.All())
+			for _, importPath := range importMap {
+				imported, ok := s.packages[importPath]
+				if !ok || imported == pkg || seen[importPath] {
+					continue
+				}
+				seen[importPath] = true
+				pkg.Imports = append(pkg.Imports, imported)
+				imported.ImportedBy = append(imported.ImportedBy, pkg)
+			}
+		}
+	}
+	for _, pkg := range s.Packages {
+		sortPackages(pkg.Imports)
+		sortPackages(pkg.ImportedBy)
+	}
+}
+
+func sortPackages(list []*pkgInfo) {
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ImportPath < list[j].ImportPath
+	})
+}
+
+func newTree(packages []*pkgInfo) []*treeNode {
+	root := &treeNode{}
+	for _, pkg := range packages {
+		node := root
+		for _, segment := range strings.Split(pkg.ImportPath, "/") {
+			var next *treeNode
+			for _, child := range node.Children {
+				if child.Name == segment {
+					next = child
+					break
+				}
+			}
+			if next == nil {
+				next = &treeNode{Name: segment}
+				node.Children = append(node.Children, next)
+			}
+			node = next
+		}
+		node.Package = pkg
+	}
+
+	var collapse func(node *treeNode)
+	collapse = func(node *treeNode) {
+		for node.Package == nil && len(node.Children) == 1 {
+			child := node.Children[0]
+			node.Name = path.Join(node.Name, child.Name)
+			node.Package, node.Children = child.Package, child.Children
+		}
+		for _, child := range node.Children {
+			collapse(child)
+		}
+	}
+	for _, child := range root.Children {
+		collapse(child)
+	}
+	return root.Children
+}
+
+// reference is a resolved package reference of a declaration.
+type reference struct {
+	ImportPath string
+	Names      []string
+	Symbols    []*symbol
+}
+
+// references resolves the package references of a declaration with
+// the imports of the declaring file.
+func (s *server) references(sym *symbol) []*reference {
+	def := sym.Package.def
+	imports, _ := def.Imports.Map(def.Imports.Get(sym.Decl.File))
+
+	var result []*reference
+	for _, pkgName := range sym.Decl.References.Keys() {
+		importPath, ok := imports[pkgName]
+		if !ok {
+			importPath = pkgName
+		}
+		ref := &reference{ImportPath: importPath}
+		for _, name := range sym.Decl.References[pkgName] {
+			if target, ok := s.symbols[importPath+"#"+name]; ok {
+				ref.Symbols = append(ref.Symbols, target)
+				continue
+			}
+			ref.Names = append(ref.Names, name)
+		}
+		result = append(result, ref)
+	}
+	return result
+}
+
+// relation is a relationship to a symbol, which is nil for symbols
+// declared outside of the model.
+type relation struct {
+	Type   edges.RelationshipType
+	Name   string
+	Symbol *symbol
+}
+
+func (s *server) relations(list []*edges.Relationship, incoming bool) []*relation {
+	result := make([]*relation, 0, len(list))
+	for _, rel := range list {
+		edge := rel.To
+		if incoming {
+			edge = rel.From
+		}
+		result = append(result, &relation{
+			Type:   rel.Type,
+			Name:   edge.ImportPath + "." + edge.FullName(),
+			Symbol: s.symbols[edge.SymbolID()],
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Type != result[j].Type {
+			return result[i].Type < result[j].Type
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// graphData is the package dependency graph for the graph page.
+type graphData struct {
+	Nodes []graphNode `json:"nodes"`
+	Links []graphLink `json:"links"`
+}
+
+type graphNode struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	Symbols int    `json:"symbols"`
+	Test    bool   `json:"test,omitempty"`
+}
+
+type graphLink struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+func (s *server) graph() *graphData {
+	result := &graphData{
+		Nodes: []graphNode{},
+		Links: []graphLink{},
+	}
+	for _, pkg := range s.Packages {
+		result.Nodes = append(result.Nodes, graphNode{
+			ID:      pkg.ImportPath,
+			Name:    pkg.Name,
+			URL:     pkg.URL(),
+			Symbols: len(pkg.Symbols),
+			Test:    pkg.TestPackage,
+		})
+		for _, imported := range pkg.Imports {
+			result.Links = append(result.Links, graphLink{Source: pkg.ImportPath, Target: imported.ImportPath})
+		}
+	}
+	return result
+}
+
+// statsReport holds the `go-fsck stats` reports.
+type statsReport struct {
+	Documentation modules.DocumentationResponse
+	PackageStats  modules.PackageStatsResponse
+	ImportStats   modules.ImportStatsResponse
+	ReverseUsage  modules.ReverseUsageResponse
+}
+
+func (s *server) stats() *statsReport {
+	defs := model.DefinitionList(s.defs)
+	return &statsReport{
+		Documentation: modules.Documentation(defs),
+		PackageStats:  modules.PackageStats(defs),
+		ImportStats:   modules.ImportStats(defs),
+		ReverseUsage:  modules.ReverseUsage(defs),
+	}
+}
+
+func synopsis(text string) string {
+	return new(doc.Package).Synopsis(text)
+}
@@ -0,0 +1,142 @@
+package serve
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
+)
+
+const examplePath = "github.com/titpetric/exp/cmd/go-fsck/example"
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	defs, err := loader.ReadFile("../example/go-fsck.json")
+	require.NoError(t, err)
+
+	srv, err := newServer(&options{}, defs)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(srv.routes())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func get(t *testing.T, ts *httptest.Server, path string) (int, string) {
+	t.Helper()
+
+	res, err := http.Get(ts.URL + path)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res.StatusCode, string(body)
+}
+
+func TestServer_pages(t *testing.T) {
+	ts := newTestServer(t)
+
+	status, body := get(t, ts, "/")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `<a href="/pkg/`+examplePath+`">`)
+
+	status, body = get(t, ts, "/pkg/"+examplePath)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "Package example")
+	assert.Contains(t, body, `<a href="/symbol?id=`+url.QueryEscape(examplePath+"#Allocator")+`">Allocator</a>`)
+
+	status, body = get(t, ts, "/symbol?id="+url.QueryEscape(examplePath+"#Allocator"))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "type Allocator")
+	assert.Contains(t, body, "Used by")
+	assert.Contains(t, body, "receiver")
+
+	status, _ = get(t, ts, "/symbol?id=unknown")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, body = get(t, ts, "/stats")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "Reverse symbol usage")
+
+	status, body = get(t, ts, "/docs/")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, examplePath)
+
+	status, _ = get(t, ts, "/static/graph.js")
+	assert.Equal(t, http.StatusOK, status)
+
+	status, body = get(t, ts, "/api/graph")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"nodes"`)
+}
+
+func TestServer_graph(t *testing.T) {
+	defs := []*model.Definition{
+		{
+			Package: model.Package{Package: "store", ImportPath: "example.com/app/store"},
+			Types:   model.DeclarationList{{Kind: model.TypeKind, Name: "Store", File: "store.go"}},
+		},
+		{
+			Package: model.Package{Package: "api", ImportPath: "example.com/app/api"},
+			Imports: model.StringSet{"api.go": {`"example.com/app/store"`, `"net/http"`}},
+			Funcs:   model.DeclarationList{{Kind: model.FuncKind, Name: "New", File: "api.go", Arguments: []string{"*store.Store"}}},
+		},
+	}
+
+	srv, err := newServer(&options{}, defs)
+	require.NoError(t, err)
+
+	graph := srv.graph()
+	assert.Len(t, graph.Nodes, 2)
+	assert.Equal(t, []graphLink{{Source: "example.com/app/api", Target: "example.com/app/store"}}, graph.Links)
+
+	store := srv.packages["example.com/app/store"]
+	require.Len(t, store.ImportedBy, 1)
+	assert.Equal(t, "example.com/app/api", store.ImportedBy[0].ImportPath)
+
+	relations := srv.relations(srv.incoming["example.com/app/store#Store"], true)
+	require.Len(t, relations, 1)
+	assert.Equal(t, "argument", string(relations[0].Type))
+	assert.Equal(t, "example.com/app/api.New", relations[0].Name)
+}
+
+func TestLogRequests(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	handler := logRequests(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/symbol?name=Store", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Contains(t, buf.String(), "GET /symbol?name=Store ")
+}
+
+func TestNewTree(t *testing.T) {
+	tree := newTree([]*pkgInfo{
+		{ImportPath: "github.com/acme/app"},
+		{ImportPath: "github.com/acme/app/internal/store"},
+		{ImportPath: "github.com/acme/app/service"},
+	})
+
+	require.Len(t, tree, 1)
+	assert.Equal(t, "github.com/acme/app", tree[0].Name)
+	require.Len(t, tree[0].Children, 2)
+	assert.Equal(t, "internal/store", tree[0].Children[0].Name)
+	assert.Equal(t, "service", tree[0].Children[1].Name)
+}
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/tools/cover"
+
+	"github.com/titpetric/exp/cmd/go-fsck/edges"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+// modelTables are the snapshot tables of schema.sql. They are dropped
+// and recreated on every run, so a database written by an older version
+// gets the current columns.
+var modelTables = []string{"`packages`", "`imports`", "`types`", "`fields`", "`methods`", "`funcs`", "`vars`", "`consts`", "`references`"}
+
+// snapshotTables hold the current model and are cleared on every run.
+// The runs, complexity history and coverage blocks are kept.
+var snapshotTables = append(modelTables, "`relationships`", "`symbols`")
+
+// Drop drops the model tables before the schema is applied.
+func Drop(db *sqlx.DB) error {
+	for _, table := range modelTables {
+		if _, err := db.Exec("DROP TABLE IF EXISTS " + table); err != nil {
+			return fmt.Errorf("error dropping %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// Reset clears the snapshot tables before storing a new model.
+func Reset(db *sqlx.DB) error {
+	for _, table := range snapshotTables {
+		if _, err := db.Exec("DELETE FROM " + table); err != nil {
+			return fmt.Errorf("error clearing %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// StoreRun inserts a new run and returns the run ID.
+func StoreRun(db *sqlx.DB, inputFile, coverageFile string) (int64, error) {
+	result, err := db.Exec(`
+		INSERT INTO `+"`runs`"+`(
+			`+"`input_file`, `coverage_file`"+`
+		) VALUES (?, ?)`,
+		inputFile, coverageFile,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// StoreEdges stores the symbol graph of the definitions.
+func StoreEdges(graph *edges.DB, defs []*model.Definition) error {
+	symbols, relationships, err := edges.ExtractAll(defs)
+	if err != nil {
+		return err
+	}
+	return graph.InsertAll(symbols, relationships)
+}
+
+// StoreComplexity stores the function complexity for a run.
+func StoreComplexity(db *sqlx.DB, runID int64, defs []*model.Definition) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	for _, def := range defs {
+		for _, f := range def.Funcs {
+			if f.Complexity == nil {
+				continue
+			}
+
+			_, err = tx.Exec(`
+				INSERT INTO `+"`complexity`"+`(
+					`+"`run_id`, `package_id`, `import_path`, `file`, `name`, `receiver`, `cognitive`, `cyclomatic`, `coverage`, `lines`"+`
+				) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				runID, def.ID, def.Package.ImportPath, f.File, f.Name, f.Receiver, f.Complexity.Cognitive, f.Complexity.Cyclomatic, f.Complexity.Coverage, f.Complexity.Lines,
+			)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// StoreCoverage stores the blocks of a coverage profile for a run.
+// Profile file names are split into the import path and file name.
+func StoreCoverage(db *sqlx.DB, runID int64, profiles []*cover.Profile) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	for _, profile := range profiles {
+		importPath, file := path.Split(profile.FileName)
+		importPath = path.Clean(importPath)
+
+		for _, block := range profile.Blocks {
+			_, err = tx.Exec(`
+				INSERT INTO `+"`coverage_blocks`"+`(
+					`+"`run_id`, `import_path`, `file`, `start_line`, `start_col`, `end_line`, `end_col`, `statements`, `count`"+`
+				) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				runID, importPath, file, block.StartLine, block.StartCol, block.EndLine, block.EndCol, block.NumStmt, block.Count,
+			)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
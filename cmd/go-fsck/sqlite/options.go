@@ -9,7 +9,9 @@ import (
 )
 
 type options struct {
-	inputFile string
+	inputFile    string
+	outputFile   string
+	coverProfile string
 
 	filter    string
 	exclude   string
@@ -22,10 +24,13 @@ type options struct {
 
 func NewOptions() *options {
 	cfg := &options{
-		inputFile: "go-fsck.json",
+		inputFile:  "go-fsck.json",
+		outputFile: "go-fsck.db",
 	}
 
 	flag.StringVarP(&cfg.inputFile, "input-file", "i", cfg.inputFile, "input file")
+	flag.StringVarP(&cfg.outputFile, "output-file", "o", cfg.outputFile, "output sqlite database")
+	flag.StringVar(&cfg.coverProfile, "coverprofile", cfg.coverProfile, "coverage profile to store (go test -coverprofile)")
 
 	flag.StringVar(&cfg.filter, "filter", cfg.filter, "filter imports that match (sql LIKE)")
 	flag.StringVar(&cfg.exclude, "exclude", cfg.exclude, "exclude imports that match (sql NOT LIKE)")
@@ -7,16 +7,32 @@ import (
 	_ "embed"
 )
 
-//go:embed schema.sql
-var schema string
+var (
+	//go:embed schema.sql
+	schema string
+
+	//go:embed views.sql
+	views string
+)
 
 // Statements returns a list of statements expanded from the schema.
 func Statements() []string {
+	return statements(schema)
+}
+
+// Views returns the statements creating the predefined views. The views
+// query the symbol graph tables, so they are created after the schema
+// of the edges package.
+func Views() []string {
+	return statements(views)
+}
+
+func statements(source string) []string {
 	result := []string{}
 
 	// remove sql comments from anywhere ([whitespace]--*\n)
 	comments := regexp.MustCompile(`\s*--.*`)
-	contents := comments.ReplaceAll([]byte(schema), nil)
+	contents := comments.ReplaceAll([]byte(source), nil)
 
 	// split statements by trailing ; at the end of the line
 	stmts := regexp.MustCompilePOSIX(`;$`).Split(string(contents), -1)
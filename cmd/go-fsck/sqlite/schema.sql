@@ -1,5 +1,5 @@
 -- Table for packages
-CREATE TABLE `packages` (
+CREATE TABLE IF NOT EXISTS `packages` (
     `id` TEXT PRIMARY KEY,
     `name` TEXT,
     `import_path` TEXT,
@@ -8,33 +8,34 @@ CREATE TABLE `packages` (
 );
 
 -- Table for imports
-CREATE TABLE `imports` (
+CREATE TABLE IF NOT EXISTS `imports` (
     `package_id` TEXT,
     `path` TEXT,
     `file` TEXT,
     `import` TEXT
 );
-CREATE INDEX `idx_imports_pkg` ON `imports`(`package_id`);
-CREATE INDEX `idx_imports_file` ON `imports`(`file`);
-CREATE INDEX `idx_imports_path` ON `imports`(`path`);
+CREATE INDEX IF NOT EXISTS `idx_imports_pkg` ON `imports`(`package_id`);
+CREATE INDEX IF NOT EXISTS `idx_imports_file` ON `imports`(`file`);
+CREATE INDEX IF NOT EXISTS `idx_imports_path` ON `imports`(`path`);
 
 -- Table for types
-CREATE TABLE `types` (
+CREATE TABLE IF NOT EXISTS `types` (
     `package_id` TEXT,
     `path` TEXT,
     `file` TEXT,
     `name` TEXT,
+    `line` INTEGER,
     `kind` TEXT,
     `doc` TEXT,
     `signature` TEXT
 );
-CREATE INDEX `idx_types_pkg` ON `types`(`package_id`);
-CREATE INDEX `idx_types_file` ON `types`(`file`);
-CREATE INDEX `idx_types_path` ON `types`(`path`);
-CREATE INDEX `idx_types_name` ON `types`(`name`);
+CREATE INDEX IF NOT EXISTS `idx_types_pkg` ON `types`(`package_id`);
+CREATE INDEX IF NOT EXISTS `idx_types_file` ON `types`(`file`);
+CREATE INDEX IF NOT EXISTS `idx_types_path` ON `types`(`path`);
+CREATE INDEX IF NOT EXISTS `idx_types_name` ON `types`(`name`);
 
 -- Table for struct/interface fields
-CREATE TABLE `fields` (
+CREATE TABLE IF NOT EXISTS `fields` (
     `type_name` TEXT,
     `package_id` TEXT,
     `path` TEXT,
@@ -47,16 +48,31 @@ CREATE TABLE `fields` (
     `comment` TEXT,
     `tag` TEXT
 );
-CREATE INDEX `idx_fields_pkg` ON `fields`(`package_id`);
-CREATE INDEX `idx_fields_file` ON `fields`(`file`);
-CREATE INDEX `idx_fields_type` ON `fields`(`type_name`);
-CREATE INDEX `idx_fields_name` ON `fields`(`name`);
+CREATE INDEX IF NOT EXISTS `idx_fields_pkg` ON `fields`(`package_id`);
+CREATE INDEX IF NOT EXISTS `idx_fields_file` ON `fields`(`file`);
+CREATE INDEX IF NOT EXISTS `idx_fields_type` ON `fields`(`type_name`);
+CREATE INDEX IF NOT EXISTS `idx_fields_name` ON `fields`(`name`);
+
+-- Table for struct methods, linking receiver types to funcs
+CREATE TABLE IF NOT EXISTS `methods` (
+    `package_id` TEXT,
+    `file` TEXT,
+    `line` INTEGER,
+    `type_name` TEXT,
+    `name` TEXT,
+    `pointer` INTEGER,
+    `signature` TEXT
+);
+CREATE INDEX IF NOT EXISTS `idx_methods_pkg` ON `methods`(`package_id`);
+CREATE INDEX IF NOT EXISTS `idx_methods_type` ON `methods`(`package_id`, `type_name`);
 
 -- Table for functions
-CREATE TABLE `funcs` (
+CREATE TABLE IF NOT EXISTS `funcs` (
     `package_id` TEXT,
     `path` TEXT,
     `file` TEXT,
+    `line` INTEGER,
+    `end_line` INTEGER,
     `name` TEXT,
     `type` TEXT,
     `receiver` TEXT,
@@ -66,47 +82,88 @@ CREATE TABLE `funcs` (
     `complexity_coverage` FLOAT,
     `complexity_lines` INTEGER
 );
-CREATE INDEX `idx_funcs_pkg` ON `funcs`(`package_id`);
-CREATE INDEX `idx_funcs_file` ON `funcs`(`file`);
-CREATE INDEX `idx_funcs_path` ON `funcs`(`path`);
-CREATE INDEX `idx_funcs_name` ON `funcs`(`name`);
+CREATE INDEX IF NOT EXISTS `idx_funcs_pkg` ON `funcs`(`package_id`);
+CREATE INDEX IF NOT EXISTS `idx_funcs_file` ON `funcs`(`file`);
+CREATE INDEX IF NOT EXISTS `idx_funcs_path` ON `funcs`(`path`);
+CREATE INDEX IF NOT EXISTS `idx_funcs_name` ON `funcs`(`name`);
 
 -- Table for variables
-CREATE TABLE `vars` (
+CREATE TABLE IF NOT EXISTS `vars` (
     `package_id` TEXT,
     `path` TEXT,
     `file` TEXT,
     `name` TEXT,
     `type` TEXT
 );
-CREATE INDEX `idx_vars_pkg` ON `vars`(`package_id`);
-CREATE INDEX `idx_vars_file` ON `vars`(`file`);
-CREATE INDEX `idx_vars_path` ON `vars`(`path`);
-CREATE INDEX `idx_vars_name` ON `vars`(`name`);
+CREATE INDEX IF NOT EXISTS `idx_vars_pkg` ON `vars`(`package_id`);
+CREATE INDEX IF NOT EXISTS `idx_vars_file` ON `vars`(`file`);
+CREATE INDEX IF NOT EXISTS `idx_vars_path` ON `vars`(`path`);
+CREATE INDEX IF NOT EXISTS `idx_vars_name` ON `vars`(`name`);
 
 -- Table for constants
-CREATE TABLE `consts` (
+CREATE TABLE IF NOT EXISTS `consts` (
     `package_id` TEXT,
     `path` TEXT,
     `file` TEXT,
     `name` TEXT,
     `type` TEXT
 );
-CREATE INDEX `idx_consts_pkg` ON `consts`(`package_id`);
-CREATE INDEX `idx_consts_file` ON `consts`(`file`);
-CREATE INDEX `idx_consts_path` ON `consts`(`path`);
-CREATE INDEX `idx_consts_name` ON `consts`(`name`);
+CREATE INDEX IF NOT EXISTS `idx_consts_pkg` ON `consts`(`package_id`);
+CREATE INDEX IF NOT EXISTS `idx_consts_file` ON `consts`(`file`);
+CREATE INDEX IF NOT EXISTS `idx_consts_path` ON `consts`(`path`);
+CREATE INDEX IF NOT EXISTS `idx_consts_name` ON `consts`(`name`);
 
 -- Table for references
-CREATE TABLE `references` (
+CREATE TABLE IF NOT EXISTS `references` (
     `package_id` TEXT,
     `path` TEXT,
     `file` TEXT,
     `from_symbol` TEXT,
     `to_symbol` TEXT
 );
-CREATE INDEX `idx_refs_pkg` ON `references`(`package_id`);
-CREATE INDEX `idx_refs_file` ON `references`(`file`);
-CREATE INDEX `idx_refs_path` ON `references`(`path`);
-CREATE INDEX `idx_refs_from` ON `references`(`from_symbol`);
-CREATE INDEX `idx_refs_to` ON `references`(`to_symbol`);
+CREATE INDEX IF NOT EXISTS `idx_refs_pkg` ON `references`(`package_id`);
+CREATE INDEX IF NOT EXISTS `idx_refs_file` ON `references`(`file`);
+CREATE INDEX IF NOT EXISTS `idx_refs_path` ON `references`(`path`);
+CREATE INDEX IF NOT EXISTS `idx_refs_from` ON `references`(`from_symbol`);
+CREATE INDEX IF NOT EXISTS `idx_refs_to` ON `references`(`to_symbol`);
+
+-- Table for runs, each export adds a run
+CREATE TABLE IF NOT EXISTS `runs` (
+    `id` INTEGER PRIMARY KEY AUTOINCREMENT,
+    `created_at` TEXT DEFAULT CURRENT_TIMESTAMP,
+    `input_file` TEXT,
+    `coverage_file` TEXT
+);
+
+-- Table for complexity history, one row per func and run
+CREATE TABLE IF NOT EXISTS `complexity` (
+    `run_id` INTEGER,
+    `package_id` TEXT,
+    `import_path` TEXT,
+    `file` TEXT,
+    `name` TEXT,
+    `receiver` TEXT,
+    `cognitive` INTEGER,
+    `cyclomatic` INTEGER,
+    `coverage` FLOAT,
+    `lines` INTEGER,
+    FOREIGN KEY(`run_id`) REFERENCES `runs`(`id`) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS `idx_complexity_run` ON `complexity`(`run_id`);
+CREATE INDEX IF NOT EXISTS `idx_complexity_func` ON `complexity`(`import_path`, `receiver`, `name`);
+
+-- Table for coverage profile blocks, per file and run
+CREATE TABLE IF NOT EXISTS `coverage_blocks` (
+    `run_id` INTEGER,
+    `import_path` TEXT,
+    `file` TEXT,
+    `start_line` INTEGER,
+    `start_col` INTEGER,
+    `end_line` INTEGER,
+    `end_col` INTEGER,
+    `statements` INTEGER,
+    `count` INTEGER,
+    FOREIGN KEY(`run_id`) REFERENCES `runs`(`id`) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS `idx_coverage_run` ON `coverage_blocks`(`run_id`);
+CREATE INDEX IF NOT EXISTS `idx_coverage_file` ON `coverage_blocks`(`import_path`, `file`);
@@ -1,6 +1,7 @@
 package sqlite
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,3 +11,18 @@ func TestStatements(t *testing.T) {
 	data := Statements()
 	assert.True(t, len(data) > 0, "expected at least one schema migration")
 }
+
+func TestModelTables(t *testing.T) {
+	data := strings.Join(Statements(), "\n")
+	for _, table := range modelTables {
+		assert.Contains(t, data, "CREATE TABLE IF NOT EXISTS "+table)
+	}
+}
+
+func TestViews(t *testing.T) {
+	data := Views()
+	assert.True(t, len(data) > 0, "expected at least one view")
+	for _, stmt := range data {
+		assert.Contains(t, stmt, "CREATE VIEW IF NOT EXISTS")
+	}
+}
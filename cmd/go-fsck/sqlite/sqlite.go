@@ -2,11 +2,12 @@ package sqlite
 
 import (
 	"context"
-	"errors"
-	"os"
+	"fmt"
 
 	"github.com/go-bridget/mig/db"
+	"golang.org/x/tools/cover"
 
+	"github.com/titpetric/exp/cmd/go-fsck/edges"
 	"github.com/titpetric/exp/cmd/go-fsck/internal"
 	"github.com/titpetric/exp/cmd/go-fsck/model"
 	"github.com/titpetric/exp/cmd/go-fsck/model/loader"
@@ -25,14 +26,16 @@ func getDefinitions(cfg *options) ([]*model.Definition, error) {
 		return nil, err
 	}
 
-	defs = []*model.Definition{}
-
-	for _, pkg := range packages {
-		d, err := loader.Load(pkg, false, cfg.verbose)
-		if err != nil {
-			return nil, err
-		}
+	results, err := loader.LoadAll(context.Background(), packages, &loader.Options{
+		IncludeTests: false,
+		Verbose:      cfg.verbose,
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	defs = []*model.Definition{}
+	for _, d := range results {
 		defs = append(defs, d...)
 	}
 
@@ -45,34 +48,65 @@ func sqliteRun(cfg *options) error {
 		return err
 	}
 
-	ctx := context.Background()
+	var profiles []*cover.Profile
+	if cfg.coverProfile != "" {
+		profiles, err = cover.ParseProfiles(cfg.coverProfile)
+		if err != nil {
+			return fmt.Errorf("error reading coverage profile: %w", err)
+		}
+	}
 
-	_, err = os.Stat("go-fsck.db")
-	create := errors.Is(err, os.ErrNotExist)
+	ctx := context.Background()
 
-	// Aggregations are easier in SQL... the following block of
-	// code uses an sqlite in-memory database to do some math.
 	conn, err := db.ConnectWithOptions(ctx, &db.Options{
 		Credentials: db.Credentials{
-			DSN:    "file:go-fsck.db",
+			DSN:    "file:" + cfg.outputFile,
 			Driver: "sqlite",
 		},
 	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
 
+	// The model tables are recreated with the current schema, the
+	// history tables are created if they don't exist.
+	if err := Drop(conn); err != nil {
+		return err
+	}
+	for _, stmt := range Statements() {
+		conn.MustExec(stmt)
+	}
+	graph, err := edges.NewDBWithConn(conn.DB)
 	if err != nil {
 		return err
 	}
+	for _, stmt := range Views() {
+		conn.MustExec(stmt)
+	}
 
-	if create {
-		for _, stmt := range Statements() {
-			conn.MustExec(stmt)
+	// The model is replaced, the run history is appended to.
+	if err := Reset(conn); err != nil {
+		return err
+	}
+	for _, def := range defs {
+		if err := Store(conn, def); err != nil {
+			return err
 		}
+	}
+	if err := StoreEdges(graph, defs); err != nil {
+		return err
+	}
 
-		for _, def := range defs {
-			if err := Store(conn, def); err != nil {
-				return err
-			}
-		}
+	runID, err := StoreRun(conn, cfg.inputFile, cfg.coverProfile)
+	if err != nil {
+		return err
+	}
+	if err := StoreComplexity(conn, runID, defs); err != nil {
+		return err
+	}
+	if err := StoreCoverage(conn, runID, profiles); err != nil {
+		return err
 	}
 
 	if err := Stats(conn); err != nil {
@@ -2,6 +2,7 @@ package sqlite
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 
@@ -105,17 +106,36 @@ func Store(db *sqlx.DB, def *model.Definition) error {
 
 	// Insert funcs
 	for _, f := range def.Funcs {
+		complexity := f.Complexity
+		if complexity == nil {
+			complexity = &model.Complexity{}
+		}
+
 		_, err = tx.Exec(`
 			INSERT INTO `+"`funcs`"+`(
-				`+"`package_id`, `path`, `file`, `name`, `receiver`, `signature`, `complexity_cognitive`, `complexity_cyclomatic`, `complexity_coverage`, `complexity_lines`"+`
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-			def.ID, f.File, f.File, f.Name, f.Receiver, f.Signature, f.Complexity.Cognitive, f.Complexity.Cyclomatic, f.Complexity.Coverage, f.Complexity.Lines,
+				`+"`package_id`, `path`, `file`, `line`, `end_line`, `name`, `receiver`, `signature`, `complexity_cognitive`, `complexity_cyclomatic`, `complexity_coverage`, `complexity_lines`"+`
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			def.ID, f.File, f.File, f.Line, f.LastLine(), f.Name, f.Receiver, f.Signature, complexity.Cognitive, complexity.Cyclomatic, complexity.Coverage, complexity.Lines,
 		)
 		if err != nil {
 			tx.Rollback()
 			return err
 		}
 
+		// Insert method for the receiver type
+		if f.HasReceiver() {
+			_, err = tx.Exec(`
+				INSERT INTO `+"`methods`"+`(
+					`+"`package_id`, `file`, `line`, `type_name`, `name`, `pointer`, `signature`"+`
+				) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				def.ID, f.File, f.Line, f.ReceiverTypeRef(), f.Name, strings.HasPrefix(f.Receiver, "*"), f.Signature,
+			)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+
 		// References for func
 		for from, tos := range f.References {
 			for _, to := range tos {
@@ -137,9 +157,9 @@ func Store(db *sqlx.DB, def *model.Definition) error {
 	for _, t := range def.Types {
 		_, err = tx.Exec(`
 			INSERT INTO `+"`types`"+`(
-				`+"`package_id`, `path`, `file`, `name`, `kind`, `doc`, `signature`"+`
-			) VALUES (?, ?, ?, ?, ?, ?, ?)`,
-			def.ID, t.File, t.File, t.Name, t.Kind.String(), t.Doc, t.Signature,
+				`+"`package_id`, `path`, `file`, `line`, `name`, `kind`, `doc`, `signature`"+`
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			def.ID, t.File, t.File, t.Line, t.Name, t.Kind.String(), t.Doc, t.Signature,
 		)
 		if err != nil {
 			tx.Rollback()
@@ -166,7 +186,7 @@ func Store(db *sqlx.DB, def *model.Definition) error {
 
 // Stats prints the count of records in each table using sqlx.DB
 func Stats(db *sqlx.DB) error {
-	tables := []string{"`packages`", "`imports`", "`types`", "`fields`", "`funcs`", "`vars`", "`consts`", "`references`"}
+	tables := []string{"`packages`", "`imports`", "`types`", "`fields`", "`methods`", "`funcs`", "`vars`", "`consts`", "`references`", "`symbols`", "`relationships`", "`runs`", "`complexity`", "`coverage_blocks`"}
 	for _, table := range tables {
 		var count int
 		err := db.Get(&count, fmt.Sprintf("SELECT COUNT(*) FROM %s", table))
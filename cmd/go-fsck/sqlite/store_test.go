@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+
+	"github.com/titpetric/exp/cmd/go-fsck/edges"
+	"github.com/titpetric/exp/cmd/go-fsck/model"
+)
+
+func newTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	db, err := sqlx.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	// Each connection to :memory: is a new database.
+	db.SetMaxOpenConns(1)
+
+	for _, stmt := range Statements() {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err, stmt)
+	}
+	_, err = edges.NewDBWithConn(db.DB)
+	require.NoError(t, err)
+	for _, stmt := range Views() {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err, stmt)
+	}
+	return db
+}
+
+func testDefinition() *model.Definition {
+	return &model.Definition{
+		Package: model.Package{ID: "example.com/app", ImportPath: "example.com/app", Package: "app", Path: "."},
+		Funcs: model.DeclarationList{
+			{
+				Kind:       model.FuncKind,
+				Name:       "Open",
+				File:       "main.go",
+				Line:       5,
+				Doc:        "// Open opens the store.\n// It spans three\n// doc lines.",
+				Source:     "// Open opens the store.\n// It spans three\n// doc lines.\nfunc Open() {\n\tprintln()\n}",
+				Complexity: &model.Complexity{Cognitive: 1, Lines: 6},
+			},
+			{
+				Kind:       model.FuncKind,
+				Name:       "Close",
+				Receiver:   "*Store",
+				File:       "main.go",
+				Line:       9,
+				Doc:        "// Close closes the store.",
+				Source:     "// Close closes the store.\nfunc (s *Store) Close() {\n\tprintln()\n}",
+				Complexity: &model.Complexity{Cognitive: 1, Lines: 4},
+			},
+		},
+	}
+}
+
+func TestStore(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, Store(db, testDefinition()))
+
+	var lines []int
+	require.NoError(t, db.Select(&lines, "SELECT `end_line` FROM `funcs` ORDER BY `line`"))
+	assert.Equal(t, []int{7, 11}, lines)
+
+	var methods int
+	require.NoError(t, db.Get(&methods, "SELECT COUNT(*) FROM `methods` WHERE `type_name` = 'Store'"))
+	assert.Equal(t, 1, methods)
+}
+
+func TestStoreCoverage(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, Store(db, testDefinition()))
+
+	runID, err := StoreRun(db, "go-fsck.json", "coverage.out")
+	require.NoError(t, err)
+	require.NoError(t, StoreCoverage(db, runID, []*cover.Profile{
+		{
+			FileName: "example.com/app/main.go",
+			Mode:     "set",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 5, StartCol: 13, EndLine: 7, EndCol: 2, NumStmt: 1, Count: 1},
+				{StartLine: 9, StartCol: 25, EndLine: 10, EndCol: 12, NumStmt: 1, Count: 0},
+			},
+		},
+	}))
+
+	type funcCoverage struct {
+		Name       string `db:"name"`
+		Statements int    `db:"statements"`
+		Covered    int    `db:"covered"`
+	}
+	var rows []funcCoverage
+	require.NoError(t, db.Select(&rows, "SELECT `name`, `statements`, `covered` FROM `func_coverage` ORDER BY `line`"))
+	assert.Equal(t, []funcCoverage{
+		{Name: "Open", Statements: 1, Covered: 1},
+		{Name: "Close", Statements: 1, Covered: 0},
+	}, rows)
+}
+
+func TestReset(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, Store(db, testDefinition()))
+
+	runID, err := StoreRun(db, "go-fsck.json", "")
+	require.NoError(t, err)
+	require.NoError(t, StoreComplexity(db, runID, []*model.Definition{testDefinition()}))
+
+	require.NoError(t, Reset(db))
+
+	var funcs, runs, complexity int
+	require.NoError(t, db.Get(&funcs, "SELECT COUNT(*) FROM `funcs`"))
+	require.NoError(t, db.Get(&runs, "SELECT COUNT(*) FROM `runs`"))
+	require.NoError(t, db.Get(&complexity, "SELECT COUNT(*) FROM `complexity`"))
+	assert.Equal(t, 0, funcs)
+	assert.Equal(t, 1, runs)
+	assert.Equal(t, 2, complexity)
+}
@@ -0,0 +1,79 @@
+-- Complex functions with low coverage, highest score first
+CREATE VIEW IF NOT EXISTS `hotspots` AS
+SELECT
+    p.`import_path`,
+    f.`file`,
+    f.`line`,
+    f.`receiver`,
+    f.`name`,
+    f.`complexity_cognitive` AS `cognitive`,
+    f.`complexity_coverage` AS `coverage`,
+    f.`complexity_cognitive` * (100 - COALESCE(f.`complexity_coverage`, 0)) / 100.0 AS `score`
+FROM `funcs` f
+JOIN `packages` p ON p.`id` = f.`package_id`
+WHERE f.`complexity_cognitive` > 0 AND f.`file` NOT LIKE '%\_test.go' ESCAPE '\'
+ORDER BY `score` DESC;
+
+-- Exported functions and methods without a test relationship
+CREATE VIEW IF NOT EXISTS `untested_exported_funcs` AS
+SELECT
+    s.`import_path`,
+    s.`file`,
+    s.`line`,
+    s.`receiver`,
+    s.`symbol_name` AS `name`
+FROM `symbols` s
+WHERE s.`symbol_kind` = 'func' AND s.`is_exported` = 1
+    AND s.`file` != '' AND s.`file` NOT LIKE '%\_test.go' ESCAPE '\'
+    AND NOT EXISTS (
+        SELECT 1 FROM `relationships` r
+        WHERE r.`to_id` = s.`id` AND r.`relationship_type` = 'test'
+    )
+ORDER BY s.`import_path`, s.`receiver`, s.`symbol_name`;
+
+-- Types with the most dependent symbols and packages
+CREATE VIEW IF NOT EXISTS `most_coupled_types` AS
+SELECT
+    s.`import_path`,
+    s.`symbol_name` AS `name`,
+    COUNT(DISTINCT r.`from_id`) AS `dependents`,
+    COUNT(DISTINCT f.`import_path`) AS `packages`
+FROM `symbols` s
+JOIN `relationships` r ON r.`to_id` = s.`id` AND r.`relationship_type` != 'test'
+JOIN `symbols` f ON f.`id` = r.`from_id`
+WHERE s.`symbol_kind` = 'type' AND s.`file` != ''
+GROUP BY s.`id`
+ORDER BY `dependents` DESC, `packages` DESC, s.`import_path`, s.`symbol_name`;
+
+-- Statement coverage per function from the blocks of the latest run
+CREATE VIEW IF NOT EXISTS `func_coverage` AS
+SELECT
+    p.`import_path`,
+    f.`file`,
+    f.`line`,
+    f.`receiver`,
+    f.`name`,
+    SUM(b.`statements`) AS `statements`,
+    SUM(CASE WHEN b.`count` > 0 THEN b.`statements` ELSE 0 END) AS `covered`
+FROM `funcs` f
+JOIN `packages` p ON p.`id` = f.`package_id`
+JOIN `coverage_blocks` b ON b.`import_path` = p.`import_path` AND b.`file` = f.`file`
+    AND b.`start_line` >= f.`line` AND b.`end_line` <= f.`end_line`
+WHERE b.`run_id` = (SELECT MAX(`id`) FROM `runs`)
+GROUP BY p.`import_path`, f.`file`, f.`line`, f.`receiver`, f.`name`;
+
+-- Complexity of each function over the runs
+CREATE VIEW IF NOT EXISTS `complexity_history` AS
+SELECT
+    c.`import_path`,
+    c.`receiver`,
+    c.`name`,
+    r.`id` AS `run_id`,
+    r.`created_at`,
+    c.`cognitive`,
+    c.`cyclomatic`,
+    c.`coverage`,
+    c.`lines`
+FROM `complexity` c
+JOIN `runs` r ON r.`id` = c.`run_id`
+ORDER BY c.`import_path`, c.`receiver`, c.`name`, r.`id`;
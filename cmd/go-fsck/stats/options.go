@@ -18,6 +18,7 @@ type options struct {
 	full    bool
 	json    bool
 	verbose bool
+	jobs    int
 }
 
 func NewOptions() *options {
@@ -33,6 +34,7 @@ func NewOptions() *options {
 	flag.BoolVar(&cfg.full, "full", cfg.full, "resolve imports to full path")
 	flag.BoolVar(&cfg.json, "json", cfg.json, "print results as json")
 	flag.BoolVarP(&cfg.verbose, "verbose", "v", cfg.verbose, "verbose output")
+	flag.IntVarP(&cfg.jobs, "jobs", "j", cfg.jobs, "number of packages to load in parallel (default GOMAXPROCS)")
 	flag.Parse()
 
 	return cfg
@@ -1,6 +1,7 @@
 package stats
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -23,14 +24,17 @@ func getDefinitions(cfg *options) ([]*model.Definition, error) {
 		return nil, err
 	}
 
-	defs = []*model.Definition{}
-
-	for _, pkg := range packages {
-		d, err := loader.Load(pkg, true, cfg.verbose)
-		if err != nil {
-			return nil, err
-		}
+	results, err := loader.LoadAll(context.Background(), packages, &loader.Options{
+		IncludeTests: true,
+		Verbose:      cfg.verbose,
+		Jobs:         cfg.jobs,
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	defs = []*model.Definition{}
+	for _, d := range results {
 		defs = append(defs, d...)
 	}
 
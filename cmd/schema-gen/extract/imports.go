@@ -0,0 +1,221 @@
+package extract
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/titpetric/exp/cmd/schema-gen/model"
+)
+
+// typeToken matches identifiers and qualified identifiers in a type
+// declaration, e.g. `map`, `string` and `model.Inner` in `map[string]*model.Inner`.
+var typeToken = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?`)
+
+// importResolver follows the types referenced from other packages.
+type importResolver struct {
+	options *model.ExtractOptions
+	module  string
+
+	// packages holds the loaded packages by import path.
+	packages map[string]*packages.Package
+	// infos holds the extracted packages by import path.
+	infos map[string]*model.PackageInfo
+	// wanted holds the types referenced in each followed package.
+	wanted map[string]map[string]bool
+}
+
+// followImports extracts the types referenced by the packages in dir
+// from other packages, and appends them to the result as separate
+// package entries. Only the referenced types and their dependencies
+// are kept. Packages outside of the module are followed with
+// IncludeDependencies, the standard library is never followed.
+func followImports(dir string, result []*model.PackageInfo, options *model.ExtractOptions) ([]*model.PackageInfo, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedModule,
+		Dir:  absDir,
+	}, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) == 0 {
+		return result, nil
+	}
+
+	r := &importResolver{
+		options:  options,
+		packages: map[string]*packages.Package{},
+		infos:    map[string]*model.PackageInfo{},
+		wanted:   map[string]map[string]bool{},
+	}
+	if root := pkgs[0]; root.Module != nil {
+		r.module = root.Module.Path
+	}
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		r.packages[p.PkgPath] = p
+	})
+
+	for _, info := range result {
+		if info.Name != pkgs[0].Name {
+			continue
+		}
+		info.ImportPath = pkgs[0].PkgPath
+		for _, decl := range info.Declarations {
+			for _, t := range decl.Types {
+				r.visit(info, t)
+			}
+		}
+	}
+
+	importPaths := make([]string, 0, len(r.infos))
+	for importPath := range r.infos {
+		importPaths = append(importPaths, importPath)
+	}
+	sort.Strings(importPaths)
+
+	for _, importPath := range importPaths {
+		info := r.infos[importPath]
+		info.Declarations = filterTypes(info.Declarations, r.wanted[importPath])
+		info.Functions = nil
+		result = append(result, info)
+	}
+	return result, nil
+}
+
+// visit follows the types referenced by t, declared in info.
+func (r *importResolver) visit(info *model.PackageInfo, t *model.TypeInfo) {
+	types := info.Declarations.TypeMap()
+	aliases := r.aliases(info)
+
+	refs := []string{t.Type}
+	for _, field := range t.Fields {
+		refs = append(refs, field.Type)
+	}
+
+	for _, ref := range refs {
+		for _, token := range typeToken.FindAllString(ref, -1) {
+			alias, name, qualified := strings.Cut(token, ".")
+			if !qualified {
+				// Types declared in followed packages are added with
+				// the types referencing them.
+				if local, ok := types[token]; ok && r.want(info.ImportPath, token) {
+					r.visit(info, local)
+				}
+				continue
+			}
+
+			target := r.extract(aliases[alias])
+			if target == nil {
+				continue
+			}
+			if typ := target.Declarations.TypeInfo(name); typ != nil && r.want(target.ImportPath, name) {
+				r.visit(target, typ)
+			}
+		}
+	}
+}
+
+// want marks a type in a followed package as referenced. It returns
+// false for types already marked and for types of the root package.
+func (r *importResolver) want(importPath, name string) bool {
+	if _, followed := r.infos[importPath]; !followed {
+		return false
+	}
+	if r.wanted[importPath] == nil {
+		r.wanted[importPath] = map[string]bool{}
+	}
+	if r.wanted[importPath][name] {
+		return false
+	}
+	r.wanted[importPath][name] = true
+	return true
+}
+
+// aliases maps the package names used in info to import paths.
+func (r *importResolver) aliases(info *model.PackageInfo) map[string]string {
+	result := map[string]string{}
+	for _, imported := range info.Imports {
+		alias, importPath, aliased := strings.Cut(imported, " ")
+		if !aliased {
+			importPath = alias
+			alias = ""
+		}
+		importPath = strings.Trim(importPath, `"`)
+
+		if alias == "" {
+			alias = path.Base(importPath)
+			if p, ok := r.packages[importPath]; ok && p.Name != "" {
+				alias = p.Name
+			}
+		}
+		if alias == "_" || alias == "." {
+			continue
+		}
+		result[alias] = importPath
+	}
+	return result
+}
+
+// extract returns the extracted package for an import path, or nil if
+// the package isn't followed.
+func (r *importResolver) extract(importPath string) *model.PackageInfo {
+	if info, ok := r.infos[importPath]; ok {
+		return info
+	}
+
+	p, ok := r.packages[importPath]
+	if !ok || len(p.GoFiles) == 0 || p.Module == nil {
+		return nil
+	}
+	if p.Module.Path != r.module && !r.options.IncludeDependencies {
+		return nil
+	}
+
+	infos, err := Extract(filepath.Dir(p.GoFiles[0])+"/", &model.ExtractOptions{
+		IncludeUnexported: r.options.IncludeUnexported,
+		IncludeInternal:   r.options.IncludeInternal,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARN: can't extract %s: %v\n", importPath, err)
+		return nil
+	}
+
+	for _, info := range infos {
+		if info.Name == p.Name {
+			info.ImportPath = importPath
+			r.infos[importPath] = info
+			return info
+		}
+	}
+	return nil
+}
+
+// filterTypes keeps the type declarations listed in names.
+func filterTypes(decls model.DeclarationList, names map[string]bool) model.DeclarationList {
+	result := model.DeclarationList{}
+	for _, decl := range decls {
+		types := model.TypeList{}
+		for _, t := range decl.Types {
+			if names[t.Name] {
+				types = append(types, t)
+			}
+		}
+		if len(types) == 0 {
+			continue
+		}
+		decl.Types = types
+		result = append(result, decl)
+	}
+	return result
+}
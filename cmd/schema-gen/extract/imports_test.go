@@ -0,0 +1,88 @@
+package extract
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/schema-gen/internal/fixture"
+	"github.com/titpetric/exp/cmd/schema-gen/model"
+)
+
+// writeModule writes a module with an api package using types from
+// the model and types packages. The types package is named tags.
+func writeModule(t *testing.T) string {
+	t.Helper()
+
+	return fixture.WriteModule(t, map[string]string{
+		"api/api.go": `package api
+
+import (
+	"time"
+
+	m "example.com/app/model"
+	"example.com/app/types"
+)
+
+// Request is the api request.
+type Request struct {
+	User *m.User     ` + "`json:\"user\"`" + `
+	Tags []tags.Tag  ` + "`json:\"tags\"`" + `
+	At   time.Time   ` + "`json:\"at\"`" + `
+}
+`,
+		"model/model.go": `package model
+
+// User is a user.
+type User struct {
+	Name  string ` + "`json:\"name\"`" + `
+	Roles Roles  ` + "`json:\"roles\"`" + `
+}
+
+type Roles []Role
+
+type Role string
+
+const (
+	Admin Role = "admin"
+	Guest Role = "guest"
+)
+
+type Unused struct{}
+`,
+		"types/types.go": `package tags
+
+type Tag struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+`,
+	})
+}
+
+func TestExtract_followImports(t *testing.T) {
+	dir := writeModule(t)
+
+	pkgInfos, err := Extract(filepath.Join(dir, "api")+"/", &model.ExtractOptions{FollowImports: true})
+	require.NoError(t, err)
+	require.Len(t, pkgInfos, 3)
+
+	assert.Equal(t, "api", pkgInfos[0].Name)
+	assert.Equal(t, "example.com/app/api", pkgInfos[0].ImportPath)
+
+	assert.Equal(t, "model", pkgInfos[1].Name)
+	assert.Equal(t, "example.com/app/model", pkgInfos[1].ImportPath)
+	order, _ := pkgInfos[1].Declarations.TypeDeclarations()
+	assert.ElementsMatch(t, []string{"User", "Roles", "Role"}, order)
+	assert.Len(t, pkgInfos[1].Declarations.TypeInfo("Role").Enums, 2)
+
+	assert.Equal(t, "tags", pkgInfos[2].Name)
+	assert.Equal(t, "example.com/app/types", pkgInfos[2].ImportPath)
+	assert.NotNil(t, pkgInfos[2].Declarations.TypeInfo("Tag"))
+
+	pkgInfos, err = Extract(filepath.Join(dir, "api")+"/", &model.ExtractOptions{})
+	require.NoError(t, err)
+	require.Len(t, pkgInfos, 1)
+	assert.Empty(t, pkgInfos[0].ImportPath)
+}
@@ -16,6 +16,9 @@ type options struct {
 	includeInternal   bool
 	ignoreFiles       []string
 
+	followImports bool
+	includeDeps   bool
+
 	prettyJSON bool
 }
 
@@ -31,6 +34,8 @@ func NewOptions() *options {
 	flag.BoolVar(&cfg.includeUnexported, "include-unexported", cfg.includeUnexported, "include unexported symbols")
 	flag.BoolVar(&cfg.includeTests, "include-tests", cfg.includeTests, "include test files")
 	flag.BoolVar(&cfg.includeInternal, "include-internal", cfg.includeInternal, "include internal packages")
+	flag.BoolVar(&cfg.followImports, "follow-imports", cfg.followImports, "include types imported from other packages in the module")
+	flag.BoolVar(&cfg.includeDeps, "include-deps", cfg.includeDeps, "follow imported types into dependencies (with --follow-imports)")
 	flag.StringSliceVarP(&cfg.ignoreFiles, "ignore-files", "", cfg.ignoreFiles, "ignore files (csv)")
 	flag.BoolVar(&cfg.prettyJSON, "pretty-json", cfg.prettyJSON, "print pretty json")
 	flag.Parse()
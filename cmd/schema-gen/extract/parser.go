@@ -26,6 +26,9 @@ func NewExtractOptions(cfg *options) *model.ExtractOptions {
 		IncludeUnexported: cfg.includeUnexported,
 		IgnoreFiles:       cfg.ignoreFiles,
 		IncludeInternal:   cfg.includeInternal,
+
+		FollowImports:       cfg.followImports,
+		IncludeDependencies: cfg.includeDeps,
 	}
 }
 
@@ -69,6 +72,15 @@ func Extract(filepath string, options *model.ExtractOptions) ([]*model.PackageIn
 		}
 		result = append(result, pkgInfo)
 	}
+
+	// Keep the package order stable, the test package last.
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	if options.FollowImports {
+		return followImports(path.Dir(filepath), result, options)
+	}
 	return result, nil
 }
 
@@ -413,6 +425,13 @@ func (p *objParser) parseStruct(goPath, name string, structInfo *model.TypeInfo,
 			JSONName: jsonName,
 		}
 
+		switch fieldType := field.Type.(type) {
+		case *ast.StarExpr:
+			fieldInfo.Pointer = true
+		case *ast.MapType:
+			fieldInfo.MapKey = getTypeDeclarationsForExpr(fieldType.Key)
+		}
+
 		isExported := ast.IsExported(fieldInfo.Name)
 		if !isExported && !options.IncludeUnexported {
 			continue
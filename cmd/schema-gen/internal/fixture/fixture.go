@@ -0,0 +1,30 @@
+// Package fixture writes go modules for tests.
+package fixture
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// GoMod is the go.mod written if files don't include one.
+const GoMod = "module example.com/app\n\ngo 1.22\n"
+
+// WriteModule writes files into a temporary folder and returns the
+// folder. The file names are relative, e.g. `api/api.go`.
+func WriteModule(t testing.TB, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if _, ok := files["go.mod"]; !ok {
+		files["go.mod"] = GoMod
+	}
+	for name, contents := range files {
+		filename := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o755))
+		require.NoError(t, os.WriteFile(filename, []byte(contents), 0o644))
+	}
+	return dir
+}
@@ -27,7 +27,11 @@ func ParseAndConvertStruct(cfg *options) error {
 		return err
 	}
 
-	pkgInfos, err := extract.Extract(absDir, &model.ExtractOptions{IncludeInternal: cfg.includeInternal})
+	pkgInfos, err := extract.Extract(absDir, &model.ExtractOptions{
+		IncludeInternal:     cfg.includeInternal,
+		FollowImports:       true,
+		IncludeDependencies: cfg.includeDeps,
+	})
 	if err != nil {
 		return err
 	}
@@ -35,7 +39,7 @@ func ParseAndConvertStruct(cfg *options) error {
 		return fmt.Errorf("no package info extracted from %q", absDir)
 	}
 
-	schema, err := ConvertToJSONSchema(pkgInfos[0], NewDefaultConfig(), cfg)
+	schema, err := ConvertToJSONSchema(pkgInfos, NewDefaultConfig(), cfg)
 
 	if err != nil {
 		return err
@@ -53,9 +57,21 @@ func ParseAndConvertStruct(cfg *options) error {
 }
 
 // ConvertToJSONSchema converts PackageInfo to JSON Schema with only the root type and its (internal and external) dependencies.
-func ConvertToJSONSchema(pkgInfo *model.PackageInfo, config *RequiredFieldsConfig, cfg *options) (*model.JSONSchema, error) {
+// The root type is looked up in the first package. The other packages hold the types imported from other packages,
+// as extracted with `FollowImports`, and are used to resolve external types before loading the package.
+func ConvertToJSONSchema(pkgInfos []*model.PackageInfo, config *RequiredFieldsConfig, cfg *options) (*model.JSONSchema, error) {
+	if len(pkgInfos) == 0 {
+		return nil, fmt.Errorf("no packages to convert")
+	}
+	pkgInfo := pkgInfos[0]
+	pkgIndex := newPackageIndex(pkgInfos)
+
+	schemaURL, err := schemaURL(cfg.draft)
+	if err != nil {
+		return nil, err
+	}
+
 	rootSchema := &model.JSONSchema{
-		Schema:      "http://json-schema.org/draft-07/schema#",
 		Definitions: make(map[string]*model.JSONSchema),
 	}
 	definitions := rootSchema.Definitions
@@ -63,7 +79,7 @@ func ConvertToJSONSchema(pkgInfo *model.PackageInfo, config *RequiredFieldsConfi
 	// We'll store discovered dependencies in this map
 	dependencies := make(map[string]bool)
 	// Build an alias mapping from the root package's imports
-	aliasMap := buildAliasMap(pkgInfo.Imports)
+	aliasMap := buildAliasMap(pkgInfo.Imports, pkgIndex)
 
 	// Find the root type and collect its dependencies
 	var rootTypeInfo *model.TypeInfo
@@ -101,19 +117,21 @@ func ConvertToJSONSchema(pkgInfo *model.PackageInfo, config *RequiredFieldsConfi
 	visited := make(map[string]bool)
 	for dep := range dependencies {
 		if strings.Contains(dep, ".") {
-			if err := ProcessExternalType(dep, aliasMap, definitions, visited, cfg); err != nil {
+			if err := ProcessExternalType(dep, aliasMap, pkgIndex, definitions, visited, cfg); err != nil {
 				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
 			}
 		}
 	}
 
 	rootSchema.Ref = "#/definitions/" + cfg.rootType
+	setDraft(rootSchema, schemaURL)
 	return rootSchema, nil
 }
 
 // ProcessExternalType loads an external package for a qualified type (e.g. "model.Inner"),
 // generates its JSON Schema definition, and then recursively processes its custom fields.
-func ProcessExternalType(qualifiedType string, aliasMap map[string]string, definitions map[string]*model.JSONSchema, visited map[string]bool, cfg *options) error {
+// Packages found in pkgIndex by import path are used instead of loading the package.
+func ProcessExternalType(qualifiedType string, aliasMap map[string]string, pkgIndex map[string]*model.PackageInfo, definitions map[string]*model.JSONSchema, visited map[string]bool, cfg *options) error {
 	if visited[qualifiedType] {
 		return nil
 	}
@@ -129,15 +147,18 @@ func ProcessExternalType(qualifiedType string, aliasMap map[string]string, defin
 	if !ok {
 		return fmt.Errorf("alias %q not found in alias map", pkgAlias)
 	}
-	absDir, err := normalizeSourcePath(cfg.sourcePath)
-	if err != nil {
-		return err
-	}
-	extPkgInfo, err := LoadExternalPackage(pkgPath, absDir, cfg.includeInternal)
-	if err != nil {
-		return fmt.Errorf("failed to load external package %q: %w", pkgPath, err)
+	extPkgInfo, ok := pkgIndex[pkgPath]
+	if !ok {
+		absDir, err := normalizeSourcePath(cfg.sourcePath)
+		if err != nil {
+			return err
+		}
+		extPkgInfo, err = LoadExternalPackage(pkgPath, absDir, cfg.includeInternal)
+		if err != nil {
+			return fmt.Errorf("failed to load external package %q: %w", pkgPath, err)
+		}
 	}
-	extAliasMap := buildAliasMap(extPkgInfo.Imports)
+	extAliasMap := buildAliasMap(extPkgInfo.Imports, pkgIndex)
 	extAliasMap[extPkgInfo.Name] = pkgPath
 	var extType *model.TypeInfo
 	for _, decl := range extPkgInfo.Declarations {
@@ -151,7 +172,7 @@ func ProcessExternalType(qualifiedType string, aliasMap map[string]string, defin
 							extAliasMap[pkgAlias] = value
 						}
 					}
-					if err := ProcessExternalType(depQualified, extAliasMap, definitions, visited, cfg); err != nil {
+					if err := ProcessExternalType(depQualified, extAliasMap, pkgIndex, definitions, visited, cfg); err != nil {
 						return err
 					}
 				}
@@ -175,7 +196,7 @@ func ProcessExternalType(qualifiedType string, aliasMap map[string]string, defin
 				}
 			}
 			depQualified := qualifyTypeName(baseType, pkgAlias)
-			if err := ProcessExternalType(depQualified, extAliasMap, definitions, visited, cfg); err != nil {
+			if err := ProcessExternalType(depQualified, extAliasMap, pkgIndex, definitions, visited, cfg); err != nil {
 				return err
 			}
 		}
@@ -318,12 +339,8 @@ func GenerateEnumSchema(typeInfo *model.TypeInfo) *model.JSONSchema {
 	for _, enum := range typeInfo.Enums {
 		enumValues = append(enumValues, enum.Value)
 	}
-	jsonType := "string"
-	if typeInfo.Type == "int" {
-		jsonType = "integer"
-	}
 	return &model.JSONSchema{
-		Type: jsonType,
+		Type: getBaseJSONType(typeInfo.Type),
 		Enum: enumValues,
 	}
 }
@@ -367,12 +384,10 @@ func GenerateStructSchema(typeInfo *model.TypeInfo, config *RequiredFieldsConfig
 		} else {
 			fieldSchema = getJSONType(field.Type)
 		}
-		if field.Doc != "" {
-			fieldSchema.Description = field.Doc
-		}
+		fieldRequired := ApplyFieldKeywords(fieldSchema, field)
 		cleanedJson := parseJSONTag(field.JSONName)
 		schema.Properties[cleanedJson] = fieldSchema
-		if requiredMap[field.Name] {
+		if requiredMap[field.Name] || fieldRequired {
 			required = append(required, cleanedJson)
 		}
 	}
@@ -465,6 +480,15 @@ func NewDefaultConfig() *RequiredFieldsConfig {
 }
 
 func generateTypeSchema(typ *model.TypeInfo, config *RequiredFieldsConfig, pkgName string, stripPrefix []string) *model.JSONSchema {
+	schema := generateTypeSchemaByKind(typ, config, pkgName, stripPrefix)
+	if schema != nil {
+		_, annotations := ParseDoc(typ.Doc)
+		_, schema.Deprecated = annotations["deprecated"]
+	}
+	return schema
+}
+
+func generateTypeSchemaByKind(typ *model.TypeInfo, config *RequiredFieldsConfig, pkgName string, stripPrefix []string) *model.JSONSchema {
 	switch {
 	case len(typ.Enums) > 0:
 		return GenerateEnumSchema(typ)
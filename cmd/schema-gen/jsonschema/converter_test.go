@@ -0,0 +1,181 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/exp/cmd/schema-gen/internal/fixture"
+	"github.com/titpetric/exp/cmd/schema-gen/model"
+)
+
+func TestParseAndConvertStruct_imports(t *testing.T) {
+	dir := fixture.WriteModule(t, map[string]string{
+		"api/api.go": `package api
+
+import "example.com/app/types"
+
+type Request struct {
+	ID   string     ` + "`json:\"id\"`" + `
+	Tags []tags.Tag ` + "`json:\"tags\"`" + `
+}
+`,
+		"types/types.go": `package tags
+
+type Tag struct {
+	Name string ` + "`json:\"name\"`" + `
+	Kind Kind   ` + "`json:\"kind\"`" + `
+}
+
+type Kind string
+
+const (
+	Label Kind = "label"
+	Topic Kind = "topic"
+)
+`,
+	})
+
+	cfg := &options{
+		sourcePath: filepath.Join(dir, "api"),
+		rootType:   "Request",
+		outputFile: filepath.Join(dir, "schema.json"),
+	}
+	require.NoError(t, ParseAndConvertStruct(cfg))
+
+	b, err := os.ReadFile(cfg.outputFile)
+	require.NoError(t, err)
+
+	schema := &model.JSONSchema{}
+	require.NoError(t, json.Unmarshal(b, schema))
+
+	assert.Equal(t, "#/definitions/TagsTag", schema.Definitions["Request"].Properties["tags"].Items.Ref)
+	assert.Equal(t, "#/definitions/TagsKind", schema.Definitions["TagsTag"].Properties["kind"].Ref)
+	assert.Equal(t, []any{"label", "topic"}, schema.Definitions["TagsKind"].Enum)
+
+	for _, ref := range collectRefs(schema) {
+		assert.Contains(t, schema.Definitions, strings.TrimPrefix(ref, "#/definitions/"))
+	}
+}
+
+func collectRefs(schema *model.JSONSchema) []string {
+	if schema == nil {
+		return nil
+	}
+
+	var result []string
+	if schema.Ref != "" {
+		result = append(result, schema.Ref)
+	}
+	for _, def := range schema.Definitions {
+		result = append(result, collectRefs(def)...)
+	}
+	for _, prop := range schema.Properties {
+		result = append(result, collectRefs(prop)...)
+	}
+	return append(result, collectRefs(schema.Items)...)
+}
+
+func TestParseAndConvertStruct_validation(t *testing.T) {
+	dir := fixture.WriteModule(t, map[string]string{
+		"api.go": `package api
+
+type Level int
+
+const (
+	Low Level = iota
+	High
+)
+
+// Request is a request.
+type Request struct {
+	// Name of the request.
+	// +pattern=^[a-z]+$
+	Name string ` + "`json:\"name\" validate:\"required,min=1,max=32\"`" + `
+
+	Count int ` + "`json:\"count\" validate:\"gt=0,lte=100\" default:\"10\"`" + `
+
+	Emails []string ` + "`json:\"emails\" validate:\"min=1,dive,email\"`" + `
+
+	Mode string ` + "`json:\"mode\" validate:\"oneof=fast slow\" default:\"fast\"`" + `
+
+	Level Level ` + "`json:\"level\" default:\"1\"`" + `
+
+	// Deprecated: use Name.
+	Title string ` + "`json:\"title,omitempty\" format:\"hostname\"`" + `
+}
+`,
+	})
+
+	convert := func(draft string) *model.JSONSchema {
+		cfg := &options{
+			sourcePath: dir,
+			rootType:   "Request",
+			outputFile: filepath.Join(dir, "schema.json"),
+			draft:      draft,
+		}
+		require.NoError(t, ParseAndConvertStruct(cfg))
+
+		b, err := os.ReadFile(cfg.outputFile)
+		require.NoError(t, err)
+
+		schema := &model.JSONSchema{}
+		require.NoError(t, json.Unmarshal(b, schema))
+		return schema
+	}
+
+	schema := convert("2020-12")
+	assert.Equal(t, Draft202012, schema.Schema)
+	assert.Equal(t, "#/$defs/Request", schema.Ref)
+	assert.Empty(t, schema.Definitions)
+
+	request := schema.Defs["Request"]
+	require.NotNil(t, request)
+	assert.Equal(t, []string{"name"}, request.Required)
+
+	name := request.Properties["name"]
+	assert.Equal(t, "Name of the request.", name.Description)
+	assert.Equal(t, "^[a-z]+$", name.Pattern)
+	assert.Equal(t, 1, *name.MinLength)
+	assert.Equal(t, 32, *name.MaxLength)
+
+	count := request.Properties["count"]
+	assert.Equal(t, 0.0, *count.ExclusiveMinimum)
+	assert.Equal(t, 100.0, *count.Maximum)
+	assert.Equal(t, 10.0, count.Default)
+
+	emails := request.Properties["emails"]
+	assert.Equal(t, 1, *emails.MinItems)
+	assert.Equal(t, "email", emails.Items.Format)
+
+	mode := request.Properties["mode"]
+	assert.Equal(t, []any{"fast", "slow"}, mode.Enum)
+	assert.Equal(t, "fast", mode.Default)
+
+	level := request.Properties["level"]
+	assert.Equal(t, "#/$defs/Level", level.Ref)
+	assert.Equal(t, 1.0, level.Default)
+	assert.Equal(t, "integer", schema.Defs["Level"].Type)
+	assert.Equal(t, []any{0.0, 1.0}, schema.Defs["Level"].Enum)
+
+	title := request.Properties["title"]
+	assert.True(t, title.Deprecated)
+	assert.Equal(t, "hostname", title.Format)
+
+	schema = convert("07")
+	assert.Equal(t, Draft07, schema.Schema)
+	assert.Equal(t, "#/definitions/Request", schema.Ref)
+
+	level = schema.Definitions["Request"].Properties["level"]
+	assert.Empty(t, level.Ref)
+	require.Len(t, level.AllOf, 1)
+	assert.Equal(t, "#/definitions/Level", level.AllOf[0].Ref)
+
+	_, err := schemaURL("2019-09")
+	assert.Error(t, err)
+}
@@ -12,6 +12,8 @@ type options struct {
 	outputFile      string
 	stripPrefix     []string
 	includeInternal bool
+	includeDeps     bool
+	draft           string
 }
 
 func NewOptions() *options {
@@ -19,6 +21,7 @@ func NewOptions() *options {
 		sourcePath:  ".",
 		outputFile:  "schema.json",
 		stripPrefix: []string{},
+		draft:       "07",
 	}
 
 	pflag.StringVarP(&cfg.sourcePath, "dir", "i", cfg.sourcePath, "Path to the directory that contains the root type (required)")
@@ -26,6 +29,8 @@ func NewOptions() *options {
 	pflag.StringVarP(&cfg.outputFile, "out", "o", cfg.outputFile, "Output file name (optional)")
 	pflag.StringSliceVarP(&cfg.stripPrefix, "strip-prefix", "s", cfg.stripPrefix, "List of package prefixes to strip from definition names (optional)")
 	pflag.BoolVarP(&cfg.includeInternal, "include-internal", "n", cfg.includeInternal, "include internal packages")
+	pflag.BoolVar(&cfg.includeDeps, "include-deps", cfg.includeDeps, "resolve imported types from dependencies outside the module")
+	pflag.StringVar(&cfg.draft, "draft", cfg.draft, "JSON Schema draft, 07 or 2020-12")
 	pflag.Parse()
 
 	return cfg
@@ -213,7 +213,9 @@ func isCustomType(typeName string) bool {
 	return true
 }
 
-func buildAliasMap(imports []string) map[string]string {
+// buildAliasMap maps package names to import paths. Packages without an
+// alias use the package name from pkgIndex, or the last path segment.
+func buildAliasMap(imports []string, pkgIndex map[string]*model.PackageInfo) map[string]string {
 	aliasMap := make(map[string]string)
 	for _, imp := range imports {
 		parts := strings.Split(imp, " ")
@@ -222,8 +224,12 @@ func buildAliasMap(imports []string) map[string]string {
 			path := strings.Trim(parts[1], "\"")
 			aliasMap[alias] = path
 		} else {
-			// No alias; deduce one from the import path
 			impPath := strings.Trim(imp, "\"")
+			if pkgInfo, ok := pkgIndex[impPath]; ok {
+				aliasMap[pkgInfo.Name] = impPath
+				continue
+			}
+			// No alias; deduce one from the import path
 			segs := strings.Split(impPath, "/")
 			aliasMap[segs[len(segs)-1]] = impPath
 		}
@@ -231,6 +237,17 @@ func buildAliasMap(imports []string) map[string]string {
 	return aliasMap
 }
 
+// newPackageIndex maps the import paths to the packages.
+func newPackageIndex(pkgInfos []*model.PackageInfo) map[string]*model.PackageInfo {
+	result := make(map[string]*model.PackageInfo)
+	for _, pkgInfo := range pkgInfos {
+		if pkgInfo.ImportPath != "" {
+			result[pkgInfo.ImportPath] = pkgInfo
+		}
+	}
+	return result
+}
+
 func ToPtr[T any](v T) *T {
 	return &v
 }
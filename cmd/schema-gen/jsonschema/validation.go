@@ -0,0 +1,279 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/titpetric/exp/cmd/schema-gen/model"
+)
+
+// Supported JSON Schema drafts.
+const (
+	Draft07     = "http://json-schema.org/draft-07/schema#"
+	Draft202012 = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// schemaURL returns the $schema URL for a `--draft` value.
+func schemaURL(draft string) (string, error) {
+	switch strings.TrimPrefix(draft, "draft-") {
+	case "", "7", "07":
+		return Draft07, nil
+	case "2020-12":
+		return Draft202012, nil
+	}
+	return "", fmt.Errorf("unsupported draft %q, use 07 or 2020-12", draft)
+}
+
+// validateFormats maps validator rules to the format keyword.
+var validateFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"hostname": "hostname",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+}
+
+// validatePatterns maps validator rules to the pattern keyword.
+var validatePatterns = map[string]string{
+	"alpha":    "^[a-zA-Z]+$",
+	"alphanum": "^[a-zA-Z0-9]+$",
+	"numeric":  "^[-+]?[0-9]+(\\.[0-9]+)?$",
+}
+
+// ParseDoc splits annotations from a doc comment. Annotations are lines
+// in the form of `+key=value` or `+key`. A `Deprecated:` paragraph, as
+// per go conventions, sets the deprecated annotation.
+func ParseDoc(doc string) (string, map[string]string) {
+	annotations := map[string]string{}
+
+	var lines []string
+	for _, line := range strings.Split(doc, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if key, ok := strings.CutPrefix(trimmed, "+"); ok && key != "" {
+			key, value, _ := strings.Cut(key, "=")
+			annotations[key] = value
+			continue
+		}
+		if strings.HasPrefix(trimmed, "Deprecated:") {
+			annotations["deprecated"] = ""
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), annotations
+}
+
+// ApplyFieldKeywords sets the description and the validation keywords
+// of a field schema from the field doc and tags. It returns true if the
+// field is required by a `validate:"required"` rule.
+//
+// The `default`, `format` and `pattern` keywords are read from tags of
+// the same name, or `+default=` style doc annotations. Rules from the
+// `validate` (or `binding`) tag are mapped to the matching keywords,
+// rules after `dive` apply to the array items.
+func ApplyFieldKeywords(schema *model.JSONSchema, field *model.FieldInfo) bool {
+	description, annotations := ParseDoc(field.Doc)
+	if description != "" {
+		schema.Description = description
+	}
+
+	tag := reflect.StructTag(field.Tag)
+	for _, key := range []string{"default", "format", "pattern"} {
+		if value, ok := tag.Lookup(key); ok {
+			annotations[key] = value
+		}
+	}
+
+	if value, ok := annotations["default"]; ok {
+		schema.Default = typedValue(schema.Type, value)
+	}
+	if value := annotations["format"]; value != "" {
+		schema.Format = value
+	}
+	if value := annotations["pattern"]; value != "" {
+		schema.Pattern = value
+	}
+	if _, ok := annotations["deprecated"]; ok {
+		schema.Deprecated = true
+	}
+
+	rules, ok := tag.Lookup("validate")
+	if !ok {
+		rules = tag.Get("binding")
+	}
+	return applyValidateRules(schema, rules)
+}
+
+func applyValidateRules(schema *model.JSONSchema, rules string) (required bool) {
+	target := schema
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		switch name {
+		case "dive":
+			if target.Items == nil {
+				return
+			}
+			target = target.Items
+		case "required":
+			required = required || target == schema
+		case "min", "max", "len":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			setSize(target, name, n)
+		case "gt", "gte", "lt", "lte":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil || !isNumeric(target.Type) {
+				continue
+			}
+			switch name {
+			case "gt":
+				target.ExclusiveMinimum = ToPtr(n)
+			case "gte":
+				target.Minimum = ToPtr(n)
+			case "lt":
+				target.ExclusiveMaximum = ToPtr(n)
+			case "lte":
+				target.Maximum = ToPtr(n)
+			}
+		case "oneof":
+			target.Enum = nil
+			for _, value := range strings.Fields(param) {
+				target.Enum = append(target.Enum, typedValue(target.Type, strings.Trim(value, "'")))
+			}
+		default:
+			if format, ok := validateFormats[name]; ok {
+				target.Format = format
+			}
+			if pattern, ok := validatePatterns[name]; ok {
+				target.Pattern = pattern
+			}
+		}
+	}
+	return
+}
+
+// setSize maps min, max and len to the keywords for the schema type.
+func setSize(schema *model.JSONSchema, rule string, n float64) {
+	length := int(n)
+	switch {
+	case schema.Type == "string":
+		if rule != "max" {
+			schema.MinLength = ToPtr(length)
+		}
+		if rule != "min" {
+			schema.MaxLength = ToPtr(length)
+		}
+	case schema.Type == "array":
+		if rule != "max" {
+			schema.MinItems = ToPtr(length)
+		}
+		if rule != "min" {
+			schema.MaxItems = ToPtr(length)
+		}
+	case isNumeric(schema.Type):
+		if rule != "max" {
+			schema.Minimum = ToPtr(n)
+		}
+		if rule != "min" {
+			schema.Maximum = ToPtr(n)
+		}
+	}
+}
+
+func isNumeric(jsonType string) bool {
+	return jsonType == "integer" || jsonType == "number"
+}
+
+// typedValue converts a value from a tag to the schema type. Values
+// for a schema without a type, e.g. a $ref, are decoded as json.
+func typedValue(jsonType, value string) any {
+	switch jsonType {
+	case "string":
+		return value
+	case "integer":
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	case "":
+		var v any
+		if err := json.Unmarshal([]byte(value), &v); err == nil {
+			return v
+		}
+	}
+	return value
+}
+
+// hasKeywords returns true if the schema has keywords next to $ref.
+func hasKeywords(schema *model.JSONSchema) bool {
+	ref := *schema
+	ref.Ref, ref.Description = "", ""
+	return !reflect.DeepEqual(ref, model.JSONSchema{})
+}
+
+// setDraft sets the $schema of the root schema. Definitions are moved
+// to $defs for 2020-12. For draft-07, where keywords next to $ref are
+// ignored, a $ref with keywords is moved into allOf.
+func setDraft(root *model.JSONSchema, url string) {
+	root.Schema = url
+
+	var (
+		walk   func(schema *model.JSONSchema)
+		visit  = map[*model.JSONSchema]bool{}
+		prefix = "#/definitions/"
+	)
+	walk = func(schema *model.JSONSchema) {
+		if schema == nil || visit[schema] {
+			return
+		}
+		visit[schema] = true
+
+		if schema.Ref != "" && schema != root {
+			switch {
+			case url == Draft202012:
+				schema.Ref = "#/$defs/" + strings.TrimPrefix(schema.Ref, prefix)
+			case hasKeywords(schema):
+				schema.AllOf = append([]*model.JSONSchema{{Ref: schema.Ref}}, schema.AllOf...)
+				schema.Ref = ""
+			}
+		}
+
+		for _, def := range schema.Definitions {
+			walk(def)
+		}
+		for _, prop := range schema.Properties {
+			walk(prop)
+		}
+		for _, s := range schema.AllOf {
+			walk(s)
+		}
+		for _, s := range schema.AnyOf {
+			walk(s)
+		}
+		walk(schema.Items)
+		if additional, ok := schema.AdditionalProperties.(*model.JSONSchema); ok {
+			walk(additional)
+		}
+	}
+	walk(root)
+
+	if url == Draft202012 {
+		root.Ref = "#/$defs/" + strings.TrimPrefix(root.Ref, prefix)
+		root.Defs, root.Definitions = root.Definitions, nil
+	}
+}
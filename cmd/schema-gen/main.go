@@ -16,6 +16,7 @@ import (
 	"github.com/titpetric/exp/cmd/schema-gen/lint"
 	"github.com/titpetric/exp/cmd/schema-gen/list"
 	"github.com/titpetric/exp/cmd/schema-gen/markdown"
+	"github.com/titpetric/exp/cmd/schema-gen/openapi"
 	"github.com/titpetric/exp/cmd/schema-gen/restore"
 )
 
@@ -34,6 +35,7 @@ func start() (err error) {
 		"lint":       lint.Run,
 		"list":       list.Run,
 		"jsonschema": jsonschema.Run,
+		"openapi":    openapi.Run,
 	}
 	commandList := maps.Keys(commands)
 	sort.Strings(commandList)
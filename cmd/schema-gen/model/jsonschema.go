@@ -1,6 +1,6 @@
 package model
 
-// JSONSchema represents a JSON Schema document according to the draft-07 specification.
+// JSONSchema represents a JSON Schema document according to the draft-07 or 2020-12 specification.
 // It includes standard fields used to define types, formats, validations.
 type JSONSchema struct {
 	// Schema specifies the JSON Schema version URL.
@@ -11,6 +11,14 @@ type JSONSchema struct {
 	Ref string `json:"$ref,omitempty"`
 	// Definitions contains subSchema definitions that can be referenced by $ref.
 	Definitions map[string]*JSONSchema `json:"definitions,omitempty"`
+	// Defs contains subSchema definitions for 2020-12, referenced as "#/$defs/SomeType".
+	Defs map[string]*JSONSchema `json:"$defs,omitempty"`
+	// AllOf requires the instance to be valid against all of the schemas.
+	// It's used to add keywords next to a $ref in draft-07, where siblings of $ref are ignored.
+	AllOf []*JSONSchema `json:"allOf,omitempty"`
+	// AnyOf requires the instance to be valid against any of the schemas.
+	// It's used for nullable values as `anyOf: [schema, {type: "null"}]`.
+	AnyOf []*JSONSchema `json:"anyOf,omitempty"`
 	// Type indicates the JSON type of the instance (e.g., "object", "array", "string").
 	Type string `json:"type,omitempty"`
 	// Format provides additional semantic validation for the instance.
@@ -28,17 +36,31 @@ type JSONSchema struct {
 	Required []string `json:"required,omitempty"`
 	// Description provides a human-readable explanation of the schema.
 	Description string `json:"description,omitempty"`
+	// Default is the default value of the instance.
+	Default any `json:"default,omitempty"`
+	// Deprecated marks the instance as deprecated (2019-09 and later).
+	Deprecated bool `json:"deprecated,omitempty"`
+	// MinLength specifies the minimum length of a string.
+	MinLength *int `json:"minLength,omitempty"`
+	// MaxLength specifies the maximum length of a string.
+	MaxLength *int `json:"maxLength,omitempty"`
+	// MinItems specifies the minimum number of array elements.
+	MinItems *int `json:"minItems,omitempty"`
+	// MaxItems specifies the maximum number of array elements.
+	MaxItems *int `json:"maxItems,omitempty"`
 	// Minimum specifies the minimum numeric value allowed.
 	Minimum *float64 `json:"minimum,omitempty"`
 	// Maximum specifies the maximum numeric value allowed.
 	Maximum *float64 `json:"maximum,omitempty"`
-	// ExclusiveMinimum, if true, requires the instance to be greater than (not equal to) Minimum.
-	ExclusiveMinimum *bool `json:"exclusiveMinimum,omitempty"`
-	// ExclusiveMaximum, if true, requires the instance to be less than (not equal to) Maximum.
-	ExclusiveMaximum *bool `json:"exclusiveMaximum,omitempty"`
+	// ExclusiveMinimum requires the instance to be greater than (not equal to) the value.
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	// ExclusiveMaximum requires the instance to be less than (not equal to) the value.
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
 	// MultipleOf indicates that the numeric instance must be a multiple of this value.
 	MultipleOf *float64 `json:"multipleOf,omitempty"`
 	// AdditionalProperties controls whether an object can have properties beyond those defined
 	// Can be a boolean or a schema that additional properties must conform to
 	AdditionalProperties any `json:"additionalProperties,omitempty"`
+	// PropertyNames is the schema property names must conform to, e.g. for integer map keys.
+	PropertyNames *JSONSchema `json:"propertyNames,omitempty"`
 }
@@ -14,6 +14,10 @@ type PackageInfo struct {
 	// Name is the package name.
 	Name string `json:"name"`
 
+	// ImportPath is the package import path, if known. It's set for
+	// packages followed from imported types, `--follow-imports`.
+	ImportPath string `json:"import_path,omitempty"`
+
 	// Imports holds a list of imported packages.
 	Imports []string `json:"imports"`
 
@@ -201,6 +205,10 @@ type FieldInfo struct {
 
 	// MapKey is the map key type, if this field is a map.
 	MapKey string `json:"map_key,omitempty"`
+
+	// Pointer is true if the field is a pointer, e.g. `*string`.
+	// The pointer is not part of Type.
+	Pointer bool `json:"pointer,omitempty"`
 }
 
 func (f *FieldInfo) TypeRef() string {
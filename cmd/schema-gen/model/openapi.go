@@ -0,0 +1,23 @@
+package model
+
+// OpenAPI is an OpenAPI 3.1 document with component schemas.
+type OpenAPI struct {
+	// OpenAPI is the OpenAPI version, e.g. "3.1.0".
+	OpenAPI string `json:"openapi"`
+	// Info holds the document title and version.
+	Info OpenAPIInfo `json:"info"`
+	// Components holds the reusable schemas.
+	Components OpenAPIComponents `json:"components"`
+}
+
+// OpenAPIInfo holds the document metadata.
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPIComponents holds the reusable objects of a document.
+type OpenAPIComponents struct {
+	// Schemas are referenced as "#/components/schemas/SomeType".
+	Schemas map[string]*JSONSchema `json:"schemas"`
+}
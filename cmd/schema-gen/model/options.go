@@ -7,4 +7,11 @@ type ExtractOptions struct {
 	IncludeUnexported bool
 	IgnoreFiles       []string
 	IncludeInternal   bool
+
+	// FollowImports extracts types referenced from other packages
+	// in the module as separate package entries.
+	FollowImports bool
+	// IncludeDependencies follows imported types into packages
+	// outside of the module, standard library excluded.
+	IncludeDependencies bool
 }
@@ -0,0 +1,264 @@
+package openapi
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/titpetric/exp/cmd/schema-gen/jsonschema"
+	"github.com/titpetric/exp/cmd/schema-gen/model"
+)
+
+const refPrefix = "#/components/schemas/"
+
+// generator builds component schemas for a type and its dependencies.
+// OpenAPI 3.1 schemas are JSON Schema 2020-12.
+type generator struct {
+	// pkgIndex holds the packages by import path.
+	pkgIndex map[string]*model.PackageInfo
+	// root is the package holding the root types.
+	root *model.PackageInfo
+
+	stripPrefix []string
+
+	schemas map[string]*model.JSONSchema
+}
+
+func newGenerator(pkgInfos []*model.PackageInfo, stripPrefix []string) *generator {
+	g := &generator{
+		pkgIndex:    map[string]*model.PackageInfo{},
+		root:        pkgInfos[0],
+		stripPrefix: stripPrefix,
+		schemas:     map[string]*model.JSONSchema{},
+	}
+	for _, pkgInfo := range pkgInfos {
+		if pkgInfo.ImportPath != "" {
+			g.pkgIndex[pkgInfo.ImportPath] = pkgInfo
+		}
+	}
+	return g
+}
+
+// Components returns the component schemas for the root types in the
+// first package, and all the types they reference.
+func Components(pkgInfos []*model.PackageInfo, rootTypes []string, stripPrefix []string) (map[string]*model.JSONSchema, error) {
+	if len(pkgInfos) == 0 {
+		return nil, fmt.Errorf("no packages to convert")
+	}
+
+	g := newGenerator(pkgInfos, stripPrefix)
+	for _, name := range rootTypes {
+		if g.root.Declarations.TypeInfo(name) == nil {
+			return nil, fmt.Errorf("root type %q not found in package", name)
+		}
+		g.ref(g.root, name)
+	}
+	return g.schemas, nil
+}
+
+// ref returns a $ref to a type declared in pkg, adding the component
+// schema for the type. Types which can't be resolved are an empty
+// schema, allowing any value.
+func (g *generator) ref(pkg *model.PackageInfo, typeName string) *model.JSONSchema {
+	if alias, name, ok := strings.Cut(typeName, "."); ok {
+		target, found := g.pkgIndex[g.aliases(pkg)[alias]]
+		if !found {
+			return &model.JSONSchema{}
+		}
+		pkg, typeName = target, name
+	}
+
+	typ := pkg.Declarations.TypeInfo(typeName)
+	if typ == nil {
+		return &model.JSONSchema{}
+	}
+
+	name := g.schemaName(pkg, typeName)
+	if _, ok := g.schemas[name]; !ok {
+		// Set first, so recursive types can reference it.
+		g.schemas[name] = &model.JSONSchema{}
+		*g.schemas[name] = *g.typeSchema(pkg, typ)
+	}
+	return &model.JSONSchema{Ref: refPrefix + name}
+}
+
+// schemaName returns the component name, types from other packages
+// are prefixed with the package name, e.g. `ModelUser`.
+func (g *generator) schemaName(pkg *model.PackageInfo, typeName string) string {
+	if pkg == g.root || slices.Contains(g.stripPrefix, pkg.Name) {
+		return typeName
+	}
+	return jsonschema.Title(pkg.Name) + typeName
+}
+
+// aliases maps the package names used in pkg to import paths.
+func (g *generator) aliases(pkg *model.PackageInfo) map[string]string {
+	result := map[string]string{}
+	for _, imported := range pkg.Imports {
+		alias, importPath, aliased := strings.Cut(imported, " ")
+		if !aliased {
+			importPath, alias = alias, ""
+		}
+		importPath = strings.Trim(importPath, `"`)
+
+		if alias == "" {
+			alias = path.Base(importPath)
+			if target, ok := g.pkgIndex[importPath]; ok {
+				alias = target.Name
+			}
+		}
+		if alias == "_" || alias == "." {
+			continue
+		}
+		result[alias] = importPath
+	}
+	return result
+}
+
+// typeSchema returns the schema of a type declaration.
+func (g *generator) typeSchema(pkg *model.PackageInfo, typ *model.TypeInfo) *model.JSONSchema {
+	var schema *model.JSONSchema
+	switch {
+	case len(typ.Enums) > 0:
+		schema = primitiveSchema(typ.Type)
+		for _, enum := range typ.Enums {
+			schema.Enum = append(schema.Enum, enum.Value)
+		}
+	case typ.StructObj != nil || len(typ.Fields) > 0:
+		schema = g.structSchema(pkg, typ)
+	default:
+		schema = g.goTypeSchema(pkg, typ.Type, "")
+	}
+
+	description, annotations := jsonschema.ParseDoc(typ.Doc)
+	if description != "" && schema.Ref == "" {
+		schema.Description = description
+	}
+	if _, ok := annotations["deprecated"]; ok {
+		schema.Deprecated = true
+	}
+	return schema
+}
+
+// structSchema returns an object schema for a struct. Pointer fields
+// are nullable.
+func (g *generator) structSchema(pkg *model.PackageInfo, typ *model.TypeInfo) *model.JSONSchema {
+	schema := &model.JSONSchema{
+		Type:       "object",
+		Properties: map[string]*model.JSONSchema{},
+	}
+
+	for _, field := range typ.Fields {
+		name, _, _ := strings.Cut(field.JSONName, ",")
+		if name == "" {
+			continue
+		}
+
+		property := g.goTypeSchema(pkg, field.Type, field.MapKey)
+		if jsonschema.ApplyFieldKeywords(property, field) {
+			schema.Required = append(schema.Required, name)
+		}
+		if field.Pointer {
+			property = nullable(property)
+		}
+		schema.Properties[name] = property
+	}
+	return schema
+}
+
+// goTypeSchema returns the schema for a go type, as written in a
+// field or type declaration. The mapKey is the key type of maps.
+func (g *generator) goTypeSchema(pkg *model.PackageInfo, goType string, mapKey string) *model.JSONSchema {
+	goType = strings.TrimPrefix(goType, "*")
+
+	switch {
+	case goType == "[]byte":
+		return &model.JSONSchema{Type: "string", Format: "byte"}
+	case strings.HasPrefix(goType, "[]"):
+		return &model.JSONSchema{
+			Type:  "array",
+			Items: g.goTypeSchema(pkg, strings.TrimPrefix(goType, "[]"), ""),
+		}
+	case strings.HasPrefix(goType, "map["):
+		key, value, _ := strings.Cut(strings.TrimPrefix(goType, "map["), "]")
+		if mapKey == "" {
+			mapKey = key
+		}
+		schema := &model.JSONSchema{
+			Type:                 "object",
+			AdditionalProperties: g.goTypeSchema(pkg, value, ""),
+		}
+		if keys := primitiveSchema(mapKey); keys.Type == "integer" {
+			schema.PropertyNames = &model.JSONSchema{Pattern: "^-?[0-9]+$"}
+		}
+		return schema
+	case goType == "" || goType == "any" || goType == "interface{}" || goType == "func":
+		return &model.JSONSchema{}
+	case isPrimitive(goType):
+		return primitiveSchema(goType)
+	}
+	return g.ref(pkg, goType)
+}
+
+// nullable allows null for a schema. A $ref is wrapped in anyOf, and
+// the keywords next to it stay on the outer schema.
+func nullable(schema *model.JSONSchema) *model.JSONSchema {
+	if schema.Ref == "" && schema.Type != "" {
+		inner := *schema
+		inner.Description, inner.Deprecated, inner.Default = "", false, nil
+		return &model.JSONSchema{
+			Description: schema.Description,
+			Deprecated:  schema.Deprecated,
+			Default:     schema.Default,
+			AnyOf:       []*model.JSONSchema{&inner, {Type: "null"}},
+		}
+	}
+	if schema.Ref == "" {
+		// Empty schemas already allow null.
+		return schema
+	}
+
+	result := *schema
+	result.Ref = ""
+	result.AnyOf = []*model.JSONSchema{{Ref: schema.Ref}, {Type: "null"}}
+	return &result
+}
+
+// isPrimitive returns true for builtin types and the well known types
+// from the standard library.
+func isPrimitive(goType string) bool {
+	switch goType {
+	case "string", "bool", "error",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"byte", "rune", "uintptr", "float32", "float64",
+		"time.Time", "time.Duration", "json.RawMessage":
+		return true
+	}
+	return false
+}
+
+// primitiveSchema returns the schema for a builtin type, with the
+// OpenAPI formats for sized numbers.
+func primitiveSchema(goType string) *model.JSONSchema {
+	switch goType {
+	case "int32", "rune", "uint32":
+		return &model.JSONSchema{Type: "integer", Format: "int32"}
+	case "int64", "uint64":
+		return &model.JSONSchema{Type: "integer", Format: "int64"}
+	case "int", "int8", "int16", "uint", "uint8", "uint16", "byte", "uintptr":
+		return &model.JSONSchema{Type: "integer"}
+	case "float32":
+		return &model.JSONSchema{Type: "number", Format: "float"}
+	case "float64":
+		return &model.JSONSchema{Type: "number", Format: "double"}
+	case "bool":
+		return &model.JSONSchema{Type: "boolean"}
+	case "time.Time":
+		return &model.JSONSchema{Type: "string", Format: "date-time"}
+	case "json.RawMessage":
+		return &model.JSONSchema{}
+	}
+	return &model.JSONSchema{Type: "string"}
+}
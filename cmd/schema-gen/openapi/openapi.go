@@ -0,0 +1,130 @@
+// Package openapi generates OpenAPI 3.1 component schemas.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/titpetric/exp/cmd/schema-gen/extract"
+	"github.com/titpetric/exp/cmd/schema-gen/model"
+)
+
+// Version is the OpenAPI version of the generated document.
+const Version = "3.1.0"
+
+// Generate writes an OpenAPI document with the component schemas
+// for the root types and their dependencies.
+func Generate(cfg *options) error {
+	if len(cfg.rootTypes) == 0 {
+		return fmt.Errorf("missing root types, use -t")
+	}
+
+	absDir, err := filepath.Abs(cfg.sourcePath)
+	if err != nil {
+		return err
+	}
+
+	pkgInfos, err := extract.Extract(absDir+"/", &model.ExtractOptions{
+		IncludeInternal:     cfg.includeInternal,
+		FollowImports:       true,
+		IncludeDependencies: cfg.includeDeps,
+	})
+	if err != nil {
+		return err
+	}
+	if len(pkgInfos) == 0 {
+		return fmt.Errorf("no package info extracted from %q", absDir)
+	}
+
+	schemas, err := Components(pkgInfos, cfg.rootTypes, cfg.stripPrefix)
+	if err != nil {
+		return err
+	}
+
+	title := cfg.title
+	if title == "" {
+		title = pkgInfos[0].Name
+	}
+
+	doc := &model.OpenAPI{
+		OpenAPI: Version,
+		Info: model.OpenAPIInfo{
+			Title:   title,
+			Version: cfg.version,
+		},
+		Components: model.OpenAPIComponents{
+			Schemas: schemas,
+		},
+	}
+
+	out, err := Marshal(doc, format(cfg))
+	if err != nil {
+		return err
+	}
+
+	if cfg.outputFile == "-" {
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+	return os.WriteFile(cfg.outputFile, out, 0o644)
+}
+
+// format returns the output format, by default from the output file extension.
+func format(cfg *options) string {
+	if cfg.format != "" {
+		return cfg.format
+	}
+	if strings.EqualFold(filepath.Ext(cfg.outputFile), ".json") {
+		return "json"
+	}
+	return "yaml"
+}
+
+// Marshal encodes the document as json or yaml. The yaml keys are
+// written in the same order as the json keys.
+func Marshal(doc *model.OpenAPI, format string) ([]byte, error) {
+	jsonBytes, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case "json":
+		return append(jsonBytes, '\n'), nil
+	case "yaml", "yml":
+	default:
+		return nil, fmt.Errorf("unknown format %q, use yaml or json", format)
+	}
+
+	// Decoding into a node keeps the key order, and the json
+	// quoting style is reset so yaml picks the plain style.
+	var node yaml.Node
+	if err := yaml.Unmarshal(jsonBytes, &node); err != nil {
+		return nil, err
+	}
+	resetStyle(&node)
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
@@ -0,0 +1,161 @@
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/titpetric/exp/cmd/schema-gen/internal/fixture"
+	"github.com/titpetric/exp/cmd/schema-gen/model"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := fixture.WriteModule(t, map[string]string{
+		"api/api.go": `package api
+
+import "example.com/app/types"
+
+// Request is an API request.
+type Request struct {
+	// ID is the request ID.
+	ID string ` + "`json:\"id\" validate:\"required\"`" + `
+	// Note is an optional note.
+	Note *string ` + "`json:\"note,omitempty\"`" + `
+	// Tag is an optional tag.
+	Tag *tags.Tag ` + "`json:\"tag,omitempty\"`" + `
+	// Counts are keyed by shard.
+	Counts map[int]int64 ` + "`json:\"counts\"`" + `
+	// Labels are free form.
+	Labels map[string]tags.Kind ` + "`json:\"labels\"`" + `
+	Data []byte ` + "`json:\"data\"`" + `
+	Skip string ` + "`json:\"-\"`" + `
+}
+`,
+		"types/types.go": `package tags
+
+type Tag struct {
+	Name string ` + "`json:\"name\"`" + `
+	Kind Kind   ` + "`json:\"kind\"`" + `
+}
+
+// Kind is a tag kind.
+//
+// Deprecated: use labels.
+type Kind string
+
+const (
+	Label Kind = "label"
+	Topic Kind = "topic"
+)
+`,
+	})
+
+	cfg := &options{
+		sourcePath: filepath.Join(dir, "api"),
+		rootTypes:  []string{"Request"},
+		outputFile: filepath.Join(dir, "openapi.yaml"),
+		version:    "1.0.0",
+	}
+	require.NoError(t, Generate(cfg))
+
+	b, err := os.ReadFile(cfg.outputFile)
+	require.NoError(t, err)
+
+	doc := &model.OpenAPI{}
+	require.NoError(t, decodeYAML(b, doc))
+
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Equal(t, model.OpenAPIInfo{Title: "api", Version: "1.0.0"}, doc.Info)
+
+	schemas := doc.Components.Schemas
+	assert.ElementsMatch(t, []string{"Request", "TagsTag", "TagsKind"}, keys(schemas))
+
+	request := schemas["Request"]
+	assert.Equal(t, "Request is an API request.", request.Description)
+	assert.Equal(t, []string{"id"}, request.Required)
+	assert.NotContains(t, request.Properties, "Skip")
+
+	id := request.Properties["id"]
+	assert.Equal(t, "string", id.Type)
+	assert.Equal(t, "ID is the request ID.", id.Description)
+
+	note := request.Properties["note"]
+	assert.Equal(t, "Note is an optional note.", note.Description)
+	assert.Equal(t, []*model.JSONSchema{{Type: "string"}, {Type: "null"}}, note.AnyOf)
+
+	tag := request.Properties["tag"]
+	assert.Empty(t, tag.Ref)
+	assert.Equal(t, []*model.JSONSchema{{Ref: "#/components/schemas/TagsTag"}, {Type: "null"}}, tag.AnyOf)
+
+	counts := request.Properties["counts"]
+	assert.Equal(t, "object", counts.Type)
+	assert.Equal(t, "^-?[0-9]+$", counts.PropertyNames.Pattern)
+	assert.Equal(t, map[string]any{"type": "integer", "format": "int64"}, counts.AdditionalProperties)
+
+	labels := request.Properties["labels"]
+	assert.Nil(t, labels.PropertyNames)
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/TagsKind"}, labels.AdditionalProperties)
+
+	assert.Equal(t, &model.JSONSchema{Type: "string", Format: "byte"}, request.Properties["data"])
+
+	kind := schemas["TagsKind"]
+	assert.Equal(t, "string", kind.Type)
+	assert.Equal(t, []any{"label", "topic"}, kind.Enum)
+	assert.True(t, kind.Deprecated)
+}
+
+func TestMarshal(t *testing.T) {
+	doc := &model.OpenAPI{
+		OpenAPI: Version,
+		Info:    model.OpenAPIInfo{Title: "api", Version: "1"},
+		Components: model.OpenAPIComponents{
+			Schemas: map[string]*model.JSONSchema{
+				"Version": {Type: "string", Enum: []any{"1", "2"}},
+			},
+		},
+	}
+
+	b, err := Marshal(doc, "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, `openapi: 3.1.0
+info:
+  title: api
+  version: "1"
+components:
+  schemas:
+    Version:
+      type: string
+      enum:
+        - "1"
+        - "2"
+`, string(b))
+
+	_, err = Marshal(doc, "xml")
+	assert.Error(t, err)
+}
+
+// decodeYAML decodes yaml into a value with json tags.
+func decodeYAML(b []byte, v any) error {
+	var data any
+	if err := yaml.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonBytes, v)
+}
+
+func keys[V any](m map[string]V) []string {
+	var result []string
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
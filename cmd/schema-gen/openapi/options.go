@@ -0,0 +1,48 @@
+package openapi
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+type options struct {
+	sourcePath      string
+	rootTypes       []string
+	outputFile      string
+	format          string
+	title           string
+	version         string
+	stripPrefix     []string
+	includeInternal bool
+	includeDeps     bool
+}
+
+func NewOptions() *options {
+	cfg := &options{
+		sourcePath:  ".",
+		outputFile:  "openapi.yaml",
+		version:     "0.0.0",
+		stripPrefix: []string{},
+	}
+
+	pflag.StringVarP(&cfg.sourcePath, "dir", "i", cfg.sourcePath, "Path to the directory that contains the root types")
+	pflag.StringSliceVarP(&cfg.rootTypes, "type", "t", cfg.rootTypes, "Root types to generate schemas for (required)")
+	pflag.StringVarP(&cfg.outputFile, "out", "o", cfg.outputFile, "Output file name, - for stdout")
+	pflag.StringVarP(&cfg.format, "format", "f", cfg.format, "Output format, yaml or json (default: from output file extension)")
+	pflag.StringVar(&cfg.title, "title", cfg.title, "Document title (default: package name)")
+	pflag.StringVar(&cfg.version, "version", cfg.version, "Document version")
+	pflag.StringSliceVarP(&cfg.stripPrefix, "strip-prefix", "s", cfg.stripPrefix, "List of package prefixes to strip from schema names")
+	pflag.BoolVarP(&cfg.includeInternal, "include-internal", "n", cfg.includeInternal, "include internal packages")
+	pflag.BoolVar(&cfg.includeDeps, "include-deps", cfg.includeDeps, "resolve imported types from dependencies outside the module")
+	pflag.Parse()
+
+	return cfg
+}
+
+// PrintHelp prints usage information for your CLI.
+func PrintHelp() {
+	fmt.Println("Usage: schema-gen openapi [options]")
+	fmt.Println()
+	pflag.PrintDefaults()
+}
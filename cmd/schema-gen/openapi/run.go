@@ -0,0 +1,18 @@
+package openapi
+
+import (
+	"os"
+	"slices"
+)
+
+// Run is the entrypoint for `schema-gen openapi`.
+func Run() (err error) {
+	cfg := NewOptions()
+
+	if slices.Contains(os.Args, "help") {
+		PrintHelp()
+		return nil
+	}
+
+	return Generate(cfg)
+}